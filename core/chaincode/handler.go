@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -25,7 +26,9 @@ import (
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
@@ -215,6 +218,12 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.HandleTransaction(msg, h.HandlePutCRDT)
 	case pb.ChaincodeMessage_GET_CRDT_STATE:
 		go h.HandleTransaction(msg, h.HandleGetCRDTState)
+	case pb.ChaincodeMessage_CRDT_SET_CONTAINS:
+		go h.HandleTransaction(msg, h.HandleCRDTSetContains)
+	case pb.ChaincodeMessage_GET_CRDT_STATE_BY_RANGE:
+		go h.HandleTransaction(msg, h.HandleGetCRDTStateByRange)
+	case pb.ChaincodeMessage_GET_CRDT_QUERY_RESULT:
+		go h.HandleTransaction(msg, h.HandleGetCRDTQueryResult)
 	default:
 		return fmt.Errorf("[%s] Fabric side handler cannot handle message (%s) while in ready state", msg.Txid, msg.Type)
 	}
@@ -666,7 +675,13 @@ func (h *Handler) HandleGetCRDTState(msg *pb.ChaincodeMessage, txContext *Transa
 	chaincodeLogger.Debugf("[%s] getting state for chaincode %s, key %s, channel %s", shorttxid(msg.Txid), namespaceID, getState.Key, txContext.ChannelID)
 
 	if isCollectionSet(collection) {
-		return nil, errors.New("CRDT is not implemented for private collections")
+		if txContext.IsInitTransaction {
+			return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+		}
+		if err := errorIfCreatorHasNoReadPermission(namespaceID, collection, txContext); err != nil {
+			return nil, err
+		}
+		res, err = txContext.TXSimulator.GetPrivateCRDTState(namespaceID, collection, getState.Key)
 	} else {
 		res, err = txContext.TXSimulator.GetCRDTState(namespaceID, getState.Key)
 	}
@@ -681,6 +696,129 @@ func (h *Handler) HandleGetCRDTState(msg *pb.ChaincodeMessage, txContext *Transa
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// Handles query to ledger to check whether an element is a member of a CRDT set
+func (h *Handler) HandleCRDTSetContains(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	setContains := &pb.CRDTSetContains{}
+	err := proto.Unmarshal(msg.Payload, setContains)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	namespaceID := txContext.NamespaceID
+	collection := setContains.Collection
+	chaincodeLogger.Debugf("[%s] checking set membership for chaincode %s, key %s, channel %s", shorttxid(msg.Txid), namespaceID, setContains.Key, txContext.ChannelID)
+
+	var value []byte
+	if isCollectionSet(collection) {
+		if txContext.IsInitTransaction {
+			return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+		}
+		if err := errorIfCreatorHasNoReadPermission(namespaceID, collection, txContext); err != nil {
+			return nil, err
+		}
+		value, err = txContext.TXSimulator.GetPrivateCRDTState(namespaceID, collection, setContains.Key)
+	} else {
+		value, err = txContext.TXSimulator.GetCRDTState(namespaceID, setContains.Key)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	contains, err := crdt_resolver.SetContains(value, setContains.Element)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: []byte(strconv.FormatBool(contains)), Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles range queries over the CRDT keys. As for GetCRDTState, the range is not added to the read-set
+func (h *Handler) HandleGetCRDTStateByRange(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	getStateByRange := &pb.GetStateByRange{}
+	err := proto.Unmarshal(msg.Payload, getStateByRange)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+	if isCollectionSet(getStateByRange.Collection) {
+		return nil, errors.New("range queries are not supported on the CRDT keys of private collections")
+	}
+
+	metadata, err := getQueryMetadataFromBytes(getStateByRange.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if isMetadataSetForPagination(metadata) {
+		return nil, errors.New("paginated queries are not supported on CRDT keys")
+	}
+
+	totalReturnLimit := h.calculateTotalReturnLimit(metadata)
+	iterID := h.UUIDGenerator.New()
+	namespaceID := txContext.NamespaceID
+	rangeIter, err := txContext.TXSimulator.GetCRDTStateRangeScanIterator(namespaceID, getStateByRange.StartKey, getStateByRange.EndKey)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	txContext.InitializeQueryContext(iterID, rangeIter)
+
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, rangeIter, iterID, false, totalReturnLimit)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.WithStack(err)
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	chaincodeLogger.Debugf("Got CRDT keys and values. Sending %s", pb.ChaincodeMessage_RESPONSE)
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles rich queries over the CRDT keys
+func (h *Handler) HandleGetCRDTQueryResult(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	getQueryResult := &pb.GetQueryResult{}
+	err := proto.Unmarshal(msg.Payload, getQueryResult)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+	if isCollectionSet(getQueryResult.Collection) {
+		return nil, errors.New("rich queries are not supported on the CRDT keys of private collections")
+	}
+
+	metadata, err := getQueryMetadataFromBytes(getQueryResult.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if isMetadataSetForPagination(metadata) {
+		return nil, errors.New("paginated queries are not supported on CRDT keys")
+	}
+
+	totalReturnLimit := h.calculateTotalReturnLimit(metadata)
+	iterID := h.UUIDGenerator.New()
+	namespaceID := txContext.NamespaceID
+	executeIter, err := txContext.TXSimulator.ExecuteCRDTQuery(namespaceID, getQueryResult.Query)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	txContext.InitializeQueryContext(iterID, executeIter)
+
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, executeIter, iterID, false, totalReturnLimit)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.WithStack(err)
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	chaincodeLogger.Debugf("Got CRDT keys and values. Sending %s", pb.ChaincodeMessage_RESPONSE)
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 func (h *Handler) HandleGetPrivateDataHash(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	getState := &pb.GetState{}
 	err := proto.Unmarshal(msg.Payload, getState)
@@ -1073,8 +1211,75 @@ func (h *Handler) HandlePutCRDT(msg *pb.ChaincodeMessage, txContext *Transaction
 	}
 
 	namespaceID := txContext.NamespaceID
+	collection := putCRDT.Collection
+	observed := func() ([]byte, error) {
+		return txContext.TXSimulator.GetCRDTState(namespaceID, putCRDT.Key)
+	}
+	if isCollectionSet(collection) {
+		if txContext.IsInitTransaction {
+			return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+		}
+		if err := errorIfCreatorHasNoWritePermission(namespaceID, collection, txContext); err != nil {
+			return nil, err
+		}
+		// the predicates are evaluated at commit, where the peers that do not hold the private data can't
+		if len(putCRDT.Predicates) != 0 {
+			return nil, errors.New("predicates are not supported on the CRDT keys of private collections")
+		}
+		observed = func() ([]byte, error) {
+			return txContext.TXSimulator.GetPrivateCRDTState(namespaceID, collection, putCRDT.Key)
+		}
+	}
+
+	switch putCRDT.Operation {
+	case kvrwset.CRDTPayload_MERGE:
+	case kvrwset.CRDTPayload_RESET, kvrwset.CRDTPayload_DELETE:
+		if isCollectionSet(collection) {
+			return nil, errors.Errorf("%s is not supported on the CRDT keys of private collections", putCRDT.Operation)
+		}
+		if len(putCRDT.Predicates) != 0 {
+			return nil, errors.Errorf("predicates are not supported on %s", putCRDT.Operation)
+		}
+	default:
+		return nil, errors.Errorf("unknown CRDT operation %d", putCRDT.Operation)
+	}
+
+	if putCRDT.Operation == kvrwset.CRDTPayload_DELETE {
+		if err := txContext.TXSimulator.DeleteCRDT(namespaceID, putCRDT.Key); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+	}
+	if putCRDT.Operation == kvrwset.CRDTPayload_RESET {
+		// the value replaces the value of the key, hence it is prepared against an empty value
+		observed = func() ([]byte, error) {
+			return []byte{}, nil
+		}
+	}
 
-	err = txContext.TXSimulator.SetCRDT(namespaceID, putCRDT.ResolutionType, putCRDT.Key, putCRDT.Value)
+	// the components of a counter are attributed to the MSP of the creator of the
+	// transaction, so a chaincode can't update the ones of other orgs, while the removes
+	// of an OR-Set and the writes of a Multi-Value Register only affect what they
+	// observed in the value of the key, including the earlier writes of the transaction
+	value, err := crdt_resolver.PrepareDiff(putCRDT.ResolutionType, putCRDT.Value, &crdt_resolver.DiffContext{
+		TxID: msg.Txid,
+		CreatorMSPID: func() (string, error) {
+			return creatorMSPID(txContext.Proposal)
+		},
+		Observed: observed,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch {
+	case isCollectionSet(collection):
+		err = txContext.TXSimulator.SetPrivateCRDT(namespaceID, collection, putCRDT.ResolutionType, putCRDT.Key, value)
+	case putCRDT.Operation == kvrwset.CRDTPayload_RESET:
+		err = txContext.TXSimulator.ResetCRDT(namespaceID, putCRDT.ResolutionType, putCRDT.Key, value)
+	default:
+		err = txContext.TXSimulator.SetCRDT(namespaceID, putCRDT.ResolutionType, putCRDT.Key, value, putCRDT.Predicates)
+	}
 
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -1083,6 +1288,26 @@ func (h *Handler) HandlePutCRDT(msg *pb.ChaincodeMessage, txContext *Transaction
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// creatorMSPID returns the MSP ID of the creator of the proposal
+func creatorMSPID(proposal *pb.Proposal) (string, error) {
+	if proposal == nil {
+		return "", errors.New("no proposal found in the transaction context")
+	}
+	header, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return "", err
+	}
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return "", err
+	}
+	creator, err := protoutil.UnmarshalSerializedIdentity(signatureHeader.Creator)
+	if err != nil {
+		return "", err
+	}
+	return creator.Mspid, nil
+}
+
 func (h *Handler) HandlePutStateMetadata(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	err := h.checkMetadataCap(msg)
 	if err != nil {
@@ -97,6 +97,7 @@ func (vc *ValidatorCommitter) ChaincodeInfo(channelName, chaincodeName string, q
 		Version:                     definedChaincode.EndorsementInfo.Version,
 		Hash:                        util.ComputeSHA256([]byte(chaincodeName + ":" + definedChaincode.EndorsementInfo.Version)),
 		ExplicitCollectionConfigPkg: definedChaincode.Collections,
+		CRDTSchema:                  definedChaincode.CRDTSchema,
 		IsLegacy:                    false,
 	}, nil
 }
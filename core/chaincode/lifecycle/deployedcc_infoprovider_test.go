@@ -110,6 +110,11 @@ var _ = Describe("ValidatorCommitter", func() {
 					},
 				},
 			},
+			CRDTSchema: &lb.CRDTSchema{
+				Keys: []*lb.CRDTKeySchema{
+					{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+				},
+			},
 		}
 		err := resources.Serializer.Serialize(lifecycle.NamespacesName, "cc-name", fakeChaincodeDef, fakePublicState)
 		Expect(err).NotTo(HaveOccurred())
@@ -179,6 +184,7 @@ var _ = Describe("ValidatorCommitter", func() {
 			Expect(res.Version).To(Equal("version"))
 			Expect(res.Hash).To(Equal(util.ComputeSHA256([]byte("cc-name:version"))))
 			Expect(len(res.ExplicitCollectionConfigPkg.Config)).To(Equal(1))
+			Expect(proto.Equal(res.CRDTSchema, fakeChaincodeDef.CRDTSchema)).To(BeTrue())
 		})
 
 		Context("when the requested chaincode is _lifecycle", func() {
@@ -207,6 +207,7 @@ var _ = Describe("Integration", func() {
 				ValidationPlugin:    "builtin",
 				ValidationParameter: []byte("validation-parameter"),
 				Collections:         &pb.CollectionConfigPackage{},
+				CrdtSchema:          &lb.CRDTSchema{},
 				Approvals: map[string]bool{
 					"fake-mspid": true,
 				},
@@ -84,6 +84,7 @@ var DefaultEndorsementPolicyBytes = protoutil.MarshalOrPanic(&pb.ApplicationPoli
 // namespaces/fields/mycc/EndorsementInfo:     {Version: "1.3", EndorsementPlugin: "builtin", InitRequired: true}
 // namespaces/fields/mycc/ValidationInfo:      {ValidationPlugin: "builtin", ValidationParameter: <application-policy>}
 // namespaces/fields/mycc/Collections          {<collection info>}
+// namespaces/fields/mycc/CRDTSchema           {<crdt key schema>}
 //
 // Private/Org Scope Implcit Collection layout looks like the following
 // namespaces/metadata/<namespace>#<sequence_number> -> namespace metadata, including type
@@ -93,10 +94,12 @@ var DefaultEndorsementPolicyBytes = protoutil.MarshalOrPanic(&pb.ApplicationPoli
 // namespaces/fields/mycc#1/EndorsementInfo:     {Version: "1.3", EndorsementPlugin: "builtin", InitRequired: true}
 // namespaces/fields/mycc#1/ValidationInfo:      {ValidationPlugin: "builtin", ValidationParameter: <application-policy>}
 // namespaces/fields/mycc#1/Collections          {<collection info>}
+// namespaces/fields/mycc#1/CRDTSchema           {<crdt key schema>}
 // namespaces/metadata/mycc#2:                   "ChaincodeParameters"
 // namespaces/fields/mycc#2/EndorsementInfo:     {Version: "1.4", EndorsementPlugin: "builtin", InitRequired: true}
 // namespaces/fields/mycc#2/ValidationInfo:      {ValidationPlugin: "builtin", ValidationParameter: <application-policy>}
 // namespaces/fields/mycc#2/Collections          {<collection info>}
+// namespaces/fields/mycc#2/CRDTSchema           {<crdt key schema>}
 //
 // chaincode-sources/metadata/mycc#1              "ChaincodeLocalPackage"
 // chaincode-sources/fields/mycc#1/PackageID      "hash1"
@@ -117,6 +120,7 @@ type ChaincodeParameters struct {
 	EndorsementInfo *lb.ChaincodeEndorsementInfo
 	ValidationInfo  *lb.ChaincodeValidationInfo
 	Collections     *pb.CollectionConfigPackage
+	CRDTSchema      *lb.CRDTSchema
 }
 
 func (cp *ChaincodeParameters) Equal(ocp *ChaincodeParameters) error {
@@ -133,6 +137,8 @@ func (cp *ChaincodeParameters) Equal(ocp *ChaincodeParameters) error {
 		return errors.Errorf("expected ValidationParameter '%x' does not match passed ValidationParameter '%x'", cp.ValidationInfo.ValidationParameter, ocp.ValidationInfo.ValidationParameter)
 	case !proto.Equal(cp.Collections, ocp.Collections):
 		return errors.Errorf("Collections do not match")
+	case !proto.Equal(cp.CRDTSchema, ocp.CRDTSchema):
+		return errors.Errorf("CRDTSchema does not match")
 	default:
 	}
 	return nil
@@ -148,6 +154,7 @@ type ChaincodeDefinition struct {
 	EndorsementInfo *lb.ChaincodeEndorsementInfo
 	ValidationInfo  *lb.ChaincodeValidationInfo
 	Collections     *pb.CollectionConfigPackage
+	CRDTSchema      *lb.CRDTSchema
 }
 
 type ApprovedChaincodeDefinition struct {
@@ -155,6 +162,7 @@ type ApprovedChaincodeDefinition struct {
 	EndorsementInfo *lb.ChaincodeEndorsementInfo
 	ValidationInfo  *lb.ChaincodeValidationInfo
 	Collections     *pb.CollectionConfigPackage
+	CRDTSchema      *lb.CRDTSchema
 	Source          *lb.ChaincodeSource
 }
 
@@ -164,6 +172,7 @@ func (cd *ChaincodeDefinition) Parameters() *ChaincodeParameters {
 		EndorsementInfo: cd.EndorsementInfo,
 		ValidationInfo:  cd.ValidationInfo,
 		Collections:     cd.Collections,
+		CRDTSchema:      cd.CRDTSchema,
 	}
 }
 
@@ -185,11 +194,12 @@ func (cd *ChaincodeDefinition) String() string {
 		)
 	}
 
-	return fmt.Sprintf("sequence: %d, %s, %s, collections: (%+v)",
+	return fmt.Sprintf("sequence: %d, %s, %s, collections: (%+v), crdt schema: (%+v)",
 		cd.Sequence,
 		endorsementInfo,
 		validationInfo,
 		cd.Collections,
+		cd.CRDTSchema,
 	)
 }
 
@@ -603,6 +613,7 @@ func (ef *ExternalFunctions) QueryApprovedChaincodeDefinition(chname, ccname str
 		EndorsementInfo: ccParameters.EndorsementInfo,
 		ValidationInfo:  ccParameters.ValidationInfo,
 		Collections:     ccParameters.Collections,
+		CRDTSchema:      ccParameters.CRDTSchema,
 		Source:          ccsrc,
 	}, nil
 }
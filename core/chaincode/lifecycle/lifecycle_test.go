@@ -172,7 +172,7 @@ var _ = Describe("Resources", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(exists).To(BeTrue())
 			Expect(definition.EndorsementInfo.Version).To(Equal("version"))
-			Expect(fmt.Sprintf("{%s}", definition)).To(Equal("{sequence: 5, endorsement info: (version: 'version', plugin: 'my endorsement plugin', init required: false), validation info: (plugin: 'my validation plugin', policy: '736f6d6520617765736f6d6520706f6c696379'), collections: ()}"))
+			Expect(fmt.Sprintf("{%s}", definition)).To(Equal("{sequence: 5, endorsement info: (version: 'version', plugin: 'my endorsement plugin', init required: false), validation info: (plugin: 'my validation plugin', policy: '736f6d6520617765736f6d6520706f6c696379'), collections: (), crdt schema: ()}"))
 		})
 
 		Context("when the requested chaincode is _lifecycle", func() {
@@ -636,6 +636,7 @@ var _ = Describe("ExternalFunctions", func() {
 					ValidationParameter: []byte("some awesome policy"),
 				},
 				Collections: &pb.CollectionConfigPackage{},
+				CRDTSchema:  &lb.CRDTSchema{},
 			}
 
 			fakePublicState = &mock.ReadWritableState{}
@@ -930,7 +931,7 @@ var _ = Describe("ExternalFunctions", func() {
 
 		Context("when writing to the org state fails for the package", func() {
 			BeforeEach(func() {
-				fakeOrgState.PutStateReturnsOnCall(4, fmt.Errorf("put-state-error"))
+				fakeOrgState.PutStateReturnsOnCall(5, fmt.Errorf("put-state-error"))
 			})
 
 			It("wraps and returns the error", func() {
@@ -8,6 +8,64 @@ import (
 )
 
 type SimpleQueryExecutor struct {
+	ExecuteCRDTQueryStub        func(string, string) (ledger.ResultsIterator, error)
+	executeCRDTQueryMutex       sync.RWMutex
+	executeCRDTQueryArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	executeCRDTQueryReturns struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	executeCRDTQueryReturnsOnCall map[int]struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	GetCRDTStateStub        func(string, string) ([]byte, error)
+	getCRDTStateMutex       sync.RWMutex
+	getCRDTStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetCRDTStateRangeScanIteratorStub        func(string, string, string) (ledger.ResultsIterator, error)
+	getCRDTStateRangeScanIteratorMutex       sync.RWMutex
+	getCRDTStateRangeScanIteratorArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getCRDTStateRangeScanIteratorReturns struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	getCRDTStateRangeScanIteratorReturnsOnCall map[int]struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	GetPrivateCRDTStateStub        func(string, string, string) ([]byte, error)
+	getPrivateCRDTStateMutex       sync.RWMutex
+	getPrivateCRDTStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getPrivateCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetPrivateDataHashStub        func(string, string, string) ([]byte, error)
 	getPrivateDataHashMutex       sync.RWMutex
 	getPrivateDataHashArgsForCall []struct {
@@ -56,6 +114,264 @@ type SimpleQueryExecutor struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *SimpleQueryExecutor) ExecuteCRDTQuery(arg1 string, arg2 string) (ledger.ResultsIterator, error) {
+	fake.executeCRDTQueryMutex.Lock()
+	ret, specificReturn := fake.executeCRDTQueryReturnsOnCall[len(fake.executeCRDTQueryArgsForCall)]
+	fake.executeCRDTQueryArgsForCall = append(fake.executeCRDTQueryArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ExecuteCRDTQuery", []interface{}{arg1, arg2})
+	fake.executeCRDTQueryMutex.Unlock()
+	if fake.ExecuteCRDTQueryStub != nil {
+		return fake.ExecuteCRDTQueryStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.executeCRDTQueryReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SimpleQueryExecutor) ExecuteCRDTQueryCallCount() int {
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	return len(fake.executeCRDTQueryArgsForCall)
+}
+
+func (fake *SimpleQueryExecutor) ExecuteCRDTQueryCalls(stub func(string, string) (ledger.ResultsIterator, error)) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = stub
+}
+
+func (fake *SimpleQueryExecutor) ExecuteCRDTQueryArgsForCall(i int) (string, string) {
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	argsForCall := fake.executeCRDTQueryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SimpleQueryExecutor) ExecuteCRDTQueryReturns(result1 ledger.ResultsIterator, result2 error) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = nil
+	fake.executeCRDTQueryReturns = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) ExecuteCRDTQueryReturnsOnCall(i int, result1 ledger.ResultsIterator, result2 error) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = nil
+	if fake.executeCRDTQueryReturnsOnCall == nil {
+		fake.executeCRDTQueryReturnsOnCall = make(map[int]struct {
+			result1 ledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.executeCRDTQueryReturnsOnCall[i] = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTState(arg1 string, arg2 string) ([]byte, error) {
+	fake.getCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateReturnsOnCall[len(fake.getCRDTStateArgsForCall)]
+	fake.getCRDTStateArgsForCall = append(fake.getCRDTStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetCRDTState", []interface{}{arg1, arg2})
+	fake.getCRDTStateMutex.Unlock()
+	if fake.GetCRDTStateStub != nil {
+		return fake.GetCRDTStateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCRDTStateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateCallCount() int {
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	return len(fake.getCRDTStateArgsForCall)
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateCalls(stub func(string, string) ([]byte, error)) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = stub
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateArgsForCall(i int) (string, string) {
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	argsForCall := fake.getCRDTStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = nil
+	fake.getCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = nil
+	if fake.getCRDTStateReturnsOnCall == nil {
+		fake.getCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateRangeScanIterator(arg1 string, arg2 string, arg3 string) (ledger.ResultsIterator, error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateRangeScanIteratorReturnsOnCall[len(fake.getCRDTStateRangeScanIteratorArgsForCall)]
+	fake.getCRDTStateRangeScanIteratorArgsForCall = append(fake.getCRDTStateRangeScanIteratorArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetCRDTStateRangeScanIterator", []interface{}{arg1, arg2, arg3})
+	fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	if fake.GetCRDTStateRangeScanIteratorStub != nil {
+		return fake.GetCRDTStateRangeScanIteratorStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCRDTStateRangeScanIteratorReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateRangeScanIteratorCallCount() int {
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	return len(fake.getCRDTStateRangeScanIteratorArgsForCall)
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateRangeScanIteratorCalls(stub func(string, string, string) (ledger.ResultsIterator, error)) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = stub
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateRangeScanIteratorArgsForCall(i int) (string, string, string) {
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	argsForCall := fake.getCRDTStateRangeScanIteratorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateRangeScanIteratorReturns(result1 ledger.ResultsIterator, result2 error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = nil
+	fake.getCRDTStateRangeScanIteratorReturns = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) GetCRDTStateRangeScanIteratorReturnsOnCall(i int, result1 ledger.ResultsIterator, result2 error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = nil
+	if fake.getCRDTStateRangeScanIteratorReturnsOnCall == nil {
+		fake.getCRDTStateRangeScanIteratorReturnsOnCall = make(map[int]struct {
+			result1 ledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.getCRDTStateRangeScanIteratorReturnsOnCall[i] = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) GetPrivateCRDTState(arg1 string, arg2 string, arg3 string) ([]byte, error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getPrivateCRDTStateReturnsOnCall[len(fake.getPrivateCRDTStateArgsForCall)]
+	fake.getPrivateCRDTStateArgsForCall = append(fake.getPrivateCRDTStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetPrivateCRDTState", []interface{}{arg1, arg2, arg3})
+	fake.getPrivateCRDTStateMutex.Unlock()
+	if fake.GetPrivateCRDTStateStub != nil {
+		return fake.GetPrivateCRDTStateStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPrivateCRDTStateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SimpleQueryExecutor) GetPrivateCRDTStateCallCount() int {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	return len(fake.getPrivateCRDTStateArgsForCall)
+}
+
+func (fake *SimpleQueryExecutor) GetPrivateCRDTStateCalls(stub func(string, string, string) ([]byte, error)) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = stub
+}
+
+func (fake *SimpleQueryExecutor) GetPrivateCRDTStateArgsForCall(i int) (string, string, string) {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	argsForCall := fake.getPrivateCRDTStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *SimpleQueryExecutor) GetPrivateCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	fake.getPrivateCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SimpleQueryExecutor) GetPrivateCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	if fake.getPrivateCRDTStateReturnsOnCall == nil {
+		fake.getPrivateCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SimpleQueryExecutor) GetPrivateDataHash(arg1 string, arg2 string, arg3 string) ([]byte, error) {
 	fake.getPrivateDataHashMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataHashReturnsOnCall[len(fake.getPrivateDataHashArgsForCall)]
@@ -253,6 +569,14 @@ func (fake *SimpleQueryExecutor) GetStateRangeScanIteratorReturnsOnCall(i int, r
 func (fake *SimpleQueryExecutor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
 	fake.getPrivateDataHashMutex.RLock()
 	defer fake.getPrivateDataHashMutex.RUnlock()
 	fake.getStateMutex.RLock()
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/dispatcher"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/msp"
 
 	"github.com/golang/protobuf/proto"
@@ -364,11 +365,18 @@ func (i *Invocation) ApproveChaincodeDefinitionForMyOrg(input *lb.ApproveChainco
 	if err := i.validateInput(input.Name, input.Version, input.Collections); err != nil {
 		return nil, errors.WithMessage(err, "error validating chaincode definition")
 	}
+	if err := validateCRDTSchema(input.CrdtSchema); err != nil {
+		return nil, errors.WithMessage(err, "error validating chaincode definition")
+	}
 	collectionName := implicitcollection.NameForOrg(i.SCC.OrgMSPID)
 	var collectionConfig []*pb.CollectionConfig
 	if input.Collections != nil {
 		collectionConfig = input.Collections.Config
 	}
+	var crdtKeySchemas []*lb.CRDTKeySchema
+	if input.CrdtSchema != nil {
+		crdtKeySchemas = input.CrdtSchema.Keys
+	}
 
 	var packageID string
 	if input.Source != nil {
@@ -394,6 +402,9 @@ func (i *Invocation) ApproveChaincodeDefinitionForMyOrg(input *lb.ApproveChainco
 		Collections: &pb.CollectionConfigPackage{
 			Config: collectionConfig,
 		},
+		CRDTSchema: &lb.CRDTSchema{
+			Keys: crdtKeySchemas,
+		},
 	}
 
 	logger.Debugf("received invocation of ApproveChaincodeDefinitionForMyOrg on channel '%s' for definition '%s'",
@@ -448,6 +459,7 @@ func (i *Invocation) QueryApprovedChaincodeDefinition(input *lb.QueryApprovedCha
 		ValidationParameter: ca.ValidationInfo.ValidationParameter,
 		InitRequired:        ca.EndorsementInfo.InitRequired,
 		Collections:         ca.Collections,
+		CrdtSchema:          ca.CRDTSchema,
 		Source:              ca.Source,
 	}, nil
 }
@@ -472,6 +484,7 @@ func (i *Invocation) CheckCommitReadiness(input *lb.CheckCommitReadinessArgs) (p
 			ValidationParameter: input.ValidationParameter,
 		},
 		Collections: input.Collections,
+		CRDTSchema:  input.CrdtSchema,
 	}
 
 	logger.Debugf("received invocation of CheckCommitReadiness on channel '%s' for definition '%s'",
@@ -501,6 +514,9 @@ func (i *Invocation) CommitChaincodeDefinition(input *lb.CommitChaincodeDefiniti
 	if err := i.validateInput(input.Name, input.Version, input.Collections); err != nil {
 		return nil, errors.WithMessage(err, "error validating chaincode definition")
 	}
+	if err := validateCRDTSchema(input.CrdtSchema); err != nil {
+		return nil, errors.WithMessage(err, "error validating chaincode definition")
+	}
 
 	if i.ApplicationConfig == nil {
 		return nil, errors.Errorf("no application config for channel '%s'", i.Stub.GetChannelID())
@@ -535,6 +551,7 @@ func (i *Invocation) CommitChaincodeDefinition(input *lb.CommitChaincodeDefiniti
 			ValidationParameter: input.ValidationParameter,
 		},
 		Collections: input.Collections,
+		CRDTSchema:  input.CrdtSchema,
 	}
 
 	logger.Debugf("received invocation of CommitChaincodeDefinition on channel '%s' for definition '%s'",
@@ -593,6 +610,7 @@ func (i *Invocation) QueryChaincodeDefinition(input *lb.QueryChaincodeDefinition
 		ValidationParameter: definedChaincode.ValidationInfo.ValidationParameter,
 		InitRequired:        definedChaincode.EndorsementInfo.InitRequired,
 		Collections:         definedChaincode.Collections,
+		CrdtSchema:          definedChaincode.CRDTSchema,
 		Approvals:           approvals,
 	}, nil
 }
@@ -626,6 +644,7 @@ func (i *Invocation) QueryChaincodeDefinitions(input *lb.QueryChaincodeDefinitio
 				ValidationParameter: definedChaincode.ValidationInfo.ValidationParameter,
 				InitRequired:        definedChaincode.EndorsementInfo.InitRequired,
 				Collections:         definedChaincode.Collections,
+				CrdtSchema:          definedChaincode.CRDTSchema,
 			})
 		}
 	}
@@ -849,6 +868,13 @@ func validateSpOrConcat(sp *common.SignaturePolicy) error {
 	return nil
 }
 
+// validateCRDTSchema checks that the key patterns of the supplied CRDT schema are well formed.
+// The resolution types are not checked against the resolvers of this peer, as the resolvers
+// are loaded locally and unknown types are rejected when the CRDT payload is merged
+func validateCRDTSchema(schema *lb.CRDTSchema) error {
+	return crdt_resolver.ValidateSchema(schema)
+}
+
 func validateCollConfigsAgainstCommittedDef(
 	proposedCollConfs []*pb.StaticCollectionConfig,
 	committedCollConfPkg *pb.CollectionConfigPackage,
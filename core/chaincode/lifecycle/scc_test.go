@@ -461,6 +461,11 @@ var _ = Describe("SCC", func() {
 					ValidationPlugin:    "validation-plugin",
 					ValidationParameter: []byte("validation-parameter"),
 					InitRequired:        true,
+					CrdtSchema: &lb.CRDTSchema{
+						Keys: []*lb.CRDTKeySchema{
+							{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+						},
+					},
 					Source: &lb.ChaincodeSource{
 						Type: &lb.ChaincodeSource_LocalPackage{
 							LocalPackage: &lb.ChaincodeSource_Local{
@@ -503,6 +508,11 @@ var _ = Describe("SCC", func() {
 					cd.Collections,
 					collConfigs.toProtoCollectionConfigPackage(),
 				)).Should(BeTrue())
+				Expect(proto.Equal(cd.CRDTSchema, &lb.CRDTSchema{
+					Keys: []*lb.CRDTKeySchema{
+						{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+					},
+				})).To(BeTrue())
 
 				Expect(packageID).To(Equal("hash"))
 				Expect(pubState).To(Equal(fakeStub))
@@ -558,6 +568,31 @@ var _ = Describe("SCC", func() {
 				})
 			})
 
+			Context("when the CRDT schema is not provided", func() {
+				BeforeEach(func() {
+					arg.CrdtSchema = nil
+				})
+
+				It("approves an empty CRDT schema", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+					_, _, cd, _, _, _ := fakeSCCFuncs.ApproveChaincodeDefinitionForOrgArgsForCall(0)
+					Expect(proto.Equal(cd.CRDTSchema, &lb.CRDTSchema{})).To(BeTrue())
+				})
+			})
+
+			Context("when a CRDT key pattern is invalid", func() {
+				BeforeEach(func() {
+					arg.CrdtSchema.Keys[0].KeyPattern = "coun*ter"
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: CRDT key pattern coun*ter is invalid, '*' is only allowed at the end of the pattern"))
+				})
+			})
+
 			Context("when a collection name begins with an invalid character", func() {
 				BeforeEach(func() {
 					collConfigs[0].Name = "_collection"
@@ -932,6 +967,11 @@ var _ = Describe("SCC", func() {
 							},
 						},
 					},
+					CrdtSchema: &lb.CRDTSchema{
+						Keys: []*lb.CRDTKeySchema{
+							{KeyPattern: "balance_*", ResolutionTypes: []string{"IntAdd", "UintSub"}},
+						},
+					},
 					InitRequired: true,
 				}
 
@@ -1003,6 +1043,11 @@ var _ = Describe("SCC", func() {
 							},
 						},
 					},
+					CRDTSchema: &lb.CRDTSchema{
+						Keys: []*lb.CRDTKeySchema{
+							{KeyPattern: "balance_*", ResolutionTypes: []string{"IntAdd", "UintSub"}},
+						},
+					},
 				}))
 				Expect(pubState).To(Equal(fakeStub))
 				Expect(len(orgStates)).To(Equal(2))
@@ -1013,6 +1058,22 @@ var _ = Describe("SCC", func() {
 				Expect([]string{collection0, collection1}).To(ConsistOf("_implicit_org_fake-mspid", "_implicit_org_other-mspid"))
 			})
 
+			Context("when a CRDT key pattern has no resolution types", func() {
+				BeforeEach(func() {
+					arg.CrdtSchema.Keys[0].ResolutionTypes = nil
+
+					marshaledArg, err = proto.Marshal(arg)
+					Expect(err).NotTo(HaveOccurred())
+					fakeStub.GetArgsReturns([][]byte{[]byte("CommitChaincodeDefinition"), marshaledArg})
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'CommitChaincodeDefinition': error validating chaincode definition: CRDT key pattern balance_* has no resolution types"))
+				})
+			})
+
 			Context("when the chaincode name begins with an invalid character", func() {
 				BeforeEach(func() {
 					arg.Name = "_invalid"
@@ -1446,6 +1507,11 @@ var _ = Describe("SCC", func() {
 							ValidationParameter: []byte("validation-parameter"),
 						},
 						Collections: &pb.CollectionConfigPackage{},
+						CRDTSchema: &lb.CRDTSchema{
+							Keys: []*lb.CRDTKeySchema{
+								{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+							},
+						},
 					},
 					nil,
 				)
@@ -1472,6 +1538,11 @@ var _ = Describe("SCC", func() {
 					ValidationPlugin:    "validation-plugin",
 					ValidationParameter: []byte("validation-parameter"),
 					Collections:         &pb.CollectionConfigPackage{},
+					CrdtSchema: &lb.CRDTSchema{
+						Keys: []*lb.CRDTKeySchema{
+							{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+						},
+					},
 					Approvals: map[string]bool{
 						"fake-mspid":  true,
 						"other-mspid": true,
@@ -1484,6 +1484,12 @@ func (m *mockLedger) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFil
 	return args.Get(0).([]*ledger.TxPvtData), nil
 }
 
+// GetCRDTValuesByNum retrieves the CRDT values
+func (m *mockLedger) GetCRDTValuesByNum(blockNum uint64) (map[uint64]*peer.TxCRDTValues, error) {
+	args := m.Called()
+	return args.Get(0).(map[uint64]*peer.TxCRDTValues), nil
+}
+
 // CommitLegacy commits the block and the corresponding pvt data in an atomic operation
 func (m *mockLedger) CommitLegacy(pvtDataAndBlock *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
 	return nil
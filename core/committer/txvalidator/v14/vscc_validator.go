@@ -374,6 +374,11 @@ func (v *VsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 		return true
 	}
 
+	// CRDT payloads update the keys they are merged into
+	if ns.KvRwSet != nil && len(ns.KvRwSet.CrdtPayload) > 0 {
+		return true
+	}
+
 	// only look at collection data if we support that capability
 	if v.cr.Capabilities().PrivateChannelData() {
 		// check for private writes for all collections
@@ -382,6 +387,11 @@ func (v *VsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 				return true
 			}
 
+			// private CRDT payloads
+			if c.HashedRwSet != nil && len(c.HashedRwSet.CrdtPayloadHashes) > 0 {
+				return true
+			}
+
 			// only look at private metadata writes if we support that capability
 			if v.cr.Capabilities().KeyLevelEndorsement() {
 				// private metadata updates
@@ -282,6 +282,11 @@ func (v *dispatcherImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 		return true
 	}
 
+	// CRDT payloads update the keys they are merged into
+	if ns.KvRwSet != nil && len(ns.KvRwSet.CrdtPayload) > 0 {
+		return true
+	}
+
 	// check for private writes for all collections
 	for _, c := range ns.CollHashedRwSets {
 		if c.HashedRwSet != nil && len(c.HashedRwSet.HashedWrites) > 0 {
@@ -292,6 +297,11 @@ func (v *dispatcherImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 		if c.HashedRwSet != nil && len(c.HashedRwSet.MetadataWrites) > 0 {
 			return true
 		}
+
+		// private CRDT payloads
+		if c.HashedRwSet != nil && len(c.HashedRwSet.CrdtPayloadHashes) > 0 {
+			return true
+		}
 	}
 
 	if ns.KvRwSet != nil && len(ns.KvRwSet.MetadataWrites) > 0 {
@@ -13,6 +13,20 @@ type QueryExecutor struct {
 	doneMutex       sync.RWMutex
 	doneArgsForCall []struct {
 	}
+	ExecuteCRDTQueryStub        func(string, string) (ledger.ResultsIterator, error)
+	executeCRDTQueryMutex       sync.RWMutex
+	executeCRDTQueryArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	executeCRDTQueryReturns struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	executeCRDTQueryReturnsOnCall map[int]struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
 	ExecuteQueryStub        func(string, string) (ledger.ResultsIterator, error)
 	executeQueryMutex       sync.RWMutex
 	executeQueryArgsForCall []struct {
@@ -58,6 +72,50 @@ type QueryExecutor struct {
 		result1 ledgera.QueryResultsIterator
 		result2 error
 	}
+	GetCRDTStateStub        func(string, string) ([]byte, error)
+	getCRDTStateMutex       sync.RWMutex
+	getCRDTStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetCRDTStateRangeScanIteratorStub        func(string, string, string) (ledger.ResultsIterator, error)
+	getCRDTStateRangeScanIteratorMutex       sync.RWMutex
+	getCRDTStateRangeScanIteratorArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getCRDTStateRangeScanIteratorReturns struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	getCRDTStateRangeScanIteratorReturnsOnCall map[int]struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	GetPrivateCRDTStateStub        func(string, string, string) ([]byte, error)
+	getPrivateCRDTStateMutex       sync.RWMutex
+	getPrivateCRDTStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getPrivateCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetPrivateDataStub        func(string, string, string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -249,6 +307,70 @@ func (fake *QueryExecutor) DoneCalls(stub func()) {
 	fake.DoneStub = stub
 }
 
+func (fake *QueryExecutor) ExecuteCRDTQuery(arg1 string, arg2 string) (ledger.ResultsIterator, error) {
+	fake.executeCRDTQueryMutex.Lock()
+	ret, specificReturn := fake.executeCRDTQueryReturnsOnCall[len(fake.executeCRDTQueryArgsForCall)]
+	fake.executeCRDTQueryArgsForCall = append(fake.executeCRDTQueryArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ExecuteCRDTQuery", []interface{}{arg1, arg2})
+	fake.executeCRDTQueryMutex.Unlock()
+	if fake.ExecuteCRDTQueryStub != nil {
+		return fake.ExecuteCRDTQueryStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.executeCRDTQueryReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QueryExecutor) ExecuteCRDTQueryCallCount() int {
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	return len(fake.executeCRDTQueryArgsForCall)
+}
+
+func (fake *QueryExecutor) ExecuteCRDTQueryCalls(stub func(string, string) (ledger.ResultsIterator, error)) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = stub
+}
+
+func (fake *QueryExecutor) ExecuteCRDTQueryArgsForCall(i int) (string, string) {
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	argsForCall := fake.executeCRDTQueryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *QueryExecutor) ExecuteCRDTQueryReturns(result1 ledger.ResultsIterator, result2 error) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = nil
+	fake.executeCRDTQueryReturns = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) ExecuteCRDTQueryReturnsOnCall(i int, result1 ledger.ResultsIterator, result2 error) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = nil
+	if fake.executeCRDTQueryReturnsOnCall == nil {
+		fake.executeCRDTQueryReturnsOnCall = make(map[int]struct {
+			result1 ledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.executeCRDTQueryReturnsOnCall[i] = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *QueryExecutor) ExecuteQuery(arg1 string, arg2 string) (ledger.ResultsIterator, error) {
 	fake.executeQueryMutex.Lock()
 	ret, specificReturn := fake.executeQueryReturnsOnCall[len(fake.executeQueryArgsForCall)]
@@ -444,6 +566,200 @@ func (fake *QueryExecutor) ExecuteQueryWithPaginationReturnsOnCall(i int, result
 	}{result1, result2}
 }
 
+func (fake *QueryExecutor) GetCRDTState(arg1 string, arg2 string) ([]byte, error) {
+	fake.getCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateReturnsOnCall[len(fake.getCRDTStateArgsForCall)]
+	fake.getCRDTStateArgsForCall = append(fake.getCRDTStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetCRDTState", []interface{}{arg1, arg2})
+	fake.getCRDTStateMutex.Unlock()
+	if fake.GetCRDTStateStub != nil {
+		return fake.GetCRDTStateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCRDTStateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QueryExecutor) GetCRDTStateCallCount() int {
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	return len(fake.getCRDTStateArgsForCall)
+}
+
+func (fake *QueryExecutor) GetCRDTStateCalls(stub func(string, string) ([]byte, error)) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = stub
+}
+
+func (fake *QueryExecutor) GetCRDTStateArgsForCall(i int) (string, string) {
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	argsForCall := fake.getCRDTStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *QueryExecutor) GetCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = nil
+	fake.getCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) GetCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = nil
+	if fake.getCRDTStateReturnsOnCall == nil {
+		fake.getCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) GetCRDTStateRangeScanIterator(arg1 string, arg2 string, arg3 string) (ledger.ResultsIterator, error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateRangeScanIteratorReturnsOnCall[len(fake.getCRDTStateRangeScanIteratorArgsForCall)]
+	fake.getCRDTStateRangeScanIteratorArgsForCall = append(fake.getCRDTStateRangeScanIteratorArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetCRDTStateRangeScanIterator", []interface{}{arg1, arg2, arg3})
+	fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	if fake.GetCRDTStateRangeScanIteratorStub != nil {
+		return fake.GetCRDTStateRangeScanIteratorStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCRDTStateRangeScanIteratorReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QueryExecutor) GetCRDTStateRangeScanIteratorCallCount() int {
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	return len(fake.getCRDTStateRangeScanIteratorArgsForCall)
+}
+
+func (fake *QueryExecutor) GetCRDTStateRangeScanIteratorCalls(stub func(string, string, string) (ledger.ResultsIterator, error)) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = stub
+}
+
+func (fake *QueryExecutor) GetCRDTStateRangeScanIteratorArgsForCall(i int) (string, string, string) {
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	argsForCall := fake.getCRDTStateRangeScanIteratorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *QueryExecutor) GetCRDTStateRangeScanIteratorReturns(result1 ledger.ResultsIterator, result2 error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = nil
+	fake.getCRDTStateRangeScanIteratorReturns = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) GetCRDTStateRangeScanIteratorReturnsOnCall(i int, result1 ledger.ResultsIterator, result2 error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = nil
+	if fake.getCRDTStateRangeScanIteratorReturnsOnCall == nil {
+		fake.getCRDTStateRangeScanIteratorReturnsOnCall = make(map[int]struct {
+			result1 ledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.getCRDTStateRangeScanIteratorReturnsOnCall[i] = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) GetPrivateCRDTState(arg1 string, arg2 string, arg3 string) ([]byte, error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getPrivateCRDTStateReturnsOnCall[len(fake.getPrivateCRDTStateArgsForCall)]
+	fake.getPrivateCRDTStateArgsForCall = append(fake.getPrivateCRDTStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetPrivateCRDTState", []interface{}{arg1, arg2, arg3})
+	fake.getPrivateCRDTStateMutex.Unlock()
+	if fake.GetPrivateCRDTStateStub != nil {
+		return fake.GetPrivateCRDTStateStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPrivateCRDTStateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QueryExecutor) GetPrivateCRDTStateCallCount() int {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	return len(fake.getPrivateCRDTStateArgsForCall)
+}
+
+func (fake *QueryExecutor) GetPrivateCRDTStateCalls(stub func(string, string, string) ([]byte, error)) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = stub
+}
+
+func (fake *QueryExecutor) GetPrivateCRDTStateArgsForCall(i int) (string, string, string) {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	argsForCall := fake.getPrivateCRDTStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *QueryExecutor) GetPrivateCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	fake.getPrivateCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) GetPrivateCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	if fake.getPrivateCRDTStateReturnsOnCall == nil {
+		fake.getPrivateCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *QueryExecutor) GetPrivateData(arg1 string, arg2 string, arg3 string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -1178,12 +1494,20 @@ func (fake *QueryExecutor) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.doneMutex.RLock()
 	defer fake.doneMutex.RUnlock()
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
 	fake.executeQueryMutex.RLock()
 	defer fake.executeQueryMutex.RUnlock()
 	fake.executeQueryOnPrivateDataMutex.RLock()
 	defer fake.executeQueryOnPrivateDataMutex.RUnlock()
 	fake.executeQueryWithPaginationMutex.RLock()
 	defer fake.executeQueryWithPaginationMutex.RUnlock()
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.getPrivateDataHashMutex.RLock()
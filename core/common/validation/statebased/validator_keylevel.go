@@ -110,6 +110,22 @@ func (p *baseEvaluator) Evaluate(blockNum, txNum uint64, NsRwSets []*rwsetutil.N
 				return err
 			}
 		}
+		// public CRDT payloads
+		// a CRDT payload updates the value of the key like a write, hence we validate
+		// it against key-level validation parameters if any are present or the
+		// chaincode-wide endorsement policy. A key is checked once even if several
+		// payloads are merged into it
+		crdtKeys := map[string]struct{}{}
+		for _, crdtPayload := range nsRWSet.KvRwSet.CrdtPayload {
+			if _, ok := crdtKeys[crdtPayload.Key]; ok {
+				continue
+			}
+			crdtKeys[crdtPayload.Key] = struct{}{}
+			err := p.checkSBAndCCEP(ns, "", crdtPayload.Key, blockNum, txNum, sd)
+			if err != nil {
+				return err
+			}
+		}
 		// writes in collections
 		// we validate writes against key-level validation parameters
 		// if any are present or the chaincode-wide endorsement policy
@@ -123,6 +139,23 @@ func (p *baseEvaluator) Evaluate(blockNum, txNum uint64, NsRwSets []*rwsetutil.N
 				}
 			}
 		}
+		// CRDT payloads in collections
+		// we validate them as the public CRDT payloads
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			coll := collRWSet.CollectionName
+			crdtKeys := map[string]struct{}{}
+			for _, crdtPayloadHash := range collRWSet.HashedRwSet.CrdtPayloadHashes {
+				key := string(crdtPayloadHash.KeyHash)
+				if _, ok := crdtKeys[key]; ok {
+					continue
+				}
+				crdtKeys[key] = struct{}{}
+				err := p.checkSBAndCCEP(ns, coll, key, blockNum, txNum, sd)
+				if err != nil {
+					return err
+				}
+			}
+		}
 		// metadata writes in collections
 		// we validate writes against key-level validation parameters
 		// if any are present or the chaincode-wide endorsement policy
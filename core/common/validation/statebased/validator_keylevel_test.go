@@ -234,6 +234,102 @@ func TestKeylevelValidationPvtMetaUpdate(t *testing.T) {
 	require.IsType(t, &errors.VSCCEndorsementPolicyError{}, err)
 }
 
+func TestKeylevelValidationCRDT(t *testing.T) {
+	t.Parallel()
+
+	// Scenario: we validate a transaction that merges CRDT payloads
+	// into a public and a pvt key that contain key-level validation
+	// params. We simulate policy check success and failure
+
+	vpMetadataKey := pb.MetaDataKeys_VALIDATION_PARAMETER.String()
+	mr := &mockState{GetStateMetadataRv: map[string][]byte{vpMetadataKey: []byte("EP")}, GetPrivateDataMetadataByHashRv: map[string][]byte{vpMetadataKey: []byte("EP")}}
+	ms := &mockStateFetcher{FetchStateRv: mr}
+	pm := &KeyLevelValidationParameterManagerImpl{PolicyTranslator: &mockTranslator{}, StateFetcher: ms}
+	pe := &mockPolicyEvaluator{EvaluateResByPolicy: map[string]error{"CCEP": fmt.Errorf("chaincode endorsement policy error")}}
+	validator := NewKeyLevelValidator(NewV13Evaluator(pe, pm), pm)
+
+	for i, buildRWSet := range []func(rwsbu *rwsetutil.RWSetBuilder){
+		func(rwsbu *rwsetutil.RWSetBuilder) {
+			rwsbu.AddToCRDT("cc", "IntAdd", "CRDTFIELD_key", []byte("1"), nil)
+			rwsbu.AddToCRDT("cc", "IntAdd", "CRDTFIELD_key", []byte("2"), nil)
+		},
+		func(rwsbu *rwsetutil.RWSetBuilder) {
+			rwsbu.AddToPvtAndHashedCRDT("cc", "coll", "IntAdd", "CRDTFIELD_key", []byte("1"))
+		},
+	} {
+		rwsbu := rwsetutil.NewRWSetBuilder()
+		buildRWSet(rwsbu)
+		rws := rwsbu.GetTxReadWriteSet()
+		rwsb, err := rws.ToProtoBytes()
+		require.NoError(t, err)
+		prp := []byte("barf")
+		blockNum := uint64(i + 1)
+		block := buildBlockWithTxs(buildTXWithRwset(rwsb))
+		block.Header.Number = blockNum
+
+		validator.PreValidate(0, block)
+
+		// the key-level validation parameter is used in place of the chaincode endorsement policy
+		pe.EvaluateRV = nil
+		err = validator.Validate("cc", blockNum, 0, rwsb, prp, []byte("CCEP"), []*pb.Endorsement{})
+		require.NoError(t, err)
+
+		pe.EvaluateRV = fmt.Errorf("policy evaluation error")
+		err = validator.Validate("cc", blockNum, 0, rwsb, prp, []byte("CCEP"), []*pb.Endorsement{})
+		require.Error(t, err)
+		require.IsType(t, &errors.VSCCEndorsementPolicyError{}, err)
+	}
+}
+
+func TestKeylevelValidationCRDTAfterMetaUpdate(t *testing.T) {
+	t.Parallel()
+
+	// Scenario: we validate a transaction that merges a CRDT payload
+	// into a key whose key-level validation parameters are updated by a
+	// preceding transaction of the block. The merge is validated once the
+	// preceding transaction is, and fails if it is valid
+
+	vpMetadataKey := pb.MetaDataKeys_VALIDATION_PARAMETER.String()
+	mr := &mockState{GetStateMetadataRv: map[string][]byte{vpMetadataKey: []byte("EP")}, GetPrivateDataMetadataByHashRv: map[string][]byte{vpMetadataKey: []byte("EP")}}
+	ms := &mockStateFetcher{FetchStateRv: mr}
+	pm := &KeyLevelValidationParameterManagerImpl{PolicyTranslator: &mockTranslator{}, StateFetcher: ms}
+	pe := &mockPolicyEvaluator{}
+	validator := NewKeyLevelValidator(NewV13Evaluator(pe, pm), pm)
+
+	rwsbu := rwsetutil.NewRWSetBuilder()
+	rwsbu.AddToCRDT("cc", "IntAdd", "CRDTFIELD_key", []byte("1"), nil)
+	rws := rwsbu.GetTxReadWriteSet()
+	rwsb, err := rws.ToProtoBytes()
+	require.NoError(t, err)
+	prp := []byte("barf")
+
+	for _, tc := range []struct {
+		blockNum       uint64
+		metaUpdateErr  error
+		expectedErrMsg string
+	}{
+		{1, fmt.Errorf(""), ""},
+		{2, nil, "validation parameters for key [CRDTFIELD_key] in namespace [cc:] have been changed"},
+	} {
+		block := buildBlockWithTxs(buildTXWithRwset(rwsetUpdatingMetadataFor("cc", "CRDTFIELD_key")), buildTXWithRwset(rwsb))
+		block.Header.Number = tc.blockNum
+		validator.PreValidate(1, block)
+
+		metaUpdateErr := tc.metaUpdateErr
+		go func() {
+			validator.PostValidate("cc", tc.blockNum, 0, metaUpdateErr)
+		}()
+
+		err = validator.Validate("cc", tc.blockNum, 1, rwsb, prp, []byte("CCEP"), []*pb.Endorsement{})
+		if tc.expectedErrMsg == "" {
+			require.NoError(t, err)
+			continue
+		}
+		require.IsType(t, &errors.VSCCEndorsementPolicyError{}, err)
+		require.Contains(t, err.Error(), tc.expectedErrMsg)
+	}
+}
+
 func TestKeylevelValidationPolicyRetrievalFailure(t *testing.T) {
 	t.Parallel()
 
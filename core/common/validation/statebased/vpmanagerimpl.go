@@ -269,7 +269,12 @@ func (m *KeyLevelValidationParameterManagerImpl) ExtractValidationParameterDepen
 		// here we cycle through all metadata updates generated by this transaction
 		// and signal that transaction (blockNum, txNum) modifies them so that
 		// all subsequent transaction know they have to wait for validation of
-		// transaction (blockNum, txNum) before they can continue
+		// transaction (blockNum, txNum) before they can continue.
+		// Like the writes, the CRDT payloads carry the metadata of the key forward,
+		// hence they introduce no dependency, whereas a transaction merging into a
+		// key waits for the preceding transactions updating the metadata of the key.
+		// As for the deletes of the writes, a CRDT delete dropping the metadata of
+		// the key introduces no dependency either
 		for _, rws := range rwset.NsRwSets {
 			for _, mw := range rws.KvRwSet.MetadataWrites {
 				// record the fact that this key has a dependency on our tx
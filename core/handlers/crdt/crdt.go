@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crdt
+
+// Resolver merges the diff carried by a CRDT payload into
+// the current value of a key.
+// Resolvers are invoked while committing a block on every peer
+// of the channel, so they must be deterministic and must not
+// depend on any state other than their input. A resolver may be
+// invoked concurrently for different keys
+type Resolver interface {
+	// Resolve returns the merged value given the current value of the key
+	// (empty if the key does not exist yet) and the diff to be merged into it
+	Resolve(curValue []byte, diffValue []byte) ([]byte, error)
+}
+
+// ResolverFunc is an adapter that allows the use of an ordinary
+// function as a Resolver
+type ResolverFunc func(curValue []byte, diffValue []byte) ([]byte, error)
+
+// Resolve calls f(curValue, diffValue)
+func (f ResolverFunc) Resolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return f(curValue, diffValue)
+}
+
+// HeightAwareResolver is implemented by the resolvers that need the position
+// in the ledger of the transaction whose payload is merged, e.g. to order
+// concurrent writes. The height is the same on every peer of the channel
+type HeightAwareResolver interface {
+	Resolver
+	// ResolveAt is invoked instead of Resolve, whenever the height is known, with the
+	// number of the block and the position in the block of the transaction being committed
+	ResolveAt(curValue []byte, diffValue []byte, blockNum uint64, txNum uint64) ([]byte, error)
+}
@@ -0,0 +1,26 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"github.com/hyperledger/fabric/core/handlers/crdt"
+)
+
+// NewResolver creates a new CRDT resolver
+func NewResolver() crdt.Resolver {
+	return &resolver{}
+}
+
+type resolver struct{}
+
+// Resolve overwrites the current value with the diff
+func (r *resolver) Resolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return diffValue, nil
+}
+
+func main() {
+}
@@ -0,0 +1,20 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver(t *testing.T) {
+	res := NewResolver()
+	out, err := res.Resolve([]byte("cur"), []byte("diff"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("diff"), out)
+}
@@ -8,16 +8,18 @@ package library
 
 import (
 	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
 // Config configures the factory methods
 // and plugins for the registry
 type Config struct {
-	AuthFilters []*HandlerConfig `yaml:"authFilters"`
-	Decorators  []*HandlerConfig `yaml:"decorators"`
-	Endorsers   PluginMapping    `yaml:"endorsers"`
-	Validators  PluginMapping    `yaml:"validators"`
+	AuthFilters   []*HandlerConfig      `yaml:"authFilters"`
+	Decorators    []*HandlerConfig      `yaml:"decorators"`
+	Endorsers     PluginMapping         `yaml:"endorsers"`
+	Validators    PluginMapping         `yaml:"validators"`
+	CRDTResolvers []*CRDTResolverConfig `yaml:"crdtResolvers"`
 }
 
 // PluginMapping stores a map between chaincode id to plugin config
@@ -29,6 +31,13 @@ type HandlerConfig struct {
 	Library string `yaml:"library"`
 }
 
+// CRDTResolverConfig binds a CRDT resolution type to a plugin or compiled resolver
+type CRDTResolverConfig struct {
+	ResolutionType string `yaml:"resolutionType"`
+	Name           string `yaml:"name"`
+	Library        string `yaml:"library"`
+}
+
 func LoadConfig() (Config, error) {
 	var authFilters, decorators []*HandlerConfig
 	if err := mapstructure.Decode(viper.Get("peer.handlers.authFilters"), &authFilters); err != nil {
@@ -39,6 +48,20 @@ func LoadConfig() (Config, error) {
 		return Config{}, err
 	}
 
+	var crdtResolvers []*CRDTResolverConfig
+	if err := mapstructure.Decode(viper.Get("peer.handlers.crdtResolvers"), &crdtResolvers); err != nil {
+		return Config{}, err
+	}
+	for _, config := range crdtResolvers {
+		if config.ResolutionType == "" {
+			return Config{}, errors.Errorf("CRDT resolver %s has no resolution type", config.Name)
+		}
+		// there are no compiled CRDT resolvers, a resolver is always loaded from a plugin
+		if config.Library == "" {
+			return Config{}, errors.Errorf("CRDT resolver %s of resolution type %s has no library", config.Name, config.ResolutionType)
+		}
+	}
+
 	endorsers, validators := make(PluginMapping), make(PluginMapping)
 	e := viper.GetStringMap("peer.handlers.endorsers")
 	for k := range e {
@@ -55,9 +78,10 @@ func LoadConfig() (Config, error) {
 	}
 
 	return Config{
-		AuthFilters: authFilters,
-		Decorators:  decorators,
-		Endorsers:   endorsers,
-		Validators:  validators,
+		AuthFilters:   authFilters,
+		Decorators:    decorators,
+		Endorsers:     endorsers,
+		Validators:    validators,
+		CRDTResolvers: crdtResolvers,
 	}, nil
 }
@@ -36,6 +36,10 @@ peer:
       vscc:
         name: DefaultValidation
         library: /path/to/vscc.so
+    crdtResolvers:
+      - resolutionType: BigIntAdd
+        name: BigIntAdd
+        library: /path/to/bigint.so
 `
 
 	viper.SetConfigType("yaml")
@@ -60,10 +64,49 @@ peer:
 		Validators: PluginMapping{
 			"vscc": &HandlerConfig{Name: "DefaultValidation", Library: "/path/to/vscc.so"},
 		},
+		CRDTResolvers: []*CRDTResolverConfig{
+			{ResolutionType: "BigIntAdd", Name: "BigIntAdd", Library: "/path/to/bigint.so"},
+		},
 	}
 	require.EqualValues(t, expect, actual)
 }
 
+func TestLoadConfigCRDTResolverWithoutType(t *testing.T) {
+	yaml := `---
+peer:
+  handlers:
+    crdtResolvers:
+      - name: BigIntAdd
+        library: /path/to/bigint.so
+`
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(bytes.NewReader([]byte(yaml)))
+	require.NoError(t, err)
+	defer viper.Reset()
+
+	_, err = LoadConfig()
+	require.EqualError(t, err, "CRDT resolver BigIntAdd has no resolution type")
+}
+
+func TestLoadConfigCRDTResolverWithoutLibrary(t *testing.T) {
+	yaml := `---
+peer:
+  handlers:
+    crdtResolvers:
+      - resolutionType: BigIntAdd
+        name: BigIntAdd
+`
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(bytes.NewReader([]byte(yaml)))
+	require.NoError(t, err)
+	defer viper.Reset()
+
+	_, err = LoadConfig()
+	require.EqualError(t, err, "CRDT resolver BigIntAdd of resolution type BigIntAdd has no library")
+}
+
 func TestLoadConfigEnvVarOverride(t *testing.T) {
 	yaml := `---
 peer:
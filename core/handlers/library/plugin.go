@@ -15,6 +15,7 @@ import (
 	"plugin"
 
 	"github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
 	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
@@ -38,6 +39,8 @@ func (r *registry) loadPlugin(pluginPath string, handlerType HandlerType, extraA
 		r.initEndorsementPlugin(p, extraArgs...)
 	} else if handlerType == Validation {
 		r.initValidationPlugin(p, extraArgs...)
+	} else if handlerType == CRDTResolution {
+		r.initCRDTResolverPlugin(p, extraArgs...)
 	}
 }
 
@@ -114,6 +117,27 @@ func (r *registry) initValidationPlugin(p *plugin.Plugin, extraArgs ...string) {
 	r.validators[extraArgs[0]] = factory
 }
 
+// initCRDTResolverPlugin constructs a CRDT resolver from the given plugin
+func (r *registry) initCRDTResolverPlugin(p *plugin.Plugin, extraArgs ...string) {
+	if len(extraArgs) != 1 {
+		logger.Panicf("expected 1 argument in extraArgs")
+	}
+	constructorSymbol, err := p.Lookup(resolverPluginFactory)
+	if err != nil {
+		panicWithLookupError(resolverPluginFactory, err)
+	}
+
+	constructor, ok := constructorSymbol.(func() crdt.Resolver)
+	if !ok {
+		panicWithDefinitionError(resolverPluginFactory)
+	}
+	resolver := constructor()
+	if resolver == nil {
+		logger.Panicf("resolver instance returned nil")
+	}
+	r.crdtResolvers[extraArgs[0]] = resolver
+}
+
 // panicWithLookupError panics when a handler constructor lookup fails
 func panicWithLookupError(factory string, err error) {
 	logger.Panicf("Plugin must contain constructor with name %s. Error from lookup: %s", factory, err)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
 	endorsement2 "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
@@ -40,17 +41,22 @@ const (
 	Decoration
 	Endorsement
 	Validation
+	// CRDTResolution handler - merge CRDT payloads into the
+	// current value of a key at commit time
+	CRDTResolution
 
 	authPluginFactory      = "NewFilter"
 	decoratorPluginFactory = "NewDecorator"
 	pluginFactory          = "NewPluginFactory"
+	resolverPluginFactory  = "NewResolver"
 )
 
 type registry struct {
-	filters    []auth.Filter
-	decorators []decoration.Decorator
-	endorsers  map[string]endorsement2.PluginFactory
-	validators map[string]validation.PluginFactory
+	filters       []auth.Filter
+	decorators    []decoration.Decorator
+	endorsers     map[string]endorsement2.PluginFactory
+	validators    map[string]validation.PluginFactory
+	crdtResolvers map[string]crdt.Resolver
 }
 
 var (
@@ -63,8 +69,9 @@ var (
 func InitRegistry(c Config) Registry {
 	once.Do(func() {
 		reg = registry{
-			endorsers:  make(map[string]endorsement2.PluginFactory),
-			validators: make(map[string]validation.PluginFactory),
+			endorsers:     make(map[string]endorsement2.PluginFactory),
+			validators:    make(map[string]validation.PluginFactory),
+			crdtResolvers: make(map[string]crdt.Resolver),
 		}
 		reg.loadHandlers(c)
 	})
@@ -87,10 +94,18 @@ func (r *registry) loadHandlers(c Config) {
 	for chaincodeID, config := range c.Validators {
 		r.evaluateModeAndLoad(config, Validation, chaincodeID)
 	}
+
+	for _, config := range c.CRDTResolvers {
+		handlerConfig := &HandlerConfig{Name: config.Name, Library: config.Library}
+		r.evaluateModeAndLoad(handlerConfig, CRDTResolution, config.ResolutionType)
+	}
 }
 
 // evaluateModeAndLoad if a library path is provided, load the shared object
 func (r *registry) evaluateModeAndLoad(c *HandlerConfig, handlerType HandlerType, extraArgs ...string) {
+	if handlerType == CRDTResolution && c.Library == "" {
+		logger.Panicf("CRDT resolver %s of resolution type %s has no library", c.Name, extraArgs[0])
+	}
 	if c.Library != "" {
 		r.loadPlugin(c.Library, handlerType, extraArgs...)
 	} else {
@@ -123,6 +138,11 @@ func (r *registry) loadCompiled(handlerFactory string, handlerType HandlerType,
 			logger.Panicf("expected 1 argument in extraArgs")
 		}
 		r.validators[extraArgs[0]] = inst.(validation.PluginFactory)
+	} else if handlerType == CRDTResolution {
+		if len(extraArgs) != 1 {
+			logger.Panicf("expected 1 argument in extraArgs")
+		}
+		r.crdtResolvers[extraArgs[0]] = inst.(crdt.Resolver)
 	}
 }
 
@@ -137,6 +157,8 @@ func (r *registry) Lookup(handlerType HandlerType) interface{} {
 		return r.endorsers
 	} else if handlerType == Validation {
 		return r.validators
+	} else if handlerType == CRDTResolution {
+		return r.crdtResolvers
 	}
 
 	return nil
@@ -14,6 +14,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,7 @@ const (
 	decoratorPluginPackage = "github.com/hyperledger/fabric/core/handlers/decoration/plugin"
 	endorsementTestPlugin  = "github.com/hyperledger/fabric/core/handlers/endorsement/testdata/"
 	validationTestPlugin   = "github.com/hyperledger/fabric/core/handlers/validation/testdata/"
+	resolverPluginPackage  = "github.com/hyperledger/fabric/core/handlers/crdt/plugin"
 )
 
 var (
@@ -131,6 +133,26 @@ func TestValidationPlugin(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCRDTResolverPlugin(t *testing.T) {
+	if noplugin {
+		t.Skip("plugins disabled")
+	}
+
+	testDir := t.TempDir()
+
+	pluginPath := filepath.Join(testDir, "resolverplugin.so")
+	buildPlugin(t, pluginPath, resolverPluginPackage)
+
+	testReg := registry{crdtResolvers: make(map[string]crdt.Resolver)}
+	testReg.loadPlugin(pluginPath, CRDTResolution, "Overwrite")
+	mapping := testReg.Lookup(CRDTResolution).(map[string]crdt.Resolver)
+	resolver := mapping["Overwrite"]
+	require.NotNil(t, resolver)
+	merged, err := resolver.Resolve([]byte{1}, []byte{2})
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, merged)
+}
+
 func TestLoadPluginInvalidPath(t *testing.T) {
 	if noplugin {
 		t.Skip("plugins disabled")
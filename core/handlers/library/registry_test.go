@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
 	"github.com/stretchr/testify/require"
 )
@@ -43,3 +44,10 @@ func TestLoadCompiledInvalid(t *testing.T) {
 	testReg := registry{}
 	testReg.loadCompiled("InvalidFactory", Auth)
 }
+
+func TestLoadCRDTResolverWithoutLibrary(t *testing.T) {
+	testReg := registry{crdtResolvers: make(map[string]crdt.Resolver)}
+	require.PanicsWithValue(t, "CRDT resolver BigIntAdd of resolution type BigIntAdd has no library", func() {
+		testReg.loadHandlers(Config{CRDTResolvers: []*CRDTResolverConfig{{ResolutionType: "BigIntAdd", Name: "BigIntAdd"}}})
+	})
+}
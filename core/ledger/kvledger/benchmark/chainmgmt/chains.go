@@ -43,6 +43,9 @@ func newChainsMgr(mgrConf *ChainMgrConf, batchConf *BatchConf, initOp chainInitO
 	dataDir := filepath.Join(mgrConf.DataDir, "ledgersData")
 	ledgermgmtInitializer := ledgermgmttest.NewInitializer(dataDir)
 	ledgermgmtInitializer.Config.HistoryDBConfig.Enabled = true
+	ledgermgmtInitializer.Config.CRDTConfig = &ledger.CRDTConfig{
+		MergeWorkers: mgrConf.CRDTMergeWorkers,
+	}
 	if os.Getenv("useCouchDB") == "true" {
 		couchdbAddr, set := os.LookupEnv("COUCHDB_ADDR")
 		if !set {
@@ -12,6 +12,8 @@ type ChainMgrConf struct {
 	DataDir string
 	// NumChains field specifies the number of chains to instantiate
 	NumChains int
+	// CRDTMergeWorkers field specifies the number of goroutines merging the CRDT payloads of a block
+	CRDTMergeWorkers int
 }
 
 // BatchConf captures the batch related configurations
@@ -64,6 +64,8 @@ func confFromTestParams(testParams []string) *configuration {
 	// chainMgrConf
 	dataDir := flags.String("DataDir", conf.chainMgrConf.DataDir, "Dir for ledger data")
 	numChains := flags.Int("NumChains", conf.chainMgrConf.NumChains, "Number of chains")
+	crdtMergeWorkers := flags.Int("CRDTMergeWorkers", conf.chainMgrConf.CRDTMergeWorkers,
+		"Number of goroutines merging the CRDT payloads of a block, 0 for the number of CPUs")
 
 	// txConf
 	numParallelTxsPerChain := flags.Int("NumParallelTxPerChain",
@@ -95,6 +97,7 @@ func confFromTestParams(testParams []string) *configuration {
 
 	conf.chainMgrConf.DataDir = *dataDir
 	conf.chainMgrConf.NumChains = *numChains
+	conf.chainMgrConf.CRDTMergeWorkers = *crdtMergeWorkers
 	conf.txConf.numParallelTxsPerChain = *numParallelTxsPerChain
 	conf.txConf.numTotalTxs = *numTotalTxs
 	conf.txConf.numWritesPerTx = *numWritesPerTx
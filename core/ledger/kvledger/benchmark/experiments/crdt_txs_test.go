@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package experiments
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/benchmark/chainmgmt"
+)
+
+// BenchmarkCRDTTxs opens the existing chains and merges increments into CRDT counters by simulating CRDT transactions.
+// For each of the chains, this test launches the parallel clients (based on the configuration) and the clients simulate
+// and commit transactions that randomly select a configurable number of counters (NumWritesPerTx) and increment them.
+// The counters are named after the keys of the chains, without overlapping them, i.e. there are NumKVs counters across
+// chains, hence a small value of NumKVs makes the blocks merge many increments into the same counters. As the
+// increments are merged at commit time, none of the transactions is invalidated. This test assumes the chains created
+// by previously running BenchmarkInsertTxs
+//
+// The number of goroutines merging the CRDT payloads of a block is controlled by the test parameter CRDTMergeWorkers,
+// for instance -testParams=-NumChains=1, -NumParallelTxPerChain=10, -NumKVs=10, -NumTotalTx=100000, -CRDTMergeWorkers=4
+func BenchmarkCRDTTxs(b *testing.B) {
+	if b.N != 1 {
+		panic(fmt.Errorf(`This benchmark should be called with N=1 only. Run this with more volume of data`))
+	}
+	runCRDTTest()
+}
+
+func runCRDTTest() {
+	testEnv := chainmgmt.InitTestEnv(conf.chainMgrConf, conf.batchConf, chainmgmt.ChainInitOpOpen)
+	for _, chain := range testEnv.Chains() {
+		go runCRDTClientsForChain(chain)
+	}
+	testEnv.WaitForTestCompletion()
+}
+
+func runCRDTClientsForChain(chain *chainmgmt.Chain) {
+	numClients := conf.txConf.numParallelTxsPerChain
+	numTxForChain := calculateShare(conf.txConf.numTotalTxs, conf.chainMgrConf.NumChains, int(chain.ID))
+	wg := &sync.WaitGroup{}
+	wg.Add(numClients)
+	for i := 0; i < numClients; i++ {
+		numTxForClient := calculateShare(numTxForChain, numClients, i)
+		randomNumGen := rand.New(rand.NewSource(int64(time.Now().Nanosecond()) + int64(chain.ID)))
+		go runCRDTClient(chain, randomNumGen, numTxForClient, wg)
+	}
+	wg.Wait()
+	chain.Done()
+}
+
+func runCRDTClient(chain *chainmgmt.Chain, rand *rand.Rand, numTx int, wg *sync.WaitGroup) {
+	numWritesPerTx := conf.txConf.numWritesPerTx
+	maxKeyNumber := calculateShare(conf.dataConf.numKVs, conf.chainMgrConf.NumChains, int(chain.ID))
+
+	for i := 0; i < numTx; i++ {
+		simulator, err := chain.NewTxSimulator(util.GenerateUUID())
+		panicOnError(err)
+		for i := 0; i < numWritesPerTx; i++ {
+			key := "counter_" + constructKey(rand.Intn(maxKeyNumber))
+			diff := []byte(strconv.Itoa(rand.Intn(100) + 1))
+			panicOnError(simulator.SetCRDT(chaincodeName, "BigIntAdd", key, diff, nil))
+		}
+		simulator.Done()
+		sr, err := simulator.GetTxSimulationResults()
+		panicOnError(err)
+		srBytes, err := sr.GetPubSimulationBytes()
+		panicOnError(err)
+		chain.SubmitTx(srBytes)
+	}
+	wg.Done()
+}
@@ -8,7 +8,7 @@
 source ./common.sh
 
 #######################################################################################################
-# This shell script contains two functions that can be invoked to execute specific tests
+# This shell script contains functions that can be invoked to execute specific tests
 #
 # runInsertTxs - This function sets the environment variables and runs the benchmark function
 # 'BenchmarkInsertTxs' in package 'github.com/hyperledger/fabric/core/ledger/kvledger/benchmark/experiments'
@@ -16,6 +16,9 @@ source ./common.sh
 # runReadWriteTxs - This function sets the environment variables and runs the benchmark function
 # 'BenchmarkReadWriteTxs' in package 'github.com/hyperledger/fabric/core/ledger/kvledger/benchmark/experiments'
 #
+# runCRDTTxs - This function sets the environment variables and runs the benchmark function
+# 'BenchmarkCRDTTxs' in package 'github.com/hyperledger/fabric/core/ledger/kvledger/benchmark/experiments'
+#
 # For the details of test specific parameters, refer to the documentation in 'go' files for the tests
 #######################################################################################################
 
@@ -40,4 +43,14 @@ function runReadWriteTxs {
   setCommonTestParams
   TEST_PARAMS="$TEST_PARAMS, -NumTotalTx=$NumTotalTx"
   executeTest
-}
\ No newline at end of file
+}
+
+function runCRDTTxs {
+  FUNCTION_NAME="BenchmarkCRDTTxs"
+  if [ "$CLEAR_OS_CACHE" == "true" ]; then
+    clearOSCache
+  fi
+  setCommonTestParams
+  TEST_PARAMS="$TEST_PARAMS, -NumTotalTx=$NumTotalTx, -CRDTMergeWorkers=$CRDTMergeWorkers"
+  executeTest
+}
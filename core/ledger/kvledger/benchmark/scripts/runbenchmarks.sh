@@ -81,6 +81,15 @@ function varyNumTxs {
     done
 }
 
+function varyCRDTMergeWorkers {
+    source $PARAM_FILE
+    for v in "${ArrayCRDTMergeWorkers[@]}"
+    do
+        CRDTMergeWorkers=$v
+        rm -rf $DataDir;upCouchDB;runInsertTxs;runCRDTTxs
+    done
+}
+
 function runLargeDataExperiment {
   source $PARAM_FILE
   if [[ $RunLargeDataExperiment = "true" ]]
@@ -125,4 +134,5 @@ fi
   varyKVSize
   varyBatchSize
   varyNumTxs
+  varyCRDTMergeWorkers
   runLargeDataExperiment
@@ -19,6 +19,7 @@ NumWritesPerTx=4
 NumReadsPerTx=4
 BatchSize=50
 KVSize=200
+CRDTMergeWorkers=0
 
 #####################################################################################################################
 # Following variables controls what experiments to run. Typically, you would wish to run only selected experiments. 
@@ -41,5 +42,7 @@ ArrayKVSize=(100 200 500 1000 2000)
 ArrayBatchSize=(10 20 100 500)
 # Run experiments with varying "NumTotalTx" (keeping remaining params as default - see function 'varyNumTxs' in file runbenchmarks.sh)
 ArrayNumTxs=(100000 200000 500000 1000000)
+# Run CRDT experiments with varying "CRDTMergeWorkers" (keeping remaining params as default - see function 'varyCRDTMergeWorkers' in file runbenchmarks.sh)
+ArrayCRDTMergeWorkers=(1 2 4 8)
 # Whether to run experiment with large amount of data (see function 'runLargeDataExperiment' in file runbenchmarks.sh)
 RunLargeDataExperiment=true
\ No newline at end of file
@@ -22,6 +22,8 @@ const (
 	MetadataPresenceIndicator
 	// SnapshotRequest maintains the information for snapshot requests
 	SnapshotRequest
+	// CRDTValues maintains the values of the CRDT keys merged by the transactions of the blocks
+	CRDTValues
 )
 
 // Provider provides db handle to different bookkeepers
@@ -50,7 +52,7 @@ func (p *Provider) Close() {
 
 // Drop drops channel-specific data from the config history db
 func (p *Provider) Drop(ledgerID string) error {
-	for _, cat := range []Category{PvtdataExpiry, MetadataPresenceIndicator, SnapshotRequest} {
+	for _, cat := range []Category{PvtdataExpiry, MetadataPresenceIndicator, SnapshotRequest, CRDTValues} {
 		if err := p.dbProvider.Drop(dbName(ledgerID, cat)); err != nil {
 			return err
 		}
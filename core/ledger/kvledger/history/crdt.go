@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package history
+
+import (
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// The history records of the CRDT payloads hold the value of the key right after the transaction,
+// as merged by the validator, so that the history of a key shows how the merged value evolved.
+// The value is prefixed by a marker telling whether the value is known. The records of the writes
+// hold no value, as the value written by the transaction is found in the block
+const (
+	crdtValueAvailable   byte = 1
+	crdtValueUnavailable byte = 2
+)
+
+type crdtValue struct {
+	value     []byte
+	available bool
+}
+
+func decodeCRDTValue(b []byte) *crdtValue {
+	if len(b) == 0 || b[0] != crdtValueAvailable {
+		return &crdtValue{}
+	}
+	return &crdtValue{value: b[1:], available: true}
+}
+
+type nsKey struct {
+	ns, key string
+}
+
+// crdtHistoryValues encodes the values the CRDT keys merged by a transaction have right after the
+// transaction, by key. There are none if the values of the transaction are not known
+func crdtHistoryValues(txValues *peer.TxCRDTValues) map[nsKey][]byte {
+	historyValues := map[nsKey][]byte{}
+	for _, v := range txValues.GetValues() {
+		historyValues[nsKey{v.Namespace, v.Key}] = append([]byte{crdtValueAvailable}, v.Value...)
+	}
+	return historyValues
+}
+
+// crdtHistoryValue returns the value of the history record of a CRDT key
+func crdtHistoryValue(historyValues map[nsKey][]byte, ns, key string) []byte {
+	if value, ok := historyValues[nsKey{ns, key}]; ok {
+		return value
+	}
+	return []byte{crdtValueUnavailable}
+}
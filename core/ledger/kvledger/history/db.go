@@ -8,6 +8,7 @@ package history
 
 import (
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/dataformat"
@@ -75,8 +76,10 @@ type DB struct {
 	name    string
 }
 
-// Commit implements method in HistoryDB interface
-func (d *DB) Commit(block *common.Block) error {
+// Commit implements method in HistoryDB interface. The CRDT values are the values the CRDT keys
+// merged by the valid transactions of the block have right after each transaction, by transaction
+// number, as computed by the validator. A key whose value is missing is recorded as unknown
+func (d *DB) Commit(block *common.Block, crdtValues map[uint64]*peer.TxCRDTValues) error {
 	blockNo := block.Header.Number
 	// Set the starting tranNo to 0
 	var tranNo uint64
@@ -125,7 +128,8 @@ func (d *DB) Commit(block *common.Block) error {
 			if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
 				return err
 			}
-			// add a history record for each write
+			// add a history record for each write and for each key a CRDT payload is merged into
+			historyValues := crdtHistoryValues(crdtValues[tranNo])
 			for _, nsRWSet := range txRWSet.NsRwSets {
 				ns := nsRWSet.NameSpace
 
@@ -134,6 +138,10 @@ func (d *DB) Commit(block *common.Block) error {
 					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
 					dbBatch.Put(dataKey, emptyValue)
 				}
+				for _, payload := range nsRWSet.KvRwSet.CrdtPayload {
+					dataKey := constructDataKey(ns, payload.Key, blockNo, tranNo)
+					dbBatch.Put(dataKey, crdtHistoryValue(historyValues, ns, payload.Key))
+				}
 			}
 
 		} else {
@@ -191,8 +199,8 @@ func (d *DB) Name() string {
 	return "history"
 }
 
-// CommitLostBlock implements method in interface kvledger.Recoverer
-func (d *DB) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error {
+// CommitLostBlock recommits a block along with the CRDT values of its transactions, see Commit
+func (d *DB) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData, crdtValues map[uint64]*peer.TxCRDTValues) error {
 	block := blockAndPvtdata.Block
 
 	// log every 1000th block at Info level so that history rebuild progress can be tracked in production envs.
@@ -201,5 +209,5 @@ func (d *DB) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error {
 	} else {
 		logger.Debugf("Recommitting block [%d] to history database", block.Header.Number)
 	}
-	return d.Commit(block)
+	return d.Commit(block, crdtValues)
 }
@@ -51,7 +51,7 @@ func TestSavepoint(t *testing.T) {
 	require.Equal(t, uint64(0), blockNum)
 
 	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
-	require.NoError(t, env.testHistoryDB.Commit(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
 	// read the savepoint, it should now exist and return a Height object with BlockNum 0
 	savepoint, err = env.testHistoryDB.GetLastSavepoint()
 	require.NoError(t, err, "Error upon historyDatabase.GetLastSavepoint()")
@@ -65,7 +65,7 @@ func TestSavepoint(t *testing.T) {
 	simRes, _ := simulator.GetTxSimulationResults()
 	pubSimResBytes, _ := simRes.GetPubSimulationBytes()
 	block1 := bg.NextBlock([][]byte{pubSimResBytes})
-	require.NoError(t, env.testHistoryDB.Commit(block1))
+	require.NoError(t, env.testHistoryDB.Commit(block1, nil))
 	savepoint, err = env.testHistoryDB.GetLastSavepoint()
 	require.NoError(t, err, "Error upon historyDatabase.GetLastSavepoint()")
 	require.Equal(t, uint64(1), savepoint.BlockNum)
@@ -86,7 +86,7 @@ func TestSavepoint(t *testing.T) {
 	block2 := bg.NextBlock([][]byte{pubSimResBytes})
 
 	// assume that the peer failed to commit this block to historyDB and is being recovered now
-	require.NoError(t, env.testHistoryDB.CommitLostBlock(&ledger.BlockAndPvtData{Block: block2}))
+	require.NoError(t, env.testHistoryDB.CommitLostBlock(&ledger.BlockAndPvtData{Block: block2}, nil))
 	savepoint, err = env.testHistoryDB.GetLastSavepoint()
 	require.NoError(t, err, "Error upon historyDatabase.GetLastSavepoint()")
 	require.Equal(t, uint64(2), savepoint.BlockNum)
@@ -137,7 +137,7 @@ func TestHistory(t *testing.T) {
 
 	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
 	require.NoError(t, store1.AddBlock(gb))
-	require.NoError(t, env.testHistoryDB.Commit(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
 
 	// block1
 	txid := util2.GenerateUUID()
@@ -150,7 +150,7 @@ func TestHistory(t *testing.T) {
 	block1 := bg.NextBlock([][]byte{pubSimResBytes})
 	err = store1.AddBlock(block1)
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block1)
+	err = env.testHistoryDB.Commit(block1, nil)
 	require.NoError(t, err)
 
 	// block2 tran1
@@ -175,7 +175,7 @@ func TestHistory(t *testing.T) {
 	block2 := bg.NextBlock(simulationResults)
 	err = store1.AddBlock(block2)
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block2)
+	err = env.testHistoryDB.Commit(block2, nil)
 	require.NoError(t, err)
 
 	// block3
@@ -188,7 +188,7 @@ func TestHistory(t *testing.T) {
 	block3 := bg.NextBlock([][]byte{pubSimResBytes})
 	err = store1.AddBlock(block3)
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block3)
+	err = env.testHistoryDB.Commit(block3, nil)
 	require.NoError(t, err)
 	t.Logf("Inserted all 3 blocks")
 
@@ -236,6 +236,188 @@ func TestHistory(t *testing.T) {
 	})
 }
 
+func TestHistoryForCRDTKey(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	store1, err := provider.Open("ledger1")
+	require.NoError(t, err)
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	require.NoError(t, store1.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
+
+	key := "counter"
+	simulate := func(update func(s ledger.TxSimulator)) []byte {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		update(simulator)
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		return pubSimResBytes
+	}
+	add := func(diffs ...string) []byte {
+		return simulate(func(s ledger.TxSimulator) {
+			for _, diff := range diffs {
+				require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte(diff), nil))
+			}
+		})
+	}
+	// the values are the ones the validator computed for the transactions of the block, by transaction number
+	commit := func(crdtValues map[uint64]string, txs ...[]byte) {
+		txsCRDTValues := map[uint64]*peer.TxCRDTValues{}
+		for txNum, value := range crdtValues {
+			txsCRDTValues[txNum] = &peer.TxCRDTValues{
+				Values: []*peer.CRDTValue{{Namespace: "ns1", Key: key, Value: []byte(value)}},
+			}
+		}
+		block := bg.NextBlock(txs)
+		require.NoError(t, store1.AddBlock(block))
+		require.NoError(t, env.testHistoryDB.Commit(block, txsCRDTValues))
+	}
+
+	commit(map[uint64]string{0: "5"}, add("5"))
+	commit(map[uint64]string{0: "7", 1: "17"}, add("3", "-1"), add("10"))
+	// a write sets the value of the key
+	commit(
+		map[uint64]string{1: "101"},
+		simulate(func(s ledger.TxSimulator) { require.NoError(t, s.SetState("ns1", key, []byte("100"))) }),
+		add("1"),
+	)
+	// the value of the key after the second transaction is not known, e.g. as the block
+	// is recommitted once the values of its transactions are no longer retained
+	commit(map[uint64]string{0: "103"}, add("2"), add("4"))
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
+	require.NoError(t, err)
+	itr, err := qhistory.GetHistoryForKey("ns1", key)
+	require.NoError(t, err)
+	defer itr.Close()
+
+	type expectedModification struct {
+		value       string
+		unavailable bool
+		diffs       []string
+	}
+	expected := []expectedModification{
+		{"", true, []string{"4"}},
+		{"103", false, []string{"2"}},
+		{"101", false, []string{"1"}},
+		{"100", false, nil},
+		{"17", false, []string{"10"}},
+		{"7", false, []string{"3", "-1"}},
+		{"5", false, []string{"5"}},
+	}
+	for _, e := range expected {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		kmod := res.(*queryresult.KeyModification)
+		if e.unavailable {
+			require.Empty(t, kmod.Value)
+		} else {
+			require.Equal(t, []byte(e.value), kmod.Value)
+		}
+		require.False(t, kmod.IsDelete)
+		require.Equal(t, e.unavailable, kmod.CrdtValueUnavailable)
+		require.Len(t, kmod.CrdtMerges, len(e.diffs))
+		for i, diff := range e.diffs {
+			require.Equal(t, "IntAdd", kmod.CrdtMerges[i].ResolutionType)
+			require.Equal(t, []byte(diff), kmod.CrdtMerges[i].Diff)
+		}
+	}
+	res, err := itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
+func TestHistoryForCRDTResetAndDelete(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	store1, err := provider.Open("ledger1")
+	require.NoError(t, err)
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	require.NoError(t, store1.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
+
+	key := "counter"
+	simulate := func(update func(s ledger.TxSimulator)) []byte {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		update(simulator)
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		return pubSimResBytes
+	}
+	commit := func(crdtValues map[uint64]*peer.CRDTValue, txs ...[]byte) {
+		txsCRDTValues := map[uint64]*peer.TxCRDTValues{}
+		for txNum, value := range crdtValues {
+			txsCRDTValues[txNum] = &peer.TxCRDTValues{Values: []*peer.CRDTValue{value}}
+		}
+		block := bg.NextBlock(txs)
+		require.NoError(t, store1.AddBlock(block))
+		require.NoError(t, env.testHistoryDB.Commit(block, txsCRDTValues))
+	}
+
+	commit(
+		map[uint64]*peer.CRDTValue{0: {Namespace: "ns1", Key: key, Value: []byte("5")}},
+		simulate(func(s ledger.TxSimulator) { require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("5"), nil)) }),
+	)
+	commit(
+		map[uint64]*peer.CRDTValue{
+			0: {Namespace: "ns1", Key: key, Value: []byte("3")},
+			1: {Namespace: "ns1", Key: key, IsDelete: true},
+		},
+		simulate(func(s ledger.TxSimulator) {
+			require.NoError(t, s.ResetCRDT("ns1", "IntAdd", key, []byte("2")))
+			require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("1"), nil))
+		}),
+		simulate(func(s ledger.TxSimulator) { require.NoError(t, s.DeleteCRDT("ns1", key)) }),
+	)
+	// the key is recreated from an empty value
+	commit(
+		map[uint64]*peer.CRDTValue{0: {Namespace: "ns1", Key: key, Value: []byte("4")}},
+		simulate(func(s ledger.TxSimulator) { require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("4"), nil)) }),
+	)
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
+	require.NoError(t, err)
+	itr, err := qhistory.GetHistoryForKey("ns1", key)
+	require.NoError(t, err)
+	defer itr.Close()
+
+	next := func() *queryresult.KeyModification {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		return res.(*queryresult.KeyModification)
+	}
+	kmod := next()
+	require.Equal(t, []byte("4"), kmod.Value)
+	require.False(t, kmod.IsDelete)
+
+	kmod = next()
+	require.Nil(t, kmod.Value)
+	require.True(t, kmod.IsDelete)
+	require.False(t, kmod.CrdtValueUnavailable)
+	require.Len(t, kmod.CrdtMerges, 1)
+	require.Equal(t, kvrwset.CRDTPayload_DELETE, kmod.CrdtMerges[0].Operation)
+
+	kmod = next()
+	require.Equal(t, []byte("3"), kmod.Value)
+	require.False(t, kmod.IsDelete)
+	require.Len(t, kmod.CrdtMerges, 2)
+	require.Equal(t, kvrwset.CRDTPayload_RESET, kmod.CrdtMerges[0].Operation)
+	require.Equal(t, kvrwset.CRDTPayload_MERGE, kmod.CrdtMerges[1].Operation)
+
+	require.Equal(t, []byte("5"), next().Value)
+	res, err := itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
 func TestHistoryForInvalidTran(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -247,7 +429,7 @@ func TestHistoryForInvalidTran(t *testing.T) {
 
 	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
 	require.NoError(t, store1.AddBlock(gb))
-	require.NoError(t, env.testHistoryDB.Commit(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
 
 	// block1
 	txid := util2.GenerateUUID()
@@ -266,7 +448,7 @@ func TestHistoryForInvalidTran(t *testing.T) {
 
 	err = store1.AddBlock(block1)
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block1)
+	err = env.testHistoryDB.Commit(block1, nil)
 	require.NoError(t, err)
 
 	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
@@ -287,7 +469,7 @@ func TestGenesisBlockNoError(t *testing.T) {
 	defer env.cleanup()
 	block, err := configtxtest.MakeGenesisBlock("test_chainid")
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block)
+	err = env.testHistoryDB.Commit(block, nil)
 	require.NoError(t, err)
 }
 
@@ -304,7 +486,7 @@ func TestHistoryWithKeyContainingNilBytes(t *testing.T) {
 
 	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
 	require.NoError(t, store1.AddBlock(gb))
-	require.NoError(t, env.testHistoryDB.Commit(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
 
 	// block1
 	txid := util2.GenerateUUID()
@@ -316,7 +498,7 @@ func TestHistoryWithKeyContainingNilBytes(t *testing.T) {
 	block1 := bg.NextBlock([][]byte{pubSimResBytes})
 	err = store1.AddBlock(block1)
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block1)
+	err = env.testHistoryDB.Commit(block1, nil)
 	require.NoError(t, err)
 
 	// block2 tran1
@@ -366,7 +548,7 @@ func TestHistoryWithKeyContainingNilBytes(t *testing.T) {
 	block2 := bg.NextBlock(simulationResults)
 	err = store1.AddBlock(block2)
 	require.NoError(t, err)
-	err = env.testHistoryDB.Commit(block2)
+	err = env.testHistoryDB.Commit(block2, nil)
 	require.NoError(t, err)
 
 	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
@@ -402,7 +584,7 @@ func TestHistoryWithBlockNumber256(t *testing.T) {
 
 	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
 	require.NoError(t, store1.AddBlock(gb))
-	require.NoError(t, env.testHistoryDB.Commit(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb, nil))
 
 	// add 256 blocks, each block has 1 transaction setting state for "ns1" and "key", value is "value<blockNum>"
 	for i := 1; i <= 256; i++ {
@@ -416,7 +598,7 @@ func TestHistoryWithBlockNumber256(t *testing.T) {
 		block := bg.NextBlock([][]byte{pubSimResBytes})
 		err = store1.AddBlock(block)
 		require.NoError(t, err)
-		err = env.testHistoryDB.Commit(block)
+		err = env.testHistoryDB.Commit(block, nil)
 		require.NoError(t, err)
 	}
 
@@ -463,9 +645,9 @@ func TestDrop(t *testing.T) {
 
 		historydb := env.testHistoryDBProvider.GetDBHandle(ledgerid)
 		require.NoError(t, store.AddBlock(gb))
-		require.NoError(t, historydb.Commit(gb))
+		require.NoError(t, historydb.Commit(gb, nil))
 		require.NoError(t, store.AddBlock(block1))
-		require.NoError(t, historydb.Commit(block1))
+		require.NoError(t, historydb.Commit(block1, nil))
 
 		historydbQE, err := historydb.NewQueryExecutor(store)
 		require.NoError(t, err)
@@ -540,9 +722,9 @@ func TestHistoryWithKVWriteOfNilValue(t *testing.T) {
 
 	historydb := env.testHistoryDBProvider.GetDBHandle("ledger1")
 	require.NoError(t, store.AddBlock(gb))
-	require.NoError(t, historydb.Commit(gb))
+	require.NoError(t, historydb.Commit(gb, nil))
 	require.NoError(t, store.AddBlock(block1))
-	require.NoError(t, historydb.Commit(block1))
+	require.NoError(t, historydb.Commit(block1, nil))
 
 	historydbQE, err := historydb.NewQueryExecutor(store)
 	require.NoError(t, err)
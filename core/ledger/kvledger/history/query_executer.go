@@ -9,6 +9,7 @@ package history
 import (
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -74,7 +75,7 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 	}
 
 	// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
-	queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+	queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key, scanner.dbItr.Value())
 	if err != nil {
 		return nil, err
 	}
@@ -92,8 +93,9 @@ func (scanner *historyScanner) Close() {
 	scanner.dbItr.Release()
 }
 
-// getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key
-func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
+// getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key and for the CRDT payloads
+// merged into it. The value of a CRDT key after the merges is taken from the history record
+func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string, historyValue []byte) (commonledger.QueryResult, error) {
 	logger.Debugf("Entering getKeyModificationFromTran %s:%s", namespace, key)
 
 	// extract action from the envelope
@@ -131,15 +133,38 @@ func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string,
 	// look for the namespace and key by looping through the transaction's ReadWriteSets
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		if nsRWSet.NameSpace == namespace {
-			// got the correct namespace, now find the key write
+			// got the correct namespace, now find the CRDT payloads and the key write
+			var crdtMerges []*queryresult.CRDTMerge
+			crdtDelete := false
+			for _, payload := range nsRWSet.KvRwSet.CrdtPayload {
+				if payload.Key == key {
+					crdtMerges = append(crdtMerges, &queryresult.CRDTMerge{
+						ResolutionType: payload.ResolutionType, Diff: payload.Data, Operation: payload.Operation,
+					})
+					// the key is deleted if the last payload of the transaction deletes it
+					crdtDelete = payload.Operation == kvrwset.CRDTPayload_DELETE
+				}
+			}
+			// the writes are applied after the CRDT payloads, hence a write sets the value
 			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 				if kvWrite.Key == key {
 					return &queryresult.KeyModification{
 						TxId: txID, Value: kvWrite.Value,
 						Timestamp: timestamp, IsDelete: rwsetutil.IsKVWriteDelete(kvWrite),
+						CrdtMerges: crdtMerges,
 					}, nil
 				}
 			} // end keys loop
+			if len(crdtMerges) != 0 {
+				merged := decodeCRDTValue(historyValue)
+				if crdtDelete {
+					merged.value = nil
+				}
+				return &queryresult.KeyModification{
+					TxId: txID, Value: merged.value, Timestamp: timestamp, IsDelete: crdtDelete,
+					CrdtMerges: crdtMerges, CrdtValueUnavailable: !merged.available,
+				}, nil
+			}
 			logger.Debugf("key [%s] not found in namespace [%s]'s writeset", key, namespace)
 			return nil, nil
 		} // end if
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
@@ -86,6 +87,7 @@ type lgrInitializer struct {
 	stats                    *ledgerStats
 	customTxProcessors       map[common.HeaderType]ledger.CustomTxProcessor
 	hashProvider             ledger.HashProvider
+	crdtResolvers            *crdt_resolver.Registry
 	config                   *ledger.Config
 }
 
@@ -116,6 +118,12 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		return hash.Sum(nil), nil
 	}
 
+	var crdtMergeWorkers int
+	var crdtValuesRetention uint64
+	if initializer.config.CRDTConfig != nil {
+		crdtMergeWorkers = initializer.config.CRDTConfig.MergeWorkers
+		crdtValuesRetention = initializer.config.CRDTConfig.ValuesRetention
+	}
 	txmgrInitializer := &txmgr.Initializer{
 		LedgerID:            ledgerID,
 		DB:                  initializer.stateDB,
@@ -125,6 +133,10 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		CCInfoProvider:      initializer.ccInfoProvider,
 		CustomTxProcessors:  initializer.customTxProcessors,
 		HashFunc:            rwsetHashFunc,
+		CRDTResolvers:       initializer.crdtResolvers,
+		CRDTMergeWorkers:    crdtMergeWorkers,
+		CRDTValuesRetention: crdtValuesRetention,
+		PvtdataOfBlock:      l.committedPvtdataOfBlock,
 	}
 	if err := l.initTxMgr(txmgrInitializer); err != nil {
 		return nil, err
@@ -368,7 +380,7 @@ func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
 	lastBlockInBlockStore := info.Height - 1
 	recoverables := []recoverable{l.txmgr}
 	if l.historyDB != nil {
-		recoverables = append(recoverables, l.historyDB)
+		recoverables = append(recoverables, &historyRecoverable{l.historyDB, l.txmgr})
 	}
 	recoverers := []*recoverer{}
 	for _, recoverable := range recoverables {
@@ -411,19 +423,21 @@ func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
 	}
 
 	// both dbs need to be recovered
-	if recoverers[0].nextRequiredBlock > recoverers[1].nextRequiredBlock {
-		// swap (put the lagger db at 0 index)
-		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
+	lagger, other := recoverers[0], recoverers[1]
+	if lagger.nextRequiredBlock > other.nextRequiredBlock {
+		// swap (put the lagger db first)
+		lagger, other = other, lagger
 	}
-	if recoverers[0].nextRequiredBlock != recoverers[1].nextRequiredBlock {
+	if lagger.nextRequiredBlock != other.nextRequiredBlock {
 		// bring the lagger db equal to the other db
-		if err := l.recommitLostBlocks(recoverers[0].nextRequiredBlock, recoverers[1].nextRequiredBlock-1,
-			recoverers[0].recoverable); err != nil {
+		if err := l.recommitLostBlocks(lagger.nextRequiredBlock, other.nextRequiredBlock-1,
+			lagger.recoverable); err != nil {
 			return err
 		}
 	}
-	// get both the db upto block storage
-	return l.recommitLostBlocks(recoverers[1].nextRequiredBlock, lastBlockInBlockStore,
+	// get both the db upto block storage. The state db recommits each block first,
+	// as the history db records the CRDT values computed by the state db
+	return l.recommitLostBlocks(other.nextRequiredBlock, lastBlockInBlockStore,
 		recoverers[0].recoverable, recoverers[1].recoverable)
 }
 
@@ -700,7 +714,7 @@ func (l *kvLedger) commit(pvtdataAndBlock *ledger.BlockAndPvtData, commitOpts *l
 	// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
 	if l.historyDB != nil {
 		logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, blockNo)
-		if err := l.historyDB.Commit(block); err != nil {
+		if err := l.historyDB.Commit(block, txsCRDTValues(txstatsInfo)); err != nil {
 			panic(errors.WithMessage(err, "Error during commit to history db"))
 		}
 	}
@@ -788,6 +802,7 @@ func (l *kvLedger) updateBlockStats(
 	l.stats.updateBlockstorageAndPvtdataCommitTime(blockstorageAndPvtdataCommitTime)
 	l.stats.updateStatedbCommitTime(statedbCommitTime)
 	l.stats.updateTransactionsStats(txstatsInfo)
+	l.stats.updateCRDTStats(txstatsInfo)
 }
 
 // GetMissingPvtDataInfoForMostRecentBlocks returns the missing private data information for the
@@ -854,6 +869,25 @@ func (l *kvLedger) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilte
 	return pvtdata, nil
 }
 
+// txsCRDTValues returns the values the public CRDT keys merged by the valid transactions of a block
+// have right after each transaction, by transaction number
+func txsCRDTValues(txsStatInfo []*validation.TxStatInfo) map[uint64]*peer.TxCRDTValues {
+	crdtValues := map[uint64]*peer.TxCRDTValues{}
+	for txNum, txStatInfo := range txsStatInfo {
+		if len(txStatInfo.CRDTValues) != 0 {
+			crdtValues[uint64(txNum)] = &peer.TxCRDTValues{TxId: txStatInfo.TxIDFromChannelHeader, Values: txStatInfo.CRDTValues}
+		}
+	}
+	return crdtValues
+}
+
+// GetCRDTValuesByNum returns the values the public CRDT keys merged by the valid transactions of the given block
+// have right after each transaction. The values are recorded along with the commit of the block to the state
+// database, before the block APIs are unlocked, hence they are available to the consumers of the block
+func (l *kvLedger) GetCRDTValuesByNum(blockNum uint64) (map[uint64]*peer.TxCRDTValues, error) {
+	return l.txmgr.GetCRDTValuesByNum(blockNum)
+}
+
 // DoesPvtDataInfoExist returns true when
 // (1) the ledger has pvtdata associated with the given block number (or)
 // (2) a few or all pvtdata associated with the given block number is missing but the
@@ -931,6 +965,24 @@ func (l *kvLedger) applyValidTxPvtDataOfOldBlocks(hashVerifiedPvtData map[uint64
 	return l.txmgr.RemoveStaleAndCommitPvtDataOfOldBlocks(committedPvtData)
 }
 
+// committedPvtdataOfBlock returns the private data of the given collections that the peer holds for the valid
+// transactions of a committed block, see txmgr.PvtdataOfBlockFunc
+func (l *kvLedger) committedPvtdataOfBlock(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
+	pvtdata, err := l.pvtdataStore.GetPvtDataByBlockNum(blockNum, filter)
+	if err != nil || len(pvtdata) == 0 {
+		return nil, err
+	}
+	lastBlockInBootstrapSnapshot := uint64(0)
+	if l.bootSnapshotMetadata != nil {
+		lastBlockInBootstrapSnapshot = l.bootSnapshotMetadata.LastBlockNumber
+	}
+	committedPvtdata, err := filterPvtDataOfInvalidTx(map[uint64][]*ledger.TxPvtData{blockNum: pvtdata}, l.blockStore, lastBlockInBootstrapSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	return committedPvtdata[blockNum], nil
+}
+
 func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
 	return l, nil
 }
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -23,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/msgs"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 	"github.com/hyperledger/fabric/internal/fileutil"
 	"github.com/hyperledger/fabric/protoutil"
@@ -67,6 +69,7 @@ type Provider struct {
 	collElgNotifier      *collElgNotifier
 	stats                *stats
 	fileLock             *leveldbhelper.FileLock
+	crdtResolvers        *crdt_resolver.Registry
 }
 
 // NewProvider instantiates a new Provider.
@@ -107,6 +110,7 @@ func NewProvider(initializer *ledger.Initializer) (pr *Provider, e error) {
 	if err := p.initPvtDataStoreProvider(); err != nil {
 		return nil, err
 	}
+	p.initCRDTResolvers()
 	if err := p.initHistoryDBProvider(); err != nil {
 		return nil, err
 	}
@@ -245,6 +249,33 @@ func (p *Provider) initLedgerStatistics() {
 	p.stats = newStats(p.initializer.MetricsProvider)
 }
 
+func (p *Provider) initCRDTResolvers() {
+	p.crdtResolvers = crdt_resolver.NewRegistry()
+	for resType, resolver := range p.initializer.CRDTResolvers {
+		if _, ok := p.crdtResolvers.Lookup(resType); ok {
+			logger.Warningf("Overriding builtin CRDT resolver for resolution type [%s]", resType)
+		}
+		p.crdtResolvers.Register(resType, resolver)
+	}
+	if crdtConfig := p.initializer.Config.CRDTConfig; crdtConfig != nil {
+		if crdtConfig.EnableTestResolvers {
+			logger.Warning("CRDT resolvers meant for testing are enabled, they must not be used in production")
+			p.crdtResolvers.EnableTestResolvers()
+		}
+		p.crdtResolvers.SetLimits(crdt_resolver.Limits{
+			MaxValueSize:     crdtConfig.MaxValueSize,
+			MaxMergeSteps:    crdtConfig.MaxMergeSteps,
+			MaxBlockDiffSize: crdtConfig.MaxBlockDiffSize,
+			SlowMergeWarning: crdtConfig.SlowMergeWarning,
+		})
+		if crdtConfig.MaxValueSize > 0 || crdtConfig.MaxMergeSteps > 0 || crdtConfig.MaxBlockDiffSize > 0 {
+			logger.Infof("CRDT merge limits enabled, they must be the same on every peer of a channel: maxValueSize=%d, maxMergeSteps=%d, maxBlockDiffSize=%d",
+				crdtConfig.MaxValueSize, crdtConfig.MaxMergeSteps, crdtConfig.MaxBlockDiffSize)
+		}
+	}
+	logger.Infof("Supported CRDT resolution types: %s", strings.Join(p.crdtResolvers.Types(), ", "))
+}
+
 func (p *Provider) initSnapshotDir() error {
 	snapshotsRootDir := p.initializer.Config.SnapshotsConfig.RootDir
 	if !filepath.IsAbs(snapshotsRootDir) {
@@ -374,9 +405,10 @@ func (p *Provider) open(ledgerID string, bootSnapshotMetadata *SnapshotMetadata,
 		bookkeeperProvider:       p.bookkeepingProvider,
 		ccInfoProvider:           p.initializer.DeployedChaincodeInfoProvider,
 		ccLifecycleEventProvider: p.initializer.ChaincodeLifecycleEventProvider,
-		stats:                    p.stats.ledgerStats(ledgerID),
+		stats:                    p.stats.ledgerStats(ledgerID, p.crdtResolvers),
 		customTxProcessors:       p.initializer.CustomTxProcessors,
 		hashProvider:             p.initializer.HashProvider,
+		crdtResolvers:            p.crdtResolvers,
 		config:                   p.initializer.Config,
 		bootSnapshotMetadata:     bootSnapshotMetadata,
 		initializingFromSnapshot: initializingFromSnapshot,
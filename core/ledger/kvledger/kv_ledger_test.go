@@ -471,7 +471,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	_, _, _, err = ledger3.(*kvLedger).txmgr.ValidateAndPrepare(blockAndPvtdata4, true)
 	require.NoError(t, err)
 	require.NoError(t, ledger3.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata4, nil))
-	require.NoError(t, ledger3.(*kvLedger).historyDB.Commit(blockAndPvtdata4.Block))
+	require.NoError(t, ledger3.(*kvLedger).historyDB.Commit(blockAndPvtdata4.Block, nil))
 
 	checkBCSummaryForTest(t, ledger3,
 		&bcSummary{
@@ -517,6 +517,77 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	)
 }
 
+func TestKVLedgerCRDTHistory(t *testing.T) {
+	conf := testConfig(t)
+	// the values of the last block only are retained
+	conf.CRDTConfig = &ledger.CRDTConfig{ValuesRetention: 1}
+	provider1 := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider1.Close()
+
+	testLedgerid := "testLedger"
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerid, false)
+	ledger1, err := provider1.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+
+	add := func(l ledger.PeerLedger, diff string) []byte {
+		simulator, err := l.NewTxSimulator(util.GenerateUUID())
+		require.NoError(t, err)
+		require.NoError(t, simulator.SetCRDT("ns1", "IntAdd", "counter", []byte(diff), nil))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		return pubSimBytes
+	}
+
+	// the history records the values the validator merged right after each transaction
+	block1 := bg.NextBlock([][]byte{add(ledger1, "5")})
+	require.NoError(t, ledger1.CommitLegacy(&ledger.BlockAndPvtData{Block: block1}, &ledger.CommitOptions{}))
+	block2 := bg.NextBlock([][]byte{add(ledger1, "3"), add(ledger1, "2")})
+	require.NoError(t, ledger1.CommitLegacy(&ledger.BlockAndPvtData{Block: block2}, &ledger.CommitOptions{}))
+
+	// the peer fails after committing the next two blocks to the state DB but before the history DB
+	for _, diff := range []string{"1", "4"} {
+		blockAndPvtdata := &ledger.BlockAndPvtData{Block: bg.NextBlock([][]byte{add(ledger1, diff)})}
+		_, _, _, err = ledger1.(*kvLedger).txmgr.ValidateAndPrepare(blockAndPvtdata, true)
+		require.NoError(t, err)
+		require.NoError(t, ledger1.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata, nil))
+		require.NoError(t, ledger1.(*kvLedger).txmgr.Commit())
+	}
+	ledger1.Close()
+	provider1.Close()
+
+	// the history DB is recovered with the values the state DB recorded, those of
+	// block 3 being no longer retained
+	provider2 := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider2.Close()
+	ledger2, err := provider2.Open(testLedgerid)
+	require.NoError(t, err)
+	defer ledger2.Close()
+
+	qhistory, err := ledger2.NewHistoryQueryExecutor()
+	require.NoError(t, err)
+	itr, err := qhistory.GetHistoryForKey("ns1", "counter")
+	require.NoError(t, err)
+	defer itr.Close()
+	type expectedModification struct {
+		value       string
+		unavailable bool
+	}
+	expected := []expectedModification{{"15", false}, {"", true}, {"10", false}, {"8", false}, {"5", false}}
+	for _, e := range expected {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		kmod := res.(*queryresult.KeyModification)
+		require.Equal(t, e.value, string(kmod.Value))
+		require.Equal(t, e.unavailable, kmod.CrdtValueUnavailable)
+	}
+	res, err := itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
 func TestLedgerWithCouchDbEnabledWithBinaryAndJSONData(t *testing.T) {
 	conf := testConfig(t)
 	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
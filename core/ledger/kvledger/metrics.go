@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
 )
 
@@ -18,6 +19,11 @@ type stats struct {
 	blockAndPvtdataStoreCommitTime metrics.Histogram
 	statedbCommitTime              metrics.Histogram
 	transactionsCount              metrics.Counter
+	crdtMergesApplied              metrics.Counter
+	crdtMergesFailed               metrics.Counter
+	crdtMergeTime                  metrics.Histogram
+	crdtValueSize                  metrics.Histogram
+	crdtHotKeys                    metrics.Histogram
 }
 
 func newStats(metricsProvider metrics.Provider) *stats {
@@ -26,17 +32,23 @@ func newStats(metricsProvider metrics.Provider) *stats {
 	stats.blockAndPvtdataStoreCommitTime = metricsProvider.NewHistogram(blockAndPvtdataStoreCommitTimeOpts)
 	stats.statedbCommitTime = metricsProvider.NewHistogram(statedbCommitTimeOpts)
 	stats.transactionsCount = metricsProvider.NewCounter(transactionCountOpts)
+	stats.crdtMergesApplied = metricsProvider.NewCounter(crdtMergesAppliedOpts)
+	stats.crdtMergesFailed = metricsProvider.NewCounter(crdtMergesFailedOpts)
+	stats.crdtMergeTime = metricsProvider.NewHistogram(crdtMergeTimeOpts)
+	stats.crdtValueSize = metricsProvider.NewHistogram(crdtValueSizeOpts)
+	stats.crdtHotKeys = metricsProvider.NewHistogram(crdtHotKeysOpts)
 	return stats
 }
 
 type ledgerStats struct {
-	stats    *stats
-	ledgerid string
+	stats         *stats
+	ledgerid      string
+	crdtResolvers *crdt_resolver.Registry
 }
 
-func (s *stats) ledgerStats(ledgerid string) *ledgerStats {
+func (s *stats) ledgerStats(ledgerid string, crdtResolvers *crdt_resolver.Registry) *ledgerStats {
 	return &ledgerStats{
-		s, ledgerid,
+		s, ledgerid, crdtResolvers,
 	}
 }
 
@@ -75,6 +87,85 @@ func (s *ledgerStats) updateTransactionsStats(
 	}
 }
 
+// updateCRDTStats updates the metrics of the merges of the public CRDT payloads of a block. The size of the value of
+// a key is observed once per block, as is the number of hot keys of a namespace, i.e. the keys merged by more than
+// one valid transaction of the block
+func (s *ledgerStats) updateCRDTStats(txstatsInfo []*validation.TxStatInfo) {
+	type nsKey struct {
+		ns, key string
+	}
+	// the keys merged by the valid transactions, in the order of their first merge, with their last resolution type
+	var mergedKeys []nsKey
+	resTypes := map[nsKey]string{}
+	mergingTxs := map[nsKey]int{}
+	for _, txstat := range txstatsInfo {
+		txKeys := map[nsKey]struct{}{}
+		for _, merge := range txstat.CRDTMerges {
+			labels := []string{
+				"channel", s.ledgerid,
+				"namespace", merge.Namespace,
+				"resolution_type", s.resolutionTypeLabel(merge.ResolutionType),
+			}
+			s.stats.crdtMergeTime.With(labels...).Observe(merge.MergeTime.Seconds())
+			if merge.Failure != "" {
+				s.stats.crdtMergesFailed.With(append(labels, "reason", merge.Failure)...).Add(1)
+				continue
+			}
+			s.stats.crdtMergesApplied.With(labels...).Add(1)
+
+			key := nsKey{merge.Namespace, merge.Key}
+			if _, ok := resTypes[key]; !ok {
+				mergedKeys = append(mergedKeys, key)
+			}
+			resTypes[key] = s.resolutionTypeLabel(merge.ResolutionType)
+			if _, ok := txKeys[key]; !ok {
+				txKeys[key] = struct{}{}
+				mergingTxs[key]++
+			}
+		}
+	}
+	if len(mergedKeys) == 0 {
+		return
+	}
+
+	valueSizes := map[nsKey]int{}
+	for _, txstat := range txstatsInfo {
+		for _, value := range txstat.CRDTValues {
+			valueSizes[nsKey{value.Namespace, value.Key}] = len(value.Value)
+		}
+	}
+	var namespaces []string
+	hotKeys := map[string]int{}
+	for _, key := range mergedKeys {
+		if size, ok := valueSizes[key]; ok {
+			s.stats.crdtValueSize.With(
+				"channel", s.ledgerid,
+				"namespace", key.ns,
+				"resolution_type", resTypes[key],
+			).Observe(float64(size))
+		}
+		if _, ok := hotKeys[key.ns]; !ok {
+			namespaces = append(namespaces, key.ns)
+			hotKeys[key.ns] = 0
+		}
+		if mergingTxs[key] > 1 {
+			hotKeys[key.ns]++
+		}
+	}
+	for _, ns := range namespaces {
+		s.stats.crdtHotKeys.With("channel", s.ledgerid, "namespace", ns).Observe(float64(hotKeys[ns]))
+	}
+}
+
+// resolutionTypeLabel returns the label of a resolution type. The resolution type of a payload is chosen by
+// the client, hence the types that are not registered share the same label to bound the number of series
+func (s *ledgerStats) resolutionTypeLabel(resType string) string {
+	if _, ok := s.crdtResolvers.Lookup(resType); !ok {
+		return "unknown"
+	}
+	return resType
+}
+
 var (
 	blockProcessingTimeOpts = metrics.HistogramOpts{
 		Namespace:    "ledger",
@@ -114,4 +205,52 @@ var (
 		LabelNames:   []string{"channel", "transaction_type", "chaincode", "validation_code"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{transaction_type}.%{chaincode}.%{validation_code}",
 	}
+
+	crdtMergesAppliedOpts = metrics.CounterOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "crdt_merges_applied",
+		Help:         "Number of CRDT payloads merged by valid transactions.",
+		LabelNames:   []string{"channel", "namespace", "resolution_type"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{namespace}.%{resolution_type}",
+	}
+
+	crdtMergesFailedOpts = metrics.CounterOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "crdt_merges_failed",
+		Help:         "Number of CRDT payloads that failed to merge, by the validation code of the transaction.",
+		LabelNames:   []string{"channel", "namespace", "resolution_type", "reason"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{namespace}.%{resolution_type}.%{reason}",
+	}
+
+	crdtMergeTimeOpts = metrics.HistogramOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "crdt_merge_time",
+		Help:         "Time taken in seconds to merge a CRDT payload into the value of a key.",
+		LabelNames:   []string{"channel", "namespace", "resolution_type"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{namespace}.%{resolution_type}",
+		Buckets:      []float64{0.00001, 0.0001, 0.001, 0.005, 0.01, 0.05, 0.1, 1},
+	}
+
+	crdtValueSizeOpts = metrics.HistogramOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "crdt_value_size",
+		Help:         "Size in bytes of the values of the CRDT keys merged by a block.",
+		LabelNames:   []string{"channel", "namespace", "resolution_type"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{namespace}.%{resolution_type}",
+		Buckets:      []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576},
+	}
+
+	crdtHotKeysOpts = metrics.HistogramOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "crdt_hot_keys",
+		Help:         "Number of distinct CRDT keys merged by more than one transaction of a block.",
+		LabelNames:   []string{"channel", "namespace"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{namespace}",
+		Buckets:      []float64{0, 1, 5, 10, 50, 100, 500, 1000},
+	}
 )
@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/stretchr/testify/require"
@@ -141,12 +142,106 @@ func TestStatsBlockCommit(t *testing.T) {
 	)
 }
 
+func TestStatsCRDTMerges(t *testing.T) {
+	testMetricProvider := testutilConstructMetricProvider()
+	stats := newStats(testMetricProvider.fakeProvider).ledgerStats("ledger1", crdt_resolver.NewRegistry())
+
+	stats.updateCRDTStats([]*validation.TxStatInfo{
+		{
+			ValidationCode: peer.TxValidationCode_VALID,
+			CRDTMerges: []*validation.CRDTMergeStat{
+				{Namespace: "ns1", Key: "CRDTFIELD_k1", ResolutionType: "IntAdd", MergeTime: time.Millisecond},
+				{Namespace: "ns1", Key: "CRDTFIELD_k2", ResolutionType: "IntAdd", MergeTime: time.Millisecond},
+			},
+		},
+		{
+			ValidationCode: peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+			CRDTMerges: []*validation.CRDTMergeStat{
+				{Namespace: "ns1", Key: "CRDTFIELD_k1", ResolutionType: "IntAdd", Failure: peer.TxValidationCode_CRDT_MALFORMED_DIFF.String()},
+			},
+		},
+		{
+			ValidationCode: peer.TxValidationCode_VALID,
+			CRDTMerges: []*validation.CRDTMergeStat{
+				{Namespace: "ns1", Key: "CRDTFIELD_k1", ResolutionType: "IntAdd", MergeTime: 2 * time.Millisecond},
+			},
+			CRDTValues: []*peer.CRDTValue{
+				{Namespace: "ns1", Key: "CRDTFIELD_k1", Value: []byte("12")},
+				{Namespace: "ns1", Key: "CRDTFIELD_k2", Value: []byte("345")},
+			},
+		},
+	})
+
+	applied := testMetricProvider.fakeCRDTMergesApplied
+	require.Equal(t, 3, applied.AddCallCount())
+	require.Equal(t,
+		[]string{"channel", "ledger1", "namespace", "ns1", "resolution_type", "IntAdd"},
+		applied.WithArgsForCall(0),
+	)
+
+	failed := testMetricProvider.fakeCRDTMergesFailed
+	require.Equal(t, 1, failed.AddCallCount())
+	require.Equal(t,
+		[]string{
+			"channel", "ledger1",
+			"namespace", "ns1",
+			"resolution_type", "IntAdd",
+			"reason", peer.TxValidationCode_CRDT_MALFORMED_DIFF.String(),
+		},
+		failed.WithArgsForCall(0),
+	)
+
+	mergeTime := testMetricProvider.fakeCRDTMergeTimeHist
+	require.Equal(t, 4, mergeTime.ObserveCallCount())
+	require.Equal(t, 0.002, mergeTime.ObserveArgsForCall(3))
+
+	valueSize := testMetricProvider.fakeCRDTValueSizeHist
+	require.Equal(t, 2, valueSize.ObserveCallCount())
+	require.Equal(t, float64(2), valueSize.ObserveArgsForCall(0))
+	require.Equal(t, float64(3), valueSize.ObserveArgsForCall(1))
+
+	// only CRDTFIELD_k1 is merged by both valid transactions
+	hotKeys := testMetricProvider.fakeCRDTHotKeysHist
+	require.Equal(t, 1, hotKeys.ObserveCallCount())
+	require.Equal(t, []string{"channel", "ledger1", "namespace", "ns1"}, hotKeys.WithArgsForCall(0))
+	require.Equal(t, float64(1), hotKeys.ObserveArgsForCall(0))
+
+	// a block without CRDT merges does not observe the per-block metrics
+	stats.updateCRDTStats([]*validation.TxStatInfo{{ValidationCode: peer.TxValidationCode_VALID}})
+	require.Equal(t, 2, valueSize.ObserveCallCount())
+	require.Equal(t, 1, hotKeys.ObserveCallCount())
+
+	// the resolution types that are not registered share a label
+	stats.updateCRDTStats([]*validation.TxStatInfo{
+		{
+			ValidationCode: peer.TxValidationCode_CRDT_UNKNOWN_TYPE,
+			CRDTMerges: []*validation.CRDTMergeStat{
+				{Namespace: "ns1", Key: "k3", ResolutionType: "Bogus-1", Failure: peer.TxValidationCode_CRDT_UNKNOWN_TYPE.String()},
+			},
+		},
+	})
+	require.Equal(t,
+		[]string{
+			"channel", "ledger1",
+			"namespace", "ns1",
+			"resolution_type", "unknown",
+			"reason", peer.TxValidationCode_CRDT_UNKNOWN_TYPE.String(),
+		},
+		failed.WithArgsForCall(1),
+	)
+}
+
 type testMetricProvider struct {
 	fakeProvider                              *metricsfakes.Provider
 	fakeBlockProcessingTimeHist               *metricsfakes.Histogram
 	fakeBlockstorageCommitWithPvtDataTimeHist *metricsfakes.Histogram
 	fakeStatedbCommitTimeHist                 *metricsfakes.Histogram
 	fakeTransactionsCount                     *metricsfakes.Counter
+	fakeCRDTMergesApplied                     *metricsfakes.Counter
+	fakeCRDTMergesFailed                      *metricsfakes.Counter
+	fakeCRDTMergeTimeHist                     *metricsfakes.Histogram
+	fakeCRDTValueSizeHist                     *metricsfakes.Histogram
+	fakeCRDTHotKeysHist                       *metricsfakes.Histogram
 }
 
 func testutilConstructMetricProvider() *testMetricProvider {
@@ -155,6 +250,11 @@ func testutilConstructMetricProvider() *testMetricProvider {
 	fakeBlockstorageCommitWithPvtDataTimeHist := testutilConstructHist()
 	fakeStatedbCommitTimeHist := testutilConstructHist()
 	fakeTransactionsCount := testutilConstructCounter()
+	fakeCRDTMergesApplied := testutilConstructCounter()
+	fakeCRDTMergesFailed := testutilConstructCounter()
+	fakeCRDTMergeTimeHist := testutilConstructHist()
+	fakeCRDTValueSizeHist := testutilConstructHist()
+	fakeCRDTHotKeysHist := testutilConstructHist()
 	fakeProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
 		// return a gauge for metrics in common/ledger
 		return testutilConstructGauge()
@@ -167,6 +267,12 @@ func testutilConstructMetricProvider() *testMetricProvider {
 			return fakeBlockstorageCommitWithPvtDataTimeHist
 		case statedbCommitTimeOpts.Name:
 			return fakeStatedbCommitTimeHist
+		case crdtMergeTimeOpts.Name:
+			return fakeCRDTMergeTimeHist
+		case crdtValueSizeOpts.Name:
+			return fakeCRDTValueSizeHist
+		case crdtHotKeysOpts.Name:
+			return fakeCRDTHotKeysHist
 		default:
 			// return a histogram for metrics in common/ledger
 			return testutilConstructHist()
@@ -177,6 +283,10 @@ func testutilConstructMetricProvider() *testMetricProvider {
 		switch opts.Name {
 		case transactionCountOpts.Name:
 			return fakeTransactionsCount
+		case crdtMergesAppliedOpts.Name:
+			return fakeCRDTMergesApplied
+		case crdtMergesFailedOpts.Name:
+			return fakeCRDTMergesFailed
 		}
 		return nil
 	}
@@ -186,6 +296,11 @@ func testutilConstructMetricProvider() *testMetricProvider {
 		fakeBlockstorageCommitWithPvtDataTimeHist,
 		fakeStatedbCommitTimeHist,
 		fakeTransactionsCount,
+		fakeCRDTMergesApplied,
+		fakeCRDTMergesFailed,
+		fakeCRDTMergeTimeHist,
+		fakeCRDTValueSizeHist,
+		fakeCRDTHotKeysHist,
 	}
 }
 
@@ -16,7 +16,11 @@ limitations under the License.
 
 package kvledger
 
-import "github.com/hyperledger/fabric/core/ledger"
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
+)
 
 type recoverable interface {
 	// ShouldRecover return whether recovery is need.
@@ -33,3 +37,21 @@ type recoverer struct {
 	nextRequiredBlock uint64
 	recoverable       recoverable
 }
+
+// historyRecoverable recommits the lost blocks to the history database along with the CRDT values
+// the transaction manager recorded for them, which are recorded as unknown once no longer retained
+type historyRecoverable struct {
+	*history.DB
+	txmgr *txmgr.LockBasedTxMgr
+}
+
+func (r *historyRecoverable) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error {
+	crdtValues, err := r.txmgr.GetCRDTValuesByNum(blockAndPvtdata.Block.Header.Number)
+	if _, ok := err.(*txmgr.CRDTValuesNotRetainedError); ok {
+		logger.Warningf("Recommitting block [%d] to history database without the values of its CRDT keys: %s",
+			blockAndPvtdata.Block.Header.Number, err)
+	} else if err != nil {
+		return err
+	}
+	return r.DB.CommitLostBlock(blockAndPvtdata, crdtValues)
+}
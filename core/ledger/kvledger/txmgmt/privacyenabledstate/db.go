@@ -169,6 +169,47 @@ func (s *DB) LoadCommittedVersionsOfPubAndHashedKeys(pubKeys []*statedb.Composit
 	return nil
 }
 
+// LoadCommittedValuesOfPubAndHashedKeys loads committed value of given public and hashed states
+func (s *DB) LoadCommittedValuesOfPubAndHashedKeys(pubKeys []*statedb.CompositeKey,
+	hashedKeys []*HashedCompositeKey) error {
+	bulkOptimizable, ok := s.VersionedDB.(statedb.BulkOptimizable)
+	if !ok {
+		return nil
+	}
+	for _, key := range hashedKeys {
+		pubKeys = append(pubKeys, &statedb.CompositeKey{
+			Namespace: deriveHashedDataNs(key.Namespace, key.CollectionName),
+			Key:       s.keyHashStr([]byte(key.KeyHash)),
+		})
+	}
+	return bulkOptimizable.LoadCommittedValues(pubKeys)
+}
+
+// GetCommittedState gets the committed value of a public key, from the cache populated
+// by LoadCommittedValuesOfPubAndHashedKeys if the key was loaded
+func (s *DB) GetCommittedState(namespace, key string) (*statedb.VersionedValue, error) {
+	if bulkOptimizable, ok := s.VersionedDB.(statedb.BulkOptimizable); ok {
+		if vv, found := bulkOptimizable.GetCachedValue(namespace, key); found {
+			return vv, nil
+		}
+	}
+	return s.GetState(namespace, key)
+}
+
+// GetCommittedValueHash gets the committed value hash of a private data item identified by a tuple
+// <namespace, collection, keyHash>, from the cache populated by LoadCommittedValuesOfPubAndHashedKeys
+// if the key was loaded
+func (s *DB) GetCommittedValueHash(namespace, collection string, keyHash []byte) (*statedb.VersionedValue, error) {
+	return s.GetCommittedState(deriveHashedDataNs(namespace, collection), s.keyHashStr(keyHash))
+}
+
+func (s *DB) keyHashStr(keyHash []byte) string {
+	if !s.BytesKeySupported() {
+		return base64.StdEncoding.EncodeToString(keyHash)
+	}
+	return string(keyHash)
+}
+
 // ClearCachedVersions clears the version cache
 func (s *DB) ClearCachedVersions() {
 	bulkOptimizable, ok := s.VersionedDB.(statedb.BulkOptimizable)
@@ -49,6 +49,7 @@ type collHashRwBuilder struct {
 	readMap          map[string]*kvrwset.KVReadHash
 	writeMap         map[string]*kvrwset.KVWriteHash
 	metadataWriteMap map[string]*kvrwset.KVMetadataWriteHash
+	crdt             []*kvrwset.CRDTPayloadHash
 	pvtDataHash      []byte
 }
 
@@ -61,6 +62,7 @@ type collPvtRwBuilder struct {
 	collectionName   string
 	writeMap         map[string]*kvrwset.KVWrite
 	metadataWriteMap map[string]*kvrwset.KVMetadataWrite
+	crdt             []*kvrwset.CRDTPayload
 }
 
 type rangeQueryKey struct {
@@ -86,12 +88,44 @@ func (b *RWSetBuilder) AddToWriteSet(ns string, key string, value []byte) {
 	nsPubRwBuilder.writeMap[key] = newKVWrite(key, value)
 }
 
-func (b *RWSetBuilder) AddToCRDT(ns string, resType string, key string, value []byte) {
+func (b *RWSetBuilder) AddToCRDT(ns string, resType string, key string, value []byte, predicates []*kvrwset.CRDTPredicate) {
 	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
-	nsPubRwBuilder.CRDT = append(nsPubRwBuilder.CRDT, newCRDTData(resType, key, value))
+	nsPubRwBuilder.CRDT = append(nsPubRwBuilder.CRDT, newCRDTData(resType, key, value, predicates))
 	// nsPubRwBuilder.writeMap[key] = newKVWrite(key, value)
 }
 
+// AddResetToCRDT adds a CRDT payload that resets the key to the given value, i.e. the value is merged
+// into an empty value, to the CRDT payloads
+func (b *RWSetBuilder) AddResetToCRDT(ns string, resType string, key string, value []byte) {
+	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
+	payload := newCRDTData(resType, key, value, nil)
+	payload.Operation = kvrwset.CRDTPayload_RESET
+	nsPubRwBuilder.CRDT = append(nsPubRwBuilder.CRDT, payload)
+}
+
+// AddDeleteToCRDT adds a CRDT payload that deletes the key to the CRDT payloads
+func (b *RWSetBuilder) AddDeleteToCRDT(ns string, key string) {
+	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
+	payload := newCRDTData("", key, nil, nil)
+	payload.Operation = kvrwset.CRDTPayload_DELETE
+	nsPubRwBuilder.CRDT = append(nsPubRwBuilder.CRDT, payload)
+}
+
+// GetCRDTPayloads returns the CRDT payloads added for the key, in the order they were added
+func (b *RWSetBuilder) GetCRDTPayloads(ns string, key string) []*kvrwset.CRDTPayload {
+	nsPubRwBuilder, ok := b.pubRwBuilderMap[ns]
+	if !ok {
+		return nil
+	}
+	var payloads []*kvrwset.CRDTPayload
+	for _, payload := range nsPubRwBuilder.CRDT {
+		if payload.Key == key {
+			payloads = append(payloads, payload)
+		}
+	}
+	return payloads
+}
+
 // AddToMetadataWriteSet adds a metadata to a key in the write-set
 // A nil/empty-map for 'metadata' parameter indicates the delete of the metadata
 func (b *RWSetBuilder) AddToMetadataWriteSet(ns, key string, metadata map[string][]byte) {
@@ -123,6 +157,35 @@ func (b *RWSetBuilder) AddToPvtAndHashedWriteSet(ns string, coll string, key str
 	b.getOrCreateCollHashedRwBuilder(ns, coll).writeMap[key] = kvWriteHash
 }
 
+// AddToPvtAndHashedCRDT adds a CRDT payload on a key of a collection to the private write-set
+// and its hashed representation to the hashed write-set
+func (b *RWSetBuilder) AddToPvtAndHashedCRDT(ns string, coll string, resType string, key string, value []byte) {
+	crdt, crdtHash := newPvtCRDTDataAndHash(resType, key, value)
+	collPvtRwBuilder := b.getOrCreateCollPvtRwBuilder(ns, coll)
+	collPvtRwBuilder.crdt = append(collPvtRwBuilder.crdt, crdt)
+	collHashRwBuilder := b.getOrCreateCollHashedRwBuilder(ns, coll)
+	collHashRwBuilder.crdt = append(collHashRwBuilder.crdt, crdtHash)
+}
+
+// GetPvtCRDTPayloads returns the CRDT payloads added for the key of the collection, in the order they were added
+func (b *RWSetBuilder) GetPvtCRDTPayloads(ns string, coll string, key string) []*kvrwset.CRDTPayload {
+	nsPvtRwBuilder, ok := b.pvtRwBuilderMap[ns]
+	if !ok {
+		return nil
+	}
+	collPvtRwBuilder, ok := nsPvtRwBuilder.collPvtRwBuilders[coll]
+	if !ok {
+		return nil
+	}
+	var payloads []*kvrwset.CRDTPayload
+	for _, payload := range collPvtRwBuilder.crdt {
+		if payload.Key == key {
+			payloads = append(payloads, payload)
+		}
+	}
+	return payloads
+}
+
 // AddToHashedWriteSetPurge adds a purge key to the hashed write-set
 func (b *RWSetBuilder) AddToHashedWriteSetPurge(ns string, coll string, key string) {
 	kvWriteHashPurge := newKVWriteHashPurge(key)
@@ -268,9 +331,10 @@ func (b *collHashRwBuilder) build() *CollHashedRwSet {
 	return &CollHashedRwSet{
 		CollectionName: b.collName,
 		HashedRwSet: &kvrwset.HashedRWSet{
-			HashedReads:    readSet,
-			HashedWrites:   writeSet,
-			MetadataWrites: metadataWriteSet,
+			HashedReads:       readSet,
+			HashedWrites:      writeSet,
+			MetadataWrites:    metadataWriteSet,
+			CrdtPayloadHashes: b.crdt,
 		},
 		PvtRwSetHash: b.pvtDataHash,
 	}
@@ -286,6 +350,7 @@ func (b *collPvtRwBuilder) build() *CollPvtRwSet {
 		KvRwSet: &kvrwset.KVRWSet{
 			Writes:         writeSet,
 			MetadataWrites: metadataWriteSet,
+			CrdtPayload:    b.crdt,
 		},
 	}
 }
@@ -352,6 +417,7 @@ func newCollHashRwBuilder(collName string) *collHashRwBuilder {
 		make(map[string]*kvrwset.KVWriteHash),
 		make(map[string]*kvrwset.KVMetadataWriteHash),
 		nil,
+		nil,
 	}
 }
 
@@ -360,6 +426,7 @@ func newCollPvtRwBuilder(collName string) *collPvtRwBuilder {
 		collName,
 		make(map[string]*kvrwset.KVWrite),
 		make(map[string]*kvrwset.KVMetadataWrite),
+		nil,
 	}
 }
 
@@ -243,6 +243,75 @@ func TestTxSimulationResultWithPvtData(t *testing.T) {
 	require.Equal(t, expectedPubRWSet, actualSimRes.PubSimulationResults)
 }
 
+func TestTxSimulationResultWithPvtCRDT(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToPvtAndHashedCRDT("ns1", "coll1", "GCounter", "key1", []byte("5"))
+	rwSetBuilder.AddToPvtAndHashedCRDT("ns1", "coll1", "GCounter", "key1", []byte("3"))
+	rwSetBuilder.AddToPvtAndHashedCRDT("ns1", "coll1", "ORSet", "key2", []byte(`{"add":["a"]}`))
+
+	require.Equal(t,
+		[]*kvrwset.CRDTPayload{
+			newCRDTData("GCounter", "key1", []byte("5"), nil),
+			newCRDTData("GCounter", "key1", []byte("3"), nil),
+		},
+		rwSetBuilder.GetPvtCRDTPayloads("ns1", "coll1", "key1"),
+	)
+	require.Nil(t, rwSetBuilder.GetPvtCRDTPayloads("ns1", "coll2", "key1"))
+	require.Nil(t, rwSetBuilder.GetCRDTPayloads("ns1", "key1"))
+
+	actualSimRes, err := rwSetBuilder.GetTxSimulationResults()
+	require.NoError(t, err)
+
+	// the plaintext payloads are present in the private rwset only
+	pvtNs1Coll1 := &kvrwset.KVRWSet{
+		CrdtPayload: []*kvrwset.CRDTPayload{
+			newCRDTData("GCounter", "key1", []byte("5"), nil),
+			newCRDTData("GCounter", "key1", []byte("3"), nil),
+			newCRDTData("ORSet", "key2", []byte(`{"add":["a"]}`), nil),
+		},
+	}
+	expectedPvtRWSet := &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace: "ns1",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+					{
+						CollectionName: "coll1",
+						Rwset:          serializeTestProtoMsg(t, pvtNs1Coll1),
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, expectedPvtRWSet, actualSimRes.PvtSimulationResults)
+
+	hashedNs1Coll1 := &kvrwset.HashedRWSet{
+		CrdtPayloadHashes: []*kvrwset.CRDTPayloadHash{
+			{ResolutionType: "GCounter", KeyHash: util.ComputeStringHash("key1"), DataHash: util.ComputeHash([]byte("5"))},
+			{ResolutionType: "GCounter", KeyHash: util.ComputeStringHash("key1"), DataHash: util.ComputeHash([]byte("3"))},
+			{ResolutionType: "ORSet", KeyHash: util.ComputeStringHash("key2"), DataHash: util.ComputeHash([]byte(`{"add":["a"]}`))},
+		},
+	}
+	expectedPubRWSet := &rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{
+			{
+				Namespace: "ns1",
+				Rwset:     serializeTestProtoMsg(t, &kvrwset.KVRWSet{}),
+				CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{
+					{
+						CollectionName: "coll1",
+						HashedRwset:    serializeTestProtoMsg(t, hashedNs1Coll1),
+						PvtRwsetHash:   util.ComputeHash(serializeTestProtoMsg(t, pvtNs1Coll1)),
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, expectedPubRWSet, actualSimRes.PubSimulationResults)
+}
+
 func TestTxSimulationResultWithMetadata(t *testing.T) {
 	rwSetBuilder := NewRWSetBuilder()
 	// public rws ns1
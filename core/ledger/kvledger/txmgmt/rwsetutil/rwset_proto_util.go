@@ -348,8 +348,13 @@ func newKVWrite(key string, value []byte) *kvrwset.KVWrite {
 	return &kvrwset.KVWrite{Key: key, IsDelete: len(value) == 0, Value: value}
 }
 
-func newCRDTData(resType string, key string, value []byte) *kvrwset.CRDTPayload {
-	return &kvrwset.CRDTPayload{ResolutionType: resType, Key: key, Data: value}
+func newCRDTData(resType string, key string, value []byte, predicates []*kvrwset.CRDTPredicate) *kvrwset.CRDTPayload {
+	return &kvrwset.CRDTPayload{ResolutionType: resType, Key: key, Data: value, Predicates: predicates}
+}
+
+func newPvtCRDTDataAndHash(resType string, key string, value []byte) (*kvrwset.CRDTPayload, *kvrwset.CRDTPayloadHash) {
+	crdt := newCRDTData(resType, key, value, nil)
+	return crdt, &kvrwset.CRDTPayloadHash{ResolutionType: resType, KeyHash: util.ComputeStringHash(key), DataHash: util.ComputeHash(value)}
 }
 
 func newPvtKVReadHash(key string, version *version.Height) *kvrwset.KVReadHash {
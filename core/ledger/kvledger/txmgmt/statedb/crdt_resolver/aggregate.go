@@ -0,0 +1,141 @@
+package crdt_resolver
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Accumulator merges a sequence of diffs into the value of a key without parsing
+// and encoding the value for every diff. Merging a diff into an accumulator yields
+// the same value and the same error as merging it with the resolver of the type
+type Accumulator interface {
+	// Add merges the diff into the accumulated value. The accumulated value is
+	// unchanged if an error is returned
+	Add(diffValue []byte) error
+	// Value returns the encoded accumulated value
+	Value() []byte
+}
+
+// accumulatorFunc constructs an accumulator holding the current value of a key
+type accumulatorFunc func(curValue []byte) (Accumulator, error)
+
+// builtinAccumulators returns the accumulators of the builtin resolvers whose
+// diffs are numbers added to the value of the key, keyed by their resolution type
+func builtinAccumulators() map[string]accumulatorFunc {
+	return map[string]accumulatorFunc{
+		"IntAdd":     newIntAddAccumulator,
+		"BigIntAdd":  numericAccumulatorFunc(false, true),
+		"BigIntSub":  numericAccumulatorFunc(true, true),
+		"DecimalAdd": numericAccumulatorFunc(false, false),
+		"DecimalSub": numericAccumulatorFunc(true, false),
+	}
+}
+
+type intAddAccumulator struct {
+	value int
+}
+
+func newIntAddAccumulator(curValue []byte) (Accumulator, error) {
+	acc := &intAddAccumulator{}
+	if len(curValue) != 0 {
+		var err error
+		if acc.value, err = strconv.Atoi(string(curValue)); err != nil {
+			return nil, typeMismatch(err)
+		}
+	}
+	return acc, nil
+}
+
+func (a *intAddAccumulator) Add(diffValue []byte) error {
+	diff, err := strconv.Atoi(string(diffValue))
+	if err != nil {
+		return malformedDiff(err)
+	}
+	res, err := add(a.value, diff)
+	if err != nil {
+		return err
+	}
+	a.value = res
+	return nil
+}
+
+func (a *intAddAccumulator) Value() []byte {
+	return []byte(strconv.Itoa(a.value))
+}
+
+// numericAccumulator accumulates the diffs of the arbitrary-precision resolvers, see numericResolve
+type numericAccumulator struct {
+	value    *decimal
+	subtract bool
+	integral bool
+}
+
+func numericAccumulatorFunc(subtract bool, integral bool) accumulatorFunc {
+	return func(curValue []byte) (Accumulator, error) {
+		acc := &numericAccumulator{value: &decimal{unscaled: new(big.Int)}, subtract: subtract, integral: integral}
+		if len(curValue) != 0 {
+			var err error
+			if acc.value, err = acc.parse(string(curValue)); err != nil {
+				return nil, typeMismatch(err)
+			}
+		}
+		return acc, nil
+	}
+}
+
+func (a *numericAccumulator) parse(s string) (*decimal, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return nil, err
+	}
+	if a.integral && d.scale != 0 {
+		return nil, fmt.Errorf("Invalid integer %q", s)
+	}
+	return d, nil
+}
+
+func (a *numericAccumulator) Add(diffValue []byte) error {
+	diff, err := parseNumericDiff(diffValue)
+	if err != nil {
+		return malformedDiff(err)
+	}
+	delta, err := a.parse(diff.Value)
+	if err != nil {
+		return malformedDiff(err)
+	}
+	if a.subtract {
+		if delta.unscaled.Sign() < 0 {
+			return mergeErrorf(MalformedDiff, "Can't have negative diff")
+		}
+		delta = &decimal{unscaled: new(big.Int).Neg(delta.unscaled), scale: delta.scale}
+	}
+
+	res := a.value.add(delta)
+
+	if diff.Min != "" {
+		min, err := a.parse(diff.Min)
+		if err != nil {
+			return malformedDiff(err)
+		}
+		if res.cmp(min) < 0 {
+			return mergeErrorf(Underflow, "Result %s is below the lower bound %s", res, min)
+		}
+	}
+	if diff.Max != "" {
+		max, err := a.parse(diff.Max)
+		if err != nil {
+			return malformedDiff(err)
+		}
+		if res.cmp(max) > 0 {
+			return mergeErrorf(Overflow, "Result %s is above the upper bound %s", res, max)
+		}
+	}
+
+	a.value = res
+	return nil
+}
+
+func (a *numericAccumulator) Value() []byte {
+	return []byte(a.value.String())
+}
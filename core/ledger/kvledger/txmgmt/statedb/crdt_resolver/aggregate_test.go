@@ -0,0 +1,74 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/handlers/crdt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccumulators(t *testing.T) {
+	tests := []struct {
+		resType string
+		cur     string
+		diffs   []string
+	}{
+		{resType: "IntAdd", diffs: []string{"1", "2", "x", "-10", "9223372036854775807", "4"}},
+		{resType: "IntAdd", cur: "abc", diffs: []string{"1"}},
+		{resType: "BigIntAdd", cur: "9223372036854775807", diffs: []string{"1", "1.5", `{"value":"5","max":"9223372036854775812"}`, `{"value":"1","max":"9223372036854775812"}`, "-3"}},
+		{resType: "BigIntSub", cur: "10", diffs: []string{"3", "-3", `{"value":"8","min":"0"}`, `{"value":"7","min":"0"}`}},
+		{resType: "DecimalAdd", diffs: []string{"0.1", "0.2", "1e3", "-0.30"}},
+		{resType: "DecimalSub", cur: "1.50", diffs: []string{"0.5", "abc", "1"}},
+		{resType: "BigIntAdd", cur: "1.5", diffs: []string{"1"}},
+	}
+
+	r := NewRegistry()
+	for _, test := range tests {
+		test := test
+		t.Run(test.resType, func(t *testing.T) {
+			acc, err := r.NewAccumulator([]byte(test.cur), test.resType)
+			_, resolveErr := r.Resolve([]byte(test.cur), []byte("1"), test.resType)
+			if resolveErr != nil {
+				// the accumulator fails as the resolver does on an invalid current value
+				require.EqualError(t, err, resolveErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, acc)
+
+			// every diff merged into the accumulator yields the same value and the
+			// same error as the resolver, a failed diff leaving the value unchanged
+			cur := []byte(test.cur)
+			for _, diff := range test.diffs {
+				res, resolveErr := r.Resolve(cur, []byte(diff), test.resType)
+				err := acc.Add([]byte(diff))
+				if resolveErr != nil {
+					require.EqualError(t, err, resolveErr.Error())
+				} else {
+					require.NoError(t, err)
+					cur = res
+				}
+				require.Equal(t, string(cur), string(acc.Value()))
+			}
+		})
+	}
+}
+
+func TestRegistryAccumulators(t *testing.T) {
+	r := NewRegistry()
+
+	acc, err := r.NewAccumulator(nil, ORSet)
+	require.NoError(t, err)
+	require.Nil(t, acc)
+
+	acc, err = r.NewAccumulator([]byte("5"), "BigIntAdd")
+	require.NoError(t, err)
+	require.NoError(t, acc.Add([]byte("2")))
+	require.Equal(t, []byte("7"), acc.Value())
+
+	// the resolver replacing a builtin one has no accumulator
+	r.Register("BigIntAdd", crdt.ResolverFunc(setResolve))
+	acc, err = r.NewAccumulator([]byte("5"), "BigIntAdd")
+	require.NoError(t, err)
+	require.Nil(t, acc)
+}
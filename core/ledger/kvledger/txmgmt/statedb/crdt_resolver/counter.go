@@ -0,0 +1,168 @@
+package crdt_resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// GCounter is the resolution type of the grow-only counters
+	GCounter = "GCounter"
+	// PNCounter is the resolution type of the counters that can be incremented and decremented
+	PNCounter = "PNCounter"
+)
+
+// counterState is the value of a G-Counter or PN-Counter key. The counter is a
+// vector that holds, for every MSP, the sum of the increments and the sum of the
+// decrements made by the transactions created by that MSP. The total is derived
+// from the components and is stored along with them so that reading the counter
+// returns both the value and the per-organization breakdown, e.g.
+// {"components":{"Org1MSP":{"inc":"5","dec":"2"},"Org2MSP":{"inc":"1"}},"total":"4"}
+type counterState struct {
+	Components map[string]*counterComponent `json:"components"`
+	Total      string                       `json:"total"`
+}
+
+type counterComponent struct {
+	Inc string `json:"inc,omitempty"`
+	Dec string `json:"dec,omitempty"`
+}
+
+// IsCounter returns true if the resolution type is one of the counters whose
+// components are attributed to the MSP of the transaction creator
+func IsCounter(resType string) bool {
+	return resType == GCounter || resType == PNCounter
+}
+
+// newCounterDelta converts the amount a transaction created by the given MSP adds to a
+// counter into the diff merged by the counter resolvers. A negative amount decrements
+// the counter and is only allowed for PN-Counters
+func newCounterDelta(resType string, mspID string, amount []byte) ([]byte, error) {
+	if !IsCounter(resType) {
+		return nil, fmt.Errorf("Resolve type %s is not a counter", resType)
+	}
+	if mspID == "" {
+		return nil, fmt.Errorf("Counter delta requires an MSP ID")
+	}
+	value, err := parseCounterValue(string(amount))
+	if err != nil {
+		return nil, err
+	}
+	component := &counterComponent{}
+	switch {
+	case value.Sign() > 0:
+		component.Inc = value.String()
+	case value.Sign() < 0 && resType == GCounter:
+		return nil, fmt.Errorf("G-Counter can't be decremented")
+	case value.Sign() < 0:
+		component.Dec = new(big.Int).Neg(value).String()
+	}
+	return json.Marshal(&counterState{
+		Components: map[string]*counterComponent{mspID: component},
+		Total:      value.String(),
+	})
+}
+
+func parseCounterValue(s string) (*big.Int, error) {
+	d, err := parseDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if d.scale != 0 {
+		return nil, fmt.Errorf("Invalid integer %q", s)
+	}
+	return d.unscaled, nil
+}
+
+func parseCounterState(value []byte, growOnly bool) (map[string][2]*big.Int, error) {
+	state := &counterState{}
+	if err := json.Unmarshal(value, state); err != nil {
+		return nil, fmt.Errorf("Invalid counter value: %s", err)
+	}
+	components := make(map[string][2]*big.Int, len(state.Components))
+	for mspID, component := range state.Components {
+		if mspID == "" || component == nil {
+			return nil, fmt.Errorf("Invalid counter value: empty component")
+		}
+		var incDec [2]*big.Int
+		for i, s := range []string{component.Inc, component.Dec} {
+			incDec[i] = new(big.Int)
+			if s == "" {
+				continue
+			}
+			v, err := parseCounterValue(s)
+			if err != nil {
+				return nil, err
+			}
+			if v.Sign() < 0 {
+				return nil, fmt.Errorf("Counter component of %s can't be negative", mspID)
+			}
+			incDec[i] = v
+		}
+		if growOnly && incDec[1].Sign() != 0 {
+			return nil, fmt.Errorf("G-Counter can't be decremented")
+		}
+		components[mspID] = incDec
+	}
+	return components, nil
+}
+
+// counterResolve adds the components of the diff to the ones of the current
+// value. The total carried by the diff is ignored and computed again from the
+// merged components
+func counterResolve(m *meter, curValue []byte, diffValue []byte, growOnly bool) ([]byte, error) {
+	merged := map[string][2]*big.Int{}
+	if len(curValue) != 0 {
+		var err error
+		if merged, err = parseCounterState(curValue, growOnly); err != nil {
+			return []byte(""), typeMismatch(err)
+		}
+	}
+	diff, err := parseCounterState(diffValue, growOnly)
+	if err != nil {
+		return []byte(""), malformedDiff(err)
+	}
+	if err := m.charge(len(merged) + len(diff)); err != nil {
+		return []byte(""), err
+	}
+
+	for mspID, incDec := range diff {
+		cur, ok := merged[mspID]
+		if !ok {
+			merged[mspID] = incDec
+			continue
+		}
+		merged[mspID] = [2]*big.Int{
+			new(big.Int).Add(cur[0], incDec[0]),
+			new(big.Int).Add(cur[1], incDec[1]),
+		}
+	}
+
+	state := &counterState{Components: make(map[string]*counterComponent, len(merged))}
+	total := new(big.Int)
+	for mspID, incDec := range merged {
+		component := &counterComponent{}
+		if incDec[0].Sign() != 0 {
+			component.Inc = incDec[0].String()
+		}
+		if incDec[1].Sign() != 0 {
+			component.Dec = incDec[1].String()
+		}
+		state.Components[mspID] = component
+		total.Add(total, incDec[0])
+		total.Sub(total, incDec[1])
+	}
+	state.Total = total.String()
+
+	return json.Marshal(state)
+}
+
+func gCounterResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	return counterResolve(m, curValue, diffValue, true)
+}
+
+func pnCounterResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	return counterResolve(m, curValue, diffValue, false)
+}
@@ -0,0 +1,94 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterDelta(t *testing.T) {
+	delta, err := newCounterDelta(PNCounter, "Org1MSP", []byte("5"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"components":{"Org1MSP":{"inc":"5"}},"total":"5"}`, string(delta))
+
+	delta, err = newCounterDelta(PNCounter, "Org1MSP", []byte("-3"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"components":{"Org1MSP":{"dec":"3"}},"total":"-3"}`, string(delta))
+
+	_, err = newCounterDelta(GCounter, "Org1MSP", []byte("-3"))
+	require.EqualError(t, err, "G-Counter can't be decremented")
+
+	_, err = newCounterDelta(GCounter, "Org1MSP", []byte("1.5"))
+	require.EqualError(t, err, `Invalid integer "1.5"`)
+
+	_, err = newCounterDelta(GCounter, "", []byte("1"))
+	require.EqualError(t, err, "Counter delta requires an MSP ID")
+
+	_, err = newCounterDelta("IntAdd", "Org1MSP", []byte("1"))
+	require.EqualError(t, err, "Resolve type IntAdd is not a counter")
+}
+
+func TestCounterResolvers(t *testing.T) {
+	r := NewRegistry()
+	merge := func(resType string, cur []byte, mspID string, amount string) []byte {
+		delta, err := newCounterDelta(resType, mspID, []byte(amount))
+		require.NoError(t, err)
+		res, err := r.Resolve(cur, delta, resType)
+		require.NoError(t, err)
+		return res
+	}
+
+	value := merge(PNCounter, nil, "Org1MSP", "5")
+	value = merge(PNCounter, value, "Org2MSP", "10")
+	value = merge(PNCounter, value, "Org1MSP", "-2")
+	value = merge(PNCounter, value, "Org2MSP", "-12")
+	require.Equal(t,
+		`{"components":{"Org1MSP":{"inc":"5","dec":"2"},"Org2MSP":{"inc":"10","dec":"12"}},"total":"1"}`,
+		string(value),
+	)
+
+	value = merge(GCounter, nil, "Org2MSP", "1")
+	value = merge(GCounter, value, "Org1MSP", "18446744073709551616")
+	require.Equal(t,
+		`{"components":{"Org1MSP":{"inc":"18446744073709551616"},"Org2MSP":{"inc":"1"}},"total":"18446744073709551617"}`,
+		string(value),
+	)
+
+	// the total carried by the diff is not trusted
+	res, err := r.Resolve(nil, []byte(`{"components":{"Org1MSP":{"inc":"1"}},"total":"100"}`), GCounter)
+	require.NoError(t, err)
+	require.Equal(t, `{"components":{"Org1MSP":{"inc":"1"}},"total":"1"}`, string(res))
+
+	_, err = r.Resolve(nil, []byte(`{"components":{"Org1MSP":{"dec":"1"}}}`), GCounter)
+	require.EqualError(t, err, "G-Counter can't be decremented")
+
+	_, err = r.Resolve(nil, []byte(`{"components":{"Org1MSP":{"inc":"-1"}}}`), PNCounter)
+	require.EqualError(t, err, "Counter component of Org1MSP can't be negative")
+
+	_, err = r.Resolve([]byte("5"), []byte(`{"components":{"Org1MSP":{"inc":"1"}}}`), PNCounter)
+	require.EqualError(t, err, "Invalid counter value: json: cannot unmarshal number into Go value of type crdt_resolver.counterState")
+}
+
+func TestCheckDiffAttribution(t *testing.T) {
+	delta, err := newCounterDelta(PNCounter, "Org1MSP", []byte("-3"))
+	require.NoError(t, err)
+	require.NoError(t, CheckDiffAttribution(PNCounter, delta, "Org1MSP"))
+
+	err = CheckDiffAttribution(PNCounter, delta, "Org2MSP")
+	require.EqualError(t, err, "Counter diff changes the component of Org1MSP, the transaction is created by Org2MSP")
+	require.Equal(t, MalformedDiff, MergeFailureOf(err))
+
+	err = CheckDiffAttribution(GCounter, []byte(`{"components":{"Org1MSP":{"inc":"1"},"Org2MSP":{"inc":"1"}}}`), "Org1MSP")
+	require.EqualError(t, err, "Counter diff changes the component of Org2MSP, the transaction is created by Org1MSP")
+
+	err = CheckDiffAttribution(GCounter, []byte("1"), "Org1MSP")
+	require.Equal(t, MalformedDiff, MergeFailureOf(err))
+
+	// the counter fields of a map are checked, the other fields are not
+	require.NoError(t, CheckDiffAttribution(CRDTMap, []byte(`{"balance":{"type":"PNCounter","diff":`+string(delta)+`},"owner":{"type":"LWWRegister","diff":{"value":"alice","timestamp":1}}}`), "Org1MSP"))
+	err = CheckDiffAttribution(CRDTMap, []byte(`{"balance":{"type":"PNCounter","diff":`+string(delta)+`}}`), "Org2MSP")
+	require.EqualError(t, err, "Field balance of the CRDT map: Counter diff changes the component of Org1MSP, the transaction is created by Org2MSP")
+	require.Equal(t, MalformedDiff, MergeFailureOf(err))
+
+	require.NoError(t, CheckDiffAttribution("IntAdd", []byte("1"), ""))
+}
@@ -2,31 +2,72 @@ package crdt_resolver
 
 import (
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/hyperledger/fabric/core/handlers/crdt"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
 )
 
-func Resolve(curValue []byte, diffValue []byte, resType string) ([]byte, error) {
-
-	switch resType {
-	case "Set":
-		return diffValue, nil
-	case "IntAdd":
-		return intAddResolve(curValue, diffValue)
-	case "UintSub":
-		return uintSubResolve(curValue, diffValue)
-	case "StringConcat":
-		return stringConcatResolve(curValue, diffValue)
-	case "ArrayAppend":
-		return arrayAppendResolve(curValue, diffValue)
-	case "Wait": // Just for testing purpose. Useless otherwise.
-		return waitResolve(diffValue)
-	default:
-		return []byte(""), fmt.Errorf("Unknown resolve type")
+// builtinResolvers returns the resolvers that are available on every peer,
+// keyed by their resolution type
+func builtinResolvers() map[string]crdt.Resolver {
+	return map[string]crdt.Resolver{
+		"Set":          crdt.ResolverFunc(setResolve),
+		"IntAdd":       crdt.ResolverFunc(intAddResolve),
+		"UintSub":      crdt.ResolverFunc(uintSubResolve),
+		"StringConcat": crdt.ResolverFunc(stringConcatResolve),
+		"ArrayAppend":  meteredResolverFunc(arrayAppendResolve),
+		"BigIntAdd":    crdt.ResolverFunc(bigIntAddResolve),
+		"BigIntSub":    crdt.ResolverFunc(bigIntSubResolve),
+		"DecimalAdd":   crdt.ResolverFunc(decimalAddResolve),
+		"DecimalSub":   crdt.ResolverFunc(decimalSubResolve),
+		GCounter:       meteredResolverFunc(gCounterResolve),
+		PNCounter:      meteredResolverFunc(pnCounterResolve),
+		GSet:           meteredResolverFunc(gSetResolve),
+		TwoPSet:        meteredResolverFunc(twoPSetResolve),
+		ORSet:          meteredResolverFunc(orSetResolve),
+		LWWRegister:    heightAwareResolverFunc(lwwRegisterResolve),
+		MVRegister:     heightAwareResolverFunc(mvRegisterResolve),
+		JSONMergePatch: meteredResolverFunc(jsonMergePatchResolve),
+		JSONPatch:      meteredResolverFunc(jsonPatchResolve),
+	}
+}
+
+// meteredResolver is implemented by the builtin resolvers that charge the steps they take to a meter,
+// see Limits.MaxMergeSteps
+type meteredResolver interface {
+	crdt.Resolver
+	resolveMetered(m *meter, curValue []byte, diffValue []byte, height *version.Height) ([]byte, error)
+}
+
+// meteredResolverFunc is an adapter that allows the use of an ordinary
+// function charging a meter as a crdt.Resolver
+type meteredResolverFunc func(m *meter, curValue []byte, diffValue []byte) ([]byte, error)
+
+// Resolve calls f with no meter
+func (f meteredResolverFunc) Resolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return f(nil, curValue, diffValue)
+}
+
+func (f meteredResolverFunc) resolveMetered(m *meter, curValue []byte, diffValue []byte, _ *version.Height) ([]byte, error) {
+	return f(m, curValue, diffValue)
+}
+
+// testResolvers returns the resolvers that are only meant for testing, keyed by
+// their resolution type. They are available once enabled, see Registry.EnableTestResolvers
+func testResolvers() map[string]crdt.Resolver {
+	return map[string]crdt.Resolver{
+		// Wait sleeps for the number of milliseconds given by the diff, so that the merges
+		// can be made to take a while, and charges as many steps
+		"Wait": meteredResolverFunc(waitResolve),
 	}
 }
 
+func setResolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return diffValue, nil
+}
+
 func intAddResolve(curValue []byte, diffValue []byte) ([]byte, error) {
 	var curNumber int
 	var err error
@@ -34,14 +75,14 @@ func intAddResolve(curValue []byte, diffValue []byte) ([]byte, error) {
 		curNumber, err = strconv.Atoi(string(curValue))
 
 		if err != nil {
-			return []byte(""), err
+			return []byte(""), typeMismatch(err)
 		}
 	}
 
 	difNumber, err := strconv.Atoi(string(diffValue))
 
 	if err != nil {
-		return []byte(""), err
+		return []byte(""), malformedDiff(err)
 	}
 
 	res, err := add(curNumber, difNumber)
@@ -57,20 +98,24 @@ func stringConcatResolve(curValue []byte, diffValue []byte) ([]byte, error) {
 	return []byte(string(curValue) + string(diffValue)), nil
 }
 
-func arrayAppendResolve(curValue []byte, diffValue []byte) ([]byte, error) {
+func arrayAppendResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
 	var curArray []interface{}
 	var diffArray []interface{}
 	if len(curValue) != 0 {
 		err := json.Unmarshal(curValue, &curArray)
 
 		if err != nil {
-			return []byte(""), err
+			return []byte(""), typeMismatch(err)
 		}
 	}
 
 	err := json.Unmarshal(diffValue, &diffArray)
 
 	if err != nil {
+		return []byte(""), malformedDiff(err)
+	}
+
+	if err := m.charge(len(curArray) + len(diffArray)); err != nil {
 		return []byte(""), err
 	}
 
@@ -86,20 +131,20 @@ func arrayAppendResolve(curValue []byte, diffValue []byte) ([]byte, error) {
 func uintSubResolve(cur []byte, diff []byte) ([]byte, error) {
 	curVal, err := strconv.Atoi(string(cur))
 	if err != nil {
-		return []byte(""), err
+		return []byte(""), typeMismatch(err)
 	}
 
 	diffVal, err := strconv.Atoi(string(diff))
 	if err != nil {
-		return []byte(""), err
+		return []byte(""), malformedDiff(err)
 	}
 
 	if diffVal < 0 {
-		return []byte(""), fmt.Errorf("Can't have negative diff")
+		return []byte(""), mergeErrorf(MalformedDiff, "Can't have negative diff")
 	}
 
 	if curVal < diffVal {
-		return []byte(""), fmt.Errorf("Negative result")
+		return []byte(""), mergeErrorf(Underflow, "Negative result")
 	}
 
 	resValue, err := sub(curVal, diffVal)
@@ -111,10 +156,14 @@ func uintSubResolve(cur []byte, diff []byte) ([]byte, error) {
 	return []byte(strconv.Itoa(resValue)), nil
 }
 
-func waitResolve(val []byte) ([]byte, error) {
+func waitResolve(m *meter, curValue []byte, val []byte) ([]byte, error) {
 	mils, err := strconv.Atoi(string(val))
 
 	if err != nil {
+		return []byte(""), malformedDiff(err)
+	}
+
+	if err := m.charge(mils); err != nil {
 		return []byte(""), err
 	}
 
@@ -131,7 +180,11 @@ func sub(b int, q int) (int, error) {
 	diff = b - q
 
 	if (diff > b) == (b >= 0 && q >= 0) {
-		return 0, fmt.Errorf("Math: Subtraction overflow occurred  %d - %d", b, q)
+		failure := Overflow
+		if q > 0 {
+			failure = Underflow
+		}
+		return 0, mergeErrorf(failure, "Math: Subtraction overflow occurred  %d - %d", b, q)
 	}
 
 	return diff, nil
@@ -139,12 +192,15 @@ func sub(b int, q int) (int, error) {
 
 func add(b int, q int) (int, error) {
 
-	// Check overflow
+	// Check overflow, it can only occur when both numbers have the same sign
 	var sum int
 	sum = q + b
 
-	if (sum < q) == (b >= 0 && q >= 0) {
-		return 0, fmt.Errorf("Math: addition overflow occurred %d + %d", b, q)
+	if b > 0 && q > 0 && sum < 0 {
+		return 0, mergeErrorf(Overflow, "Math: addition overflow occurred %d + %d", b, q)
+	}
+	if b < 0 && q < 0 && sum >= 0 {
+		return 0, mergeErrorf(Underflow, "Math: addition overflow occurred %d + %d", b, q)
 	}
 
 	return sum, nil
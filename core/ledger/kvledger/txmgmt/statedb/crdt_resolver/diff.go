@@ -0,0 +1,80 @@
+package crdt_resolver
+
+import "fmt"
+
+// DiffContext provides what is known about a transaction while it is simulated,
+// that is needed to prepare the diffs of some resolution types
+type DiffContext struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// CreatorMSPID returns the MSP ID of the creator of the transaction
+	CreatorMSPID func() (string, error)
+	// Observed returns the value of the key read by the transaction, that is the
+	// committed value with the earlier writes of the transaction merged into it
+	Observed func() ([]byte, error)
+}
+
+// PrepareDiff converts the value sent by chaincode into the diff that is recorded in the
+// CRDT payload of the transaction. The diffs of the counters are attributed to the MSP of
+// the creator of the transaction, while the removes of an OR-Set and the writes of a
+// Multi-Value Register are resolved against the value of the key observed by the transaction. The values
+// of the other resolution types are recorded as they are
+func PrepareDiff(resType string, value []byte, ctx *DiffContext) ([]byte, error) {
+	switch resType {
+	case GCounter, PNCounter:
+		mspID, err := ctx.CreatorMSPID()
+		if err != nil {
+			return nil, err
+		}
+		return newCounterDelta(resType, mspID, value)
+	case ORSet:
+		observed, err := ctx.Observed()
+		if err != nil {
+			return nil, err
+		}
+		return newORSetDelta(ctx.TxID, observed, value)
+	case MVRegister:
+		observed, err := ctx.Observed()
+		if err != nil {
+			return nil, err
+		}
+		return newMVRegisterDelta(observed, value)
+	case CRDTMap:
+		return prepareMapDiff(value, ctx)
+	default:
+		return value, nil
+	}
+}
+
+// CheckDiffAttribution checks that the diff of a CRDT payload only changes the components of the counters that
+// belong to the MSP of the creator of the transaction, as PrepareDiff attributes them on the endorser. The
+// counters are checked at validation too, so that a client can't change the components of another organization
+// by sending a transaction simulated with a modified peer. The fields of a CRDT map are checked recursively
+func CheckDiffAttribution(resType string, diff []byte, mspID string) error {
+	switch resType {
+	case GCounter, PNCounter:
+		components, err := parseCounterState(diff, resType == GCounter)
+		if err != nil {
+			return malformedDiff(err)
+		}
+		for componentMSPID := range components {
+			if componentMSPID != mspID {
+				return mergeErrorf(MalformedDiff, "Counter diff changes the component of %s, the transaction is created by %s", componentMSPID, mspID)
+			}
+		}
+	case CRDTMap:
+		diffs, err := parseMapDiff(diff)
+		if err != nil {
+			return malformedDiff(err)
+		}
+		for name, fieldDiff := range diffs {
+			if fieldDiff == nil {
+				continue
+			}
+			if err := CheckDiffAttribution(fieldDiff.Type, fieldDiff.Diff, mspID); err != nil {
+				return mergeError(MalformedDiff, fmt.Errorf("Field %s of the CRDT map: %s", name, err))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package crdt_resolver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MergeFailure tells why a CRDT payload failed to merge into a key
+type MergeFailure int
+
+const (
+	// MergeFailed is a failure that is not classified further, e.g. one reported by a resolver
+	// registered by a plugin or a JSON patch operation that does not apply to the current document
+	MergeFailed MergeFailure = iota
+	// UnknownType is reported when no resolver is registered for the resolution type
+	UnknownType
+	// MalformedDiff is reported when the diff, or a predicate, is not valid for the resolution type
+	MalformedDiff
+	// TypeMismatch is reported when the current value of the key is not a value of the
+	// resolution type, e.g. when the key was merged with another resolution type before
+	TypeMismatch
+	// Underflow is reported when the merged value would be below the lower bound of the value
+	Underflow
+	// Overflow is reported when the merged value would be above the upper bound of the value
+	Overflow
+	// SchemaViolation is reported when the CRDT schema of the chaincode does not allow
+	// the resolution type to be used on the key
+	SchemaViolation
+	// BudgetExceeded is reported when a merge exceeds the limits of the registry, see Limits
+	BudgetExceeded
+)
+
+var mergeFailureNames = map[MergeFailure]string{
+	MergeFailed:     "MergeFailed",
+	UnknownType:     "UnknownType",
+	MalformedDiff:   "MalformedDiff",
+	TypeMismatch:    "TypeMismatch",
+	Underflow:       "Underflow",
+	Overflow:        "Overflow",
+	SchemaViolation: "SchemaViolation",
+	BudgetExceeded:  "BudgetExceeded",
+}
+
+func (f MergeFailure) String() string {
+	if name, ok := mergeFailureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("MergeFailure(%d)", int(f))
+}
+
+// MergeError is returned by the builtin resolvers and by the registry when a payload fails to merge,
+// so that the reason of the failure can be told apart. The message is the one of the wrapped error
+type MergeError struct {
+	Failure MergeFailure
+	Err     error
+}
+
+func (e *MergeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MergeError) Unwrap() error {
+	return e.Err
+}
+
+// MergeFailureOf returns the failure of the MergeError found in the chain of err,
+// MergeFailed if there is none
+func MergeFailureOf(err error) MergeFailure {
+	var mergeErr *MergeError
+	if errors.As(err, &mergeErr) {
+		return mergeErr.Failure
+	}
+	return MergeFailed
+}
+
+// mergeError classifies err unless it is already classified
+func mergeError(failure MergeFailure, err error) error {
+	var mergeErr *MergeError
+	if err == nil || errors.As(err, &mergeErr) {
+		return err
+	}
+	return &MergeError{Failure: failure, Err: err}
+}
+
+func mergeErrorf(failure MergeFailure, format string, args ...interface{}) error {
+	return &MergeError{Failure: failure, Err: fmt.Errorf(format, args...)}
+}
+
+func malformedDiff(err error) error {
+	return mergeError(MalformedDiff, err)
+}
+
+func typeMismatch(err error) error {
+	return mergeError(TypeMismatch, err)
+}
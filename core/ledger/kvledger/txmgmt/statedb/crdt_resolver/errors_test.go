@@ -0,0 +1,72 @@
+package crdt_resolver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFailures(t *testing.T) {
+	registry := NewRegistry()
+	tests := []struct {
+		name      string
+		resType   string
+		curValue  string
+		diffValue string
+		failure   MergeFailure
+	}{
+		{"unknown type", "NoSuchType", "", "1", UnknownType},
+		{"malformed int", "IntAdd", "1", "abc", MalformedDiff},
+		{"int value mismatch", "IntAdd", `{"elements":[]}`, "1", TypeMismatch},
+		{"int overflow", "IntAdd", "9223372036854775807", "1", Overflow},
+		{"int underflow", "IntAdd", "-9223372036854775808", "-1", Underflow},
+		{"uint negative result", "UintSub", "1", "2", Underflow},
+		{"uint negative diff", "UintSub", "1", "-2", MalformedDiff},
+		{"big int below min", "BigIntAdd", "1", `{"value":"-2","min":"0"}`, Underflow},
+		{"big int above max", "BigIntAdd", "1", `{"value":"2","max":"2"}`, Overflow},
+		{"big int malformed bound", "BigIntAdd", "1", `{"value":"2","max":"x"}`, MalformedDiff},
+		{"decimal value mismatch", "DecimalAdd", "abc", "1", TypeMismatch},
+		{"array malformed", "ArrayAppend", "[]", "{", MalformedDiff},
+		{"counter mismatch", PNCounter, "5", `{"components":{"Org1MSP":{"inc":"1"}},"total":"1"}`, TypeMismatch},
+		{"g-counter decremented", GCounter, "", `{"components":{"Org1MSP":{"dec":"1"}},"total":"-1"}`, MalformedDiff},
+		{"g-set remove", GSet, "", `{"remove":["a"]}`, MalformedDiff},
+		{"set mismatch", TwoPSet, "5", `{"add":["a"]}`, TypeMismatch},
+		{"lww malformed", LWWRegister, "", `{"timestamp":1}`, MalformedDiff},
+		{"json patch mismatch", JSONMergePatch, "{", `{"a":1}`, TypeMismatch},
+		{"json patch test", JSONPatch, `{"a":1}`, `[{"op":"test","path":"/a","value":2}]`, MergeFailed},
+		{"map field underflow", CRDTMap, `{"stock":{"type":"UintSub","value":1}}`, `{"stock":{"type":"UintSub","diff":2}}`, Underflow},
+		{"map field bound", CRDTMap, "", `{"stock":{"type":"BigIntAdd","diff":{"value":"-1","min":"0"}}}`, Underflow},
+		{"map field type change", CRDTMap, `{"stock":{"type":"IntAdd","value":1}}`, `{"stock":{"type":"BigIntAdd","diff":1}}`, TypeMismatch},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := registry.ResolveAt([]byte(test.curValue), []byte(test.diffValue), test.resType, version.NewHeight(1, 0))
+			require.Error(t, err)
+			require.Equal(t, test.failure, MergeFailureOf(err), err.Error())
+		})
+	}
+
+	err := CheckSchema(&lb.CRDTSchema{Keys: []*lb.CRDTKeySchema{{KeyPattern: "a", ResolutionTypes: []string{"IntAdd"}}}}, "b", "IntAdd")
+	require.Equal(t, SchemaViolation, MergeFailureOf(err))
+
+	err = CheckPredicates([]byte("abc"), "StringConcat", []*kvrwset.CRDTPredicate{{Operator: kvrwset.CRDTPredicate_GREATER_OR_EQUAL, Operand: []byte("1")}})
+	require.Equal(t, TypeMismatch, MergeFailureOf(err))
+
+	// the errors of the resolvers that do not classify them are not classified
+	require.Equal(t, MergeFailed, MergeFailureOf(errors.New("custom resolver error")))
+	require.Equal(t, MergeFailed, MergeFailureOf(nil))
+
+	// a classified error keeps its classification and its message when wrapped
+	err = fmt.Errorf("wrapped: %w", mergeErrorf(Overflow, "too big"))
+	require.Equal(t, Overflow, MergeFailureOf(err))
+	require.EqualError(t, err, "wrapped: too big")
+	require.Equal(t, err, mergeError(Underflow, err))
+	require.Equal(t, "Overflow", Overflow.String())
+	require.Equal(t, "MergeFailure(42)", MergeFailure(42).String())
+}
@@ -0,0 +1,459 @@
+package crdt_resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JSONMergePatch is the resolution type that merges RFC 7386 merge patches into JSON documents
+	JSONMergePatch = "JSONMergePatch"
+	// JSONPatch is the resolution type that applies RFC 6902 patches to JSON documents
+	JSONPatch = "JSONPatch"
+)
+
+// decodeJSON decodes a JSON value keeping the numbers as json.Number,
+// so that they are written back exactly as they were received
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// decodeDocument decodes the current value of a JSON document key,
+// which is an empty object if the key does not exist yet
+func decodeDocument(curValue []byte) (interface{}, error) {
+	if len(curValue) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	doc, err := decodeJSON(curValue)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid JSON document: %s", err)
+	}
+	return doc, nil
+}
+
+// encodeDocument checks that the document can be stored as a JSON document by the
+// CouchDB state database, so that it can be used in rich queries and indexes, and
+// encodes it with the keys of the objects sorted
+func encodeDocument(doc interface{}) ([]byte, error) {
+	object, ok := doc.(map[string]interface{})
+	if !ok {
+		return []byte(""), mergeErrorf(MalformedDiff, "Merged document must be a JSON object")
+	}
+	for field := range object {
+		if strings.HasPrefix(field, "_") || field == "~version" {
+			return []byte(""), mergeErrorf(MalformedDiff, "Field %s is reserved by the CouchDB state database", field)
+		}
+	}
+	return json.Marshal(object)
+}
+
+func jsonMergePatchResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	doc, err := decodeDocument(curValue)
+	if err != nil {
+		return []byte(""), typeMismatch(err)
+	}
+	patch, err := decodeJSON(diffValue)
+	if err != nil {
+		return []byte(""), mergeErrorf(MalformedDiff, "Invalid JSON merge patch: %s", err)
+	}
+	if doc, err = mergePatch(m, doc, patch); err != nil {
+		return []byte(""), err
+	}
+	return encodeDocument(doc)
+}
+
+// mergePatch implements the MergePatch function of RFC 7386, charging a step for every member of the patch
+func mergePatch(m *meter, target interface{}, patch interface{}) (interface{}, error) {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch, nil
+	}
+	if err := m.charge(len(patchObject)); err != nil {
+		return nil, err
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		merged, err := mergePatch(m, targetObject[name], value)
+		if err != nil {
+			return nil, err
+		}
+		targetObject[name] = merged
+	}
+	return targetObject, nil
+}
+
+// patchOperation is an operation of an RFC 6902 JSON patch
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func jsonPatchResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	doc, err := decodeDocument(curValue)
+	if err != nil {
+		return []byte(""), typeMismatch(err)
+	}
+	var ops []*patchOperation
+	if err := json.Unmarshal(diffValue, &ops); err != nil {
+		return []byte(""), mergeErrorf(MalformedDiff, "Invalid JSON patch: %s", err)
+	}
+	for i, op := range ops {
+		if doc, err = applyPatchOperation(m, doc, op); err != nil {
+			if MergeFailureOf(err) == BudgetExceeded {
+				return []byte(""), err
+			}
+			return []byte(""), fmt.Errorf("JSON patch operation %d failed: %s", i, err)
+		}
+	}
+	return encodeDocument(doc)
+}
+
+// applyPatchOperation applies an operation to the document, charging a step for the operation and for
+// every token of its paths, along with the elements of the arrays that are copied and the values that
+// are copied or compared
+func applyPatchOperation(m *meter, doc interface{}, op *patchOperation) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("missing path")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.charge(1 + len(path)); err != nil {
+		return nil, err
+	}
+
+	value := func() (interface{}, error) {
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		return decodeJSON(op.Value)
+	}
+	from := func() ([]string, error) {
+		if op.From == nil {
+			return nil, fmt.Errorf("missing from")
+		}
+		fromPath, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		return fromPath, m.charge(len(fromPath))
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return addValue(m, doc, path, v)
+	case "remove":
+		doc, _, err := removeValue(m, doc, path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return v, nil
+		}
+		if doc, _, err = removeValue(m, doc, path); err != nil {
+			return nil, err
+		}
+		return addValue(m, doc, path, v)
+	case "move":
+		fromPath, err := from()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(fromPath) && isPrefix(fromPath, path) {
+			return nil, fmt.Errorf("can't move %s into one of its children", *op.From)
+		}
+		doc, v, err := removeValue(m, doc, fromPath)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(m, doc, path, v)
+	case "copy":
+		fromPath, err := from()
+		if err != nil {
+			return nil, err
+		}
+		v, err := getValue(doc, fromPath)
+		if err != nil {
+			return nil, err
+		}
+		// the value is copied so that the two locations do not share it
+		if err := m.charge(jsonNodes(v)); err != nil {
+			return nil, err
+		}
+		copied, err := copyValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(m, doc, path, copied)
+	case "test":
+		expected, err := value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.charge(jsonNodes(expected)); err != nil {
+			return nil, err
+		}
+		if !jsonEqual(expected, actual) {
+			return nil, fmt.Errorf("test of %s failed", *op.Path)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// parsePointer parses an RFC 6901 JSON pointer into its reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix []string, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex parses the index of an array element. If allowEnd is set, "-" and
+// the length of the array are accepted as the position after the last element
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > length || (i == length && !allowEnd) {
+		return 0, fmt.Errorf("array index %q out of bounds", token)
+	}
+	return i, nil
+}
+
+func getValue(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			v, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+		}
+	}
+	return doc, nil
+}
+
+// addValue adds the value at the path and returns the updated document. Since
+// arrays may be reallocated, the parent of the updated array is updated as well
+func addValue(m *meter, doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := getValue(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		container[token] = value
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(container), true)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.charge(len(container)); err != nil {
+			return nil, err
+		}
+		updated := make([]interface{}, 0, len(container)+1)
+		updated = append(updated, container[:i]...)
+		updated = append(updated, value)
+		updated = append(updated, container[i:]...)
+		return setValue(doc, path[:len(path)-1], updated)
+	default:
+		return nil, fmt.Errorf("can't add %q to a scalar value", token)
+	}
+}
+
+// removeValue removes the value at the path and returns the updated document and the removed value
+func removeValue(m *meter, doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("can't remove the whole document")
+	}
+	parent, err := getValue(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := path[len(path)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		v, ok := container[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("member %q not found", token)
+		}
+		delete(container, token)
+		return doc, v, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(container), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := m.charge(len(container)); err != nil {
+			return nil, nil, err
+		}
+		v := container[i]
+		updated := make([]interface{}, 0, len(container)-1)
+		updated = append(updated, container[:i]...)
+		updated = append(updated, container[i+1:]...)
+		doc, err = setValue(doc, path[:len(path)-1], updated)
+		return doc, v, err
+	default:
+		return nil, nil, fmt.Errorf("can't remove %q from a scalar value", token)
+	}
+}
+
+// setValue replaces the existing value at the path
+func setValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := getValue(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		container[token] = value
+	case []interface{}:
+		i, err := arrayIndex(token, len(container), false)
+		if err != nil {
+			return nil, err
+		}
+		container[i] = value
+	}
+	return doc, nil
+}
+
+func copyValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// jsonNodes returns the number of values a decoded JSON value is made of
+func jsonNodes(v interface{}) int {
+	nodes := 1
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			nodes += jsonNodes(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			nodes += jsonNodes(child)
+		}
+	}
+	return nodes
+}
+
+// jsonEqual compares two decoded JSON values as defined by the test operation
+// of RFC 6902, i.e. the numbers are compared by their numeric value
+func jsonEqual(a interface{}, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(v, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ra, okA := new(big.Rat).SetString(string(a))
+		rb, okB := new(big.Rat).SetString(string(b))
+		return okA && okB && ra.Cmp(rb) == 0
+	default:
+		return a == b
+	}
+}
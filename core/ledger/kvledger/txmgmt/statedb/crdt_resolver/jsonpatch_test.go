@@ -0,0 +1,79 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMergePatchResolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		cur   string
+		patch string
+		res   string
+		err   string
+	}{
+		{name: "missing key", patch: `{"a":1}`, res: `{"a":1}`},
+		{name: "RFC 7386 example", cur: `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+			patch: `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`,
+			res:   `{"author":{"givenName":"John"},"content":"This will be unchanged","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`},
+		{name: "numbers are preserved", cur: `{"a":12345678901234567890}`, patch: `{"b":1.10}`, res: `{"a":12345678901234567890,"b":1.10}`},
+		{name: "non object patch", cur: `{"a":1}`, patch: `[1]`, err: "Merged document must be a JSON object"},
+		{name: "reserved field", cur: `{"a":1}`, patch: `{"_id":"x"}`, err: "Field _id is reserved by the CouchDB state database"},
+		{name: "invalid patch", cur: `{"a":1}`, patch: `{"a":}`, err: "Invalid JSON merge patch: invalid character '}' looking for beginning of value"},
+		{name: "invalid document", cur: `abc`, patch: `{}`, err: "Invalid JSON document: invalid character 'a' looking for beginning of value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := jsonMergePatchResolve(nil, []byte(tt.cur), []byte(tt.patch))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.res, string(res))
+		})
+	}
+}
+
+func TestJSONPatchResolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		cur   string
+		patch string
+		res   string
+		err   string
+	}{
+		{name: "add to missing key", patch: `[{"op":"add","path":"/a","value":1}]`, res: `{"a":1}`},
+		{name: "add to array", cur: `{"a":[1,3]}`, patch: `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/-","value":4}]`, res: `{"a":[1,2,3,4]}`},
+		{name: "remove", cur: `{"a":[1,2],"b":1}`, patch: `[{"op":"remove","path":"/a/0"},{"op":"remove","path":"/b"}]`, res: `{"a":[2]}`},
+		{name: "replace", cur: `{"a":{"b":1}}`, patch: `[{"op":"replace","path":"/a/b","value":[true]}]`, res: `{"a":{"b":[true]}}`},
+		{name: "replace document", cur: `{"a":1}`, patch: `[{"op":"replace","path":"","value":{"b":2}}]`, res: `{"b":2}`},
+		{name: "move", cur: `{"a":{"b":1},"c":[]}`, patch: `[{"op":"move","from":"/a/b","path":"/c/0"}]`, res: `{"a":{},"c":[1]}`},
+		{name: "copy", cur: `{"a":{"b":1}}`, patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/d","value":2}]`, res: `{"a":{"b":1},"c":{"b":1,"d":2}}`},
+		{name: "escaped pointer", cur: `{"a/b":{"c~d":1}}`, patch: `[{"op":"replace","path":"/a~1b/c~0d","value":2}]`, res: `{"a/b":{"c~d":2}}`},
+		{name: "test", cur: `{"a":{"b":[1,"x"]}}`, patch: `[{"op":"test","path":"/a","value":{"b":[1.0,"x"]}},{"op":"add","path":"/c","value":1}]`, res: `{"a":{"b":[1,"x"]},"c":1}`},
+		{name: "test mismatch", cur: `{"a":1}`, patch: `[{"op":"test","path":"/a","value":2}]`, err: "JSON patch operation 0 failed: test of /a failed"},
+		{name: "missing path", cur: `{"a":1}`, patch: `[{"op":"add","path":"/a","value":2},{"op":"remove","path":"/b/c"}]`, err: `JSON patch operation 1 failed: member "b" not found`},
+		{name: "out of bounds", cur: `{"a":[1]}`, patch: `[{"op":"add","path":"/a/2","value":2}]`, err: `JSON patch operation 0 failed: array index "2" out of bounds`},
+		{name: "leading zero", cur: `{"a":[1]}`, patch: `[{"op":"remove","path":"/a/00"}]`, err: `JSON patch operation 0 failed: invalid array index "00"`},
+		{name: "move into child", cur: `{"a":{}}`, patch: `[{"op":"move","from":"/a","path":"/a/b"}]`, err: "JSON patch operation 0 failed: can't move /a into one of its children"},
+		{name: "missing value", cur: `{}`, patch: `[{"op":"add","path":"/a"}]`, err: "JSON patch operation 0 failed: missing value"},
+		{name: "unknown op", cur: `{}`, patch: `[{"op":"merge","path":"/a"}]`, err: `JSON patch operation 0 failed: unknown operation "merge"`},
+		{name: "remove document", cur: `{}`, patch: `[{"op":"remove","path":""}]`, err: "JSON patch operation 0 failed: can't remove the whole document"},
+		{name: "reserved field", cur: `{}`, patch: `[{"op":"add","path":"/~0version","value":1}]`, err: "Field ~version is reserved by the CouchDB state database"},
+		{name: "invalid patch", cur: `{}`, patch: `{"op":"add"}`, err: "Invalid JSON patch: json: cannot unmarshal object into Go value of type []*crdt_resolver.patchOperation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := jsonPatchResolve(nil, []byte(tt.cur), []byte(tt.patch))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.res, string(res))
+		})
+	}
+}
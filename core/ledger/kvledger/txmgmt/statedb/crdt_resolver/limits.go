@@ -0,0 +1,106 @@
+package crdt_resolver
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+)
+
+var logger = flogging.MustGetLogger("crdt_resolver")
+
+// Limits bounds the size of the values merged by the resolvers of a registry and the work the resolvers
+// do, so that a payload can't stall the commit of a block. A merge exceeding a limit fails with
+// BudgetExceeded. The zero value of a limit disables it.
+//
+// The limits come from the local configuration of the peer and must be the same on every peer of a
+// channel. The work of a merge is counted in steps charged by the builtin resolvers, e.g. one per
+// element of a set, and is never measured in time. The resolvers of plugins are charged one step per merge
+type Limits struct {
+	// MaxValueSize is the maximum size, in bytes, of a diff and of a value merged by a resolver
+	MaxValueSize int
+	// MaxMergeSteps is the maximum number of steps the resolvers may take to merge a diff. The fields of
+	// a CRDT map are charged to the merge of the map
+	MaxMergeSteps int
+	// MaxBlockDiffSize is the maximum total size, in bytes, of the diffs of the public CRDT payloads
+	// of a block. It is enforced by the validator, which charges the payloads of the transactions in
+	// block order
+	MaxBlockDiffSize int
+	// SlowMergeWarning is not a limit. A merge taking longer is logged, so that the resolvers slowing
+	// down the commit of the blocks can be spotted, but its outcome is unchanged
+	SlowMergeWarning time.Duration
+}
+
+// ErrNotAccumulated is returned by the accumulators of a registry limiting the size of the values when
+// a diff could take the accumulated value over the limit. The diff is not merged into the accumulator,
+// it has to be merged by the resolver of the type instead, which tells whether the limit is exceeded
+var ErrNotAccumulated = errors.New("diff not accumulated")
+
+// meter counts the steps taken by the resolvers to merge a diff. A nil meter, or a meter with no
+// maximum, lets the resolvers take any number of steps
+type meter struct {
+	steps    int
+	maxSteps int
+}
+
+// charge adds the steps to the meter and fails once the maximum is exceeded
+func (m *meter) charge(steps int) error {
+	if m == nil || m.maxSteps == 0 {
+		return nil
+	}
+	m.steps += steps
+	if m.steps > m.maxSteps {
+		return mergeErrorf(BudgetExceeded, "Merge exceeds the maximum of %d steps", m.maxSteps)
+	}
+	return nil
+}
+
+// boundedAccumulator refuses the diffs that could take the accumulated value over the maximum size
+type boundedAccumulator struct {
+	Accumulator
+	// size is an upper bound of the size of the encoded value. The values of the builtin
+	// accumulators are numbers, which grow by at most the size of a diff plus one byte
+	size    int
+	maxSize int
+}
+
+func (a *boundedAccumulator) Add(diffValue []byte) error {
+	size := a.size + len(diffValue) + 1
+	if size > a.maxSize {
+		return ErrNotAccumulated
+	}
+	if err := a.Accumulator.Add(diffValue); err != nil {
+		return err
+	}
+	a.size = size
+	return nil
+}
+
+// resolveWithinLimits merges the diff into the current value unless the merge exceeds the limits of the registry
+func (r *Registry) resolveWithinLimits(curValue []byte, diffValue []byte, resType string, height *version.Height) ([]byte, error) {
+	maxSize := r.limits.MaxValueSize
+	if maxSize > 0 && len(diffValue) > maxSize {
+		return []byte(""), mergeErrorf(BudgetExceeded, "Diff of %d bytes exceeds the maximum value size of %d bytes", len(diffValue), maxSize)
+	}
+
+	if r.limits.SlowMergeWarning > 0 {
+		start := time.Now()
+		defer func() {
+			if elapsed := time.Since(start); elapsed > r.limits.SlowMergeWarning {
+				logger.Warningf("Resolver of type %s took %s to merge a diff of %d bytes into a value of %d bytes",
+					resType, elapsed, len(diffValue), len(curValue))
+			}
+		}()
+	}
+
+	merged, err := r.resolve(&meter{maxSteps: r.limits.MaxMergeSteps}, curValue, diffValue, resType, height)
+	if err != nil {
+		return merged, err
+	}
+
+	if maxSize > 0 && len(merged) > maxSize {
+		return []byte(""), mergeErrorf(BudgetExceeded, "Merged value of %d bytes exceeds the maximum value size of %d bytes", len(merged), maxSize)
+	}
+	return merged, nil
+}
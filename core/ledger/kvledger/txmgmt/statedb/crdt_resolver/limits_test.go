@@ -0,0 +1,171 @@
+package crdt_resolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/handlers/crdt"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryTestResolvers(t *testing.T) {
+	r := NewRegistry()
+	_, ok := r.Lookup("Wait")
+	require.False(t, ok)
+	_, err := r.Resolve(nil, []byte("1"), "Wait")
+	require.Equal(t, UnknownType, MergeFailureOf(err))
+
+	r.EnableTestResolvers()
+	_, ok = r.Lookup("Wait")
+	require.True(t, ok)
+	res, err := r.Resolve([]byte("a"), []byte("1"), "Wait")
+	require.NoError(t, err)
+	require.Equal(t, []byte(""), res)
+
+	// test resolvers are only enabled on the registry
+	_, ok = NewRegistry().Lookup("Wait")
+	require.False(t, ok)
+}
+
+func TestRegistryValueSizeLimit(t *testing.T) {
+	r := NewRegistry()
+	r.SetLimits(Limits{MaxValueSize: 8})
+	require.Equal(t, Limits{MaxValueSize: 8}, r.Limits())
+
+	res, err := r.Resolve([]byte("abcd"), []byte("efgh"), "StringConcat")
+	require.NoError(t, err)
+	require.Equal(t, []byte("abcdefgh"), res)
+
+	_, err = r.Resolve([]byte("abcd"), []byte("efghi"), "StringConcat")
+	require.EqualError(t, err, "Merged value of 9 bytes exceeds the maximum value size of 8 bytes")
+	require.Equal(t, BudgetExceeded, MergeFailureOf(err))
+
+	_, err = r.Resolve(nil, []byte("123456789"), "Set")
+	require.EqualError(t, err, "Diff of 9 bytes exceeds the maximum value size of 8 bytes")
+	require.Equal(t, BudgetExceeded, MergeFailureOf(err))
+
+	// the limit applies to a map as a whole
+	r.SetLimits(Limits{MaxValueSize: 64})
+	_, err = r.Resolve(nil, []byte(`{"a":{"type":"StringConcat","diff":"`+strings.Repeat("x", 40)+`"}}`), CRDTMap)
+	require.Equal(t, BudgetExceeded, MergeFailureOf(err))
+	res, err = r.Resolve(nil, []byte(`{"a":{"type":"IntAdd","diff":1}}`), CRDTMap)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":{"type":"IntAdd","value":1}}`, string(res))
+}
+
+func TestRegistryAccumulatorSizeLimit(t *testing.T) {
+	r := NewRegistry()
+	acc, err := r.NewAccumulator([]byte("99"), "BigIntAdd")
+	require.NoError(t, err)
+	_, bounded := acc.(*boundedAccumulator)
+	require.False(t, bounded)
+
+	r.SetLimits(Limits{MaxValueSize: 5})
+	acc, err = r.NewAccumulator([]byte("99"), "BigIntAdd")
+	require.NoError(t, err)
+	require.NoError(t, acc.Add([]byte("1")))
+	require.Equal(t, []byte("100"), acc.Value())
+	// the value could exceed the limit, the diff is left to the resolver
+	require.Equal(t, ErrNotAccumulated, acc.Add([]byte("1")))
+	require.Equal(t, []byte("100"), acc.Value())
+	res, err := r.Resolve(acc.Value(), []byte("1"), "BigIntAdd")
+	require.NoError(t, err)
+	require.Equal(t, []byte("101"), res)
+
+	// the errors of the accumulator are reported as they are
+	acc, err = r.NewAccumulator(nil, "BigIntAdd")
+	require.NoError(t, err)
+	require.Equal(t, MalformedDiff, MergeFailureOf(acc.Add([]byte("x"))))
+
+	acc, err = r.NewAccumulator(nil, "StringConcat")
+	require.NoError(t, err)
+	require.Nil(t, acc)
+}
+
+func TestRegistryMergeStepsLimit(t *testing.T) {
+	r := NewRegistry()
+	r.EnableTestResolvers()
+	r.SetLimits(Limits{MaxMergeSteps: 50})
+
+	// the merge itself takes a step
+	_, err := r.Resolve(nil, []byte("49"), "Wait")
+	require.NoError(t, err)
+
+	_, err = r.Resolve(nil, []byte("50"), "Wait")
+	require.EqualError(t, err, "Merge exceeds the maximum of 50 steps")
+	require.Equal(t, BudgetExceeded, MergeFailureOf(err))
+
+	// the errors of the resolvers are reported as they are
+	_, err = r.Resolve(nil, []byte("x"), "Wait")
+	require.Equal(t, MalformedDiff, MergeFailureOf(err))
+
+	// the height is passed to the height aware resolvers
+	diff := []byte(`{"value":"alice","timestamp":1}`)
+	res, err := r.ResolveAt(nil, diff, LWWRegister, version.NewHeight(1, 2))
+	require.NoError(t, err)
+	lww, err := NewRegistry().ResolveAt(nil, diff, LWWRegister, version.NewHeight(1, 2))
+	require.NoError(t, err)
+	require.Equal(t, lww, res)
+
+	// the resolvers registered by plugins are charged a step per merge
+	r.Register("Max", crdt.ResolverFunc(func(curValue []byte, diffValue []byte) ([]byte, error) { return diffValue, nil }))
+	res, err = r.Resolve(nil, []byte("1"), "Max")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), res)
+}
+
+func TestBuiltinResolversChargeSteps(t *testing.T) {
+	elements := func(n int) string {
+		e := make([]string, n)
+		for i := range e {
+			e[i] = fmt.Sprintf("%q", strconv.Itoa(i))
+		}
+		return strings.Join(e, ",")
+	}
+	array := "[" + strings.Repeat("1,", 19) + "1]"
+
+	tests := []struct {
+		name    string
+		resType string
+		cur     string
+		diff    string
+	}{
+		{name: "G-Set", resType: GSet, diff: `{"add":[` + elements(20) + `]}`},
+		{name: "2P-Set", resType: TwoPSet, diff: `{"remove":[` + elements(20) + `]}`},
+		{name: "OR-Set", resType: ORSet, diff: `{"add":{"a":[` + elements(20) + `]}}`},
+		{name: "array", resType: "ArrayAppend", cur: array, diff: "[1]"},
+		{name: "JSON merge patch", resType: JSONMergePatch, diff: `{"a":{` + strings.Replace(elements(20), ",", ":1,", -1) + `:1}}`},
+		{name: "JSON patch operations", resType: JSONPatch, diff: "[" + strings.Repeat(`{"op":"add","path":"/a","value":1},`, 9) + `{"op":"add","path":"/a","value":1}]`},
+		{name: "JSON patch array", resType: JSONPatch, cur: `{"a":` + array + `}`, diff: `[{"op":"remove","path":"/a/0"}]`},
+		{name: "JSON patch copy", resType: JSONPatch, cur: `{"a":` + array + `}`, diff: `[{"op":"copy","from":"/a","path":"/b"}]`},
+		{name: "JSON patch test", resType: JSONPatch, cur: `{"a":` + array + `}`, diff: `[{"op":"test","path":"/a","value":` + array + `}]`},
+		{name: "map", resType: CRDTMap, diff: `{"a":{"type":"GSet","diff":{"add":[` + elements(20) + `]}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			_, err := r.Resolve([]byte(tt.cur), []byte(tt.diff), tt.resType)
+			require.NoError(t, err)
+
+			r.SetLimits(Limits{MaxMergeSteps: 15})
+			_, err = r.Resolve([]byte(tt.cur), []byte(tt.diff), tt.resType)
+			require.Contains(t, err.Error(), "Merge exceeds the maximum of 15 steps")
+			require.Equal(t, BudgetExceeded, MergeFailureOf(err))
+		})
+	}
+}
+
+func TestRegistrySlowMergeWarning(t *testing.T) {
+	r := NewRegistry()
+	r.EnableTestResolvers()
+	r.SetLimits(Limits{SlowMergeWarning: time.Millisecond})
+
+	// a slow merge is only logged
+	res, err := r.Resolve([]byte("a"), []byte("10"), "Wait")
+	require.NoError(t, err)
+	require.Equal(t, []byte(""), res)
+}
@@ -0,0 +1,146 @@
+package crdt_resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+)
+
+// CRDTMap is the resolution type of the keys whose value is a JSON object
+// in which every field is a CRDT with its own resolution type
+const CRDTMap = "CRDTMap"
+
+// mapField is a field of the value of a CRDT map, e.g.
+// {"balance":{"type":"PNCounter","value":{...}},"owner":{"type":"LWWRegister","value":{...}}}.
+// The values of the fields are stored as they are, hence a field can only use a resolution
+// type whose values are JSON. The type of a field can't change once the field is created
+type mapField struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// mapFieldDiff is a field of the diff of a CRDT map, e.g.
+// {"balance":{"type":"PNCounter","diff":5},"tags":{"type":"ORSet","diff":{"add":["gold"]}}}.
+// The diff of every field is passed as is to the resolver of the field
+type mapFieldDiff struct {
+	Type string          `json:"type"`
+	Diff json.RawMessage `json:"diff"`
+}
+
+// mapResolver merges every field of the diff into the corresponding field of
+// the current value, using the resolver registered for the type of the field
+type mapResolver struct {
+	registry *Registry
+}
+
+func (m *mapResolver) Resolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return m.resolveMetered(nil, curValue, diffValue, nil)
+}
+
+func (m *mapResolver) ResolveAt(curValue []byte, diffValue []byte, blockNum uint64, txNum uint64) ([]byte, error) {
+	return m.resolveMetered(nil, curValue, diffValue, version.NewHeight(blockNum, txNum))
+}
+
+// resolveMetered merges the fields of the diff, charging their merges to the meter of the map
+func (m *mapResolver) resolveMetered(mtr *meter, curValue []byte, diffValue []byte, height *version.Height) ([]byte, error) {
+	fields := map[string]*mapField{}
+	if len(curValue) != 0 {
+		if err := json.Unmarshal(curValue, &fields); err != nil {
+			return []byte(""), mergeErrorf(TypeMismatch, "Invalid CRDT map value: %s", err)
+		}
+	}
+	diffs, err := parseMapDiff(diffValue)
+	if err != nil {
+		return []byte(""), malformedDiff(err)
+	}
+
+	// the fields are merged in a fixed order so that every peer reports the same error
+	names := make([]string, 0, len(diffs))
+	for name := range diffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		diff := diffs[name]
+		if diff == nil || diff.Type == "" {
+			return []byte(""), mergeErrorf(MalformedDiff, "Field %s of the CRDT map diff has no type", name)
+		}
+		field, ok := fields[name]
+		if !ok {
+			field = &mapField{Type: diff.Type}
+			fields[name] = field
+		}
+		if field.Type != diff.Type {
+			return []byte(""), mergeErrorf(TypeMismatch, "Field %s of the CRDT map is a %s, can't merge a %s diff", name, field.Type, diff.Type)
+		}
+		// the limits of the registry apply to the map as a whole
+		merged, err := m.registry.resolve(mtr, field.Value, diff.Diff, diff.Type, height)
+		if err != nil {
+			// the failure of the field is the failure of the map
+			return []byte(""), &MergeError{Failure: MergeFailureOf(err), Err: fmt.Errorf("Field %s of the CRDT map: %s", name, err)}
+		}
+		if !json.Valid(merged) {
+			return []byte(""), mergeErrorf(MalformedDiff, "Field %s of the CRDT map: resolve type %s does not produce JSON values", name, diff.Type)
+		}
+		field.Value = merged
+	}
+
+	return json.Marshal(fields)
+}
+
+func parseMapDiff(diffValue []byte) (map[string]*mapFieldDiff, error) {
+	diffs := map[string]*mapFieldDiff{}
+	if err := json.Unmarshal(diffValue, &diffs); err != nil {
+		return nil, fmt.Errorf("Invalid CRDT map diff: %s", err)
+	}
+	return diffs, nil
+}
+
+// prepareMapDiff prepares the diff of every field of a CRDT map diff
+// against the observed value of the field
+func prepareMapDiff(value []byte, ctx *DiffContext) ([]byte, error) {
+	diffs, err := parseMapDiff(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var observed map[string]*mapField
+	observedField := func(name string) ([]byte, error) {
+		if observed == nil {
+			value, err := ctx.Observed()
+			if err != nil {
+				return nil, err
+			}
+			fields := map[string]*mapField{}
+			if len(value) != 0 {
+				if err := json.Unmarshal(value, &fields); err != nil {
+					return nil, fmt.Errorf("Invalid CRDT map value: %s", err)
+				}
+			}
+			observed = fields
+		}
+		if field, ok := observed[name]; ok {
+			return field.Value, nil
+		}
+		return nil, nil
+	}
+
+	for name, diff := range diffs {
+		if diff == nil {
+			continue
+		}
+		name := name
+		fieldCtx := &DiffContext{
+			TxID:         ctx.TxID,
+			CreatorMSPID: ctx.CreatorMSPID,
+			Observed:     func() ([]byte, error) { return observedField(name) },
+		}
+		if diff.Diff, err = PrepareDiff(diff.Type, diff.Diff, fieldCtx); err != nil {
+			return nil, fmt.Errorf("Field %s of the CRDT map: %s", name, err)
+		}
+	}
+	return json.Marshal(diffs)
+}
@@ -0,0 +1,93 @@
+package crdt_resolver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRDTMapResolve(t *testing.T) {
+	r := NewRegistry()
+	ctx := &DiffContext{
+		TxID:         "tx1",
+		CreatorMSPID: func() (string, error) { return "Org1MSP", nil },
+		Observed:     func() ([]byte, error) { return nil, nil },
+	}
+
+	diff, err := PrepareDiff(CRDTMap, []byte(`{"balance":{"type":"PNCounter","diff":5},"owner":{"type":"LWWRegister","diff":{"value":"alice","timestamp":1}}}`), ctx)
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"balance":{"type":"PNCounter","diff":{"components":{"Org1MSP":{"inc":"5"}},"total":"5"}},"owner":{"type":"LWWRegister","diff":{"value":"alice","timestamp":1}}}`,
+		string(diff),
+	)
+
+	value, err := r.ResolveAt(nil, diff, CRDTMap, version.NewHeight(1, 0))
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"balance":{"type":"PNCounter","value":{"components":{"Org1MSP":{"inc":"5"}},"total":"5"}},"owner":{"type":"LWWRegister","value":{"value":"alice","timestamp":1,"height":{"blockNum":1,"txNum":0}}}}`,
+		string(value),
+	)
+
+	// the fields that are not in the diff are left untouched
+	ctx.Observed = func() ([]byte, error) { return value, nil }
+	diff, err = PrepareDiff(CRDTMap, []byte(`{"balance":{"type":"PNCounter","diff":-2},"tags":{"type":"GSet","diff":{"add":["gold"]}}}`), ctx)
+	require.NoError(t, err)
+	value, err = r.ResolveAt(value, diff, CRDTMap, version.NewHeight(2, 0))
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"balance":{"type":"PNCounter","value":{"components":{"Org1MSP":{"inc":"5","dec":"2"}},"total":"3"}},"owner":{"type":"LWWRegister","value":{"value":"alice","timestamp":1,"height":{"blockNum":1,"txNum":0}}},"tags":{"type":"GSet","value":{"elements":["gold"]}}}`,
+		string(value),
+	)
+
+	_, err = r.ResolveAt(value, []byte(`{"balance":{"type":"IntAdd","diff":1}}`), CRDTMap, version.NewHeight(3, 0))
+	require.EqualError(t, err, "Field balance of the CRDT map is a PNCounter, can't merge a IntAdd diff")
+
+	_, err = r.ResolveAt(value, []byte(`{"balance":{"diff":1}}`), CRDTMap, version.NewHeight(3, 0))
+	require.EqualError(t, err, "Field balance of the CRDT map diff has no type")
+
+	_, err = r.ResolveAt(value, []byte(`{"tags":{"type":"GSet","diff":{"remove":["gold"]}}}`), CRDTMap, version.NewHeight(3, 0))
+	require.EqualError(t, err, "Field tags of the CRDT map: Can't remove elements from a G-Set")
+
+	name, err := r.ResolveAt(nil, []byte(`{"name":{"type":"StringConcat","diff":"alice"}}`), CRDTMap, version.NewHeight(3, 0))
+	require.NoError(t, err)
+	_, err = r.ResolveAt(name, []byte(`{"name":{"type":"StringConcat","diff":"bob"}}`), CRDTMap, version.NewHeight(4, 0))
+	require.EqualError(t, err, "Field name of the CRDT map: resolve type StringConcat does not produce JSON values")
+
+	_, err = r.ResolveAt(nil, []byte(`["balance"]`), CRDTMap, version.NewHeight(3, 0))
+	require.EqualError(t, err, "Invalid CRDT map diff: json: cannot unmarshal array into Go value of type map[string]*crdt_resolver.mapFieldDiff")
+
+	// the height is only needed by the fields whose resolvers use it
+	_, err = r.Resolve(value, []byte(`{"owner":{"type":"LWWRegister","diff":{"value":"bob","timestamp":2}}}`), CRDTMap)
+	require.EqualError(t, err, "Field owner of the CRDT map: Resolver requires the height of the transaction")
+}
+
+func TestPrepareMapDiff(t *testing.T) {
+	observed := []byte(`{"tags":{"type":"ORSet","value":{"elements":["gold"],"tags":{"gold":["tx0"]}}}}`)
+	reads := 0
+	ctx := &DiffContext{
+		TxID:         "tx1",
+		CreatorMSPID: func() (string, error) { return "", errors.New("no creator") },
+		Observed: func() ([]byte, error) {
+			reads++
+			return observed, nil
+		},
+	}
+
+	// the removes of the OR-Set field only affect the tags observed in the field
+	diff, err := PrepareDiff(CRDTMap, []byte(`{"tags":{"type":"ORSet","diff":{"remove":["gold"]}},"owners":{"type":"ORSet","diff":{"add":["alice"]}}}`), ctx)
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"tags":{"type":"ORSet","diff":{"remove":{"gold":["tx0"]}}},"owners":{"type":"ORSet","diff":{"add":{"alice":["tx1"]}}}}`,
+		string(diff),
+	)
+	require.Equal(t, 1, reads)
+
+	_, err = PrepareDiff(CRDTMap, []byte(`{"balance":{"type":"PNCounter","diff":1}}`), ctx)
+	require.EqualError(t, err, "Field balance of the CRDT map: no creator")
+
+	ctx.Observed = func() ([]byte, error) { return []byte(`"alice"`), nil }
+	_, err = PrepareDiff(CRDTMap, []byte(`{"tags":{"type":"ORSet","diff":{"add":["gold"]}}}`), ctx)
+	require.EqualError(t, err, "Field tags of the CRDT map: Invalid CRDT map value: json: cannot unmarshal string into Go value of type map[string]*crdt_resolver.mapField")
+}
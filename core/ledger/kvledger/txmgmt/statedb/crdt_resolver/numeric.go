@@ -0,0 +1,155 @@
+package crdt_resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// numericDiff is the diff of the arbitrary-precision resolvers. A diff is either
+// a plain number, e.g. "42" or "-0.5", or a JSON object that carries the number
+// together with the bounds the merged value has to stay within, e.g.
+// {"value":"42","min":"0","max":"115792089237316195423570985008687907853269984665640564039457584007913129639935"}.
+// The bounds are optional and are part of the transaction, hence every peer applies the same ones
+type numericDiff struct {
+	Value string `json:"value"`
+	Min   string `json:"min,omitempty"`
+	Max   string `json:"max,omitempty"`
+}
+
+func parseNumericDiff(diffValue []byte) (*numericDiff, error) {
+	trimmed := strings.TrimSpace(string(diffValue))
+	if !strings.HasPrefix(trimmed, "{") {
+		return &numericDiff{Value: trimmed}, nil
+	}
+	diff := &numericDiff{}
+	if err := json.Unmarshal([]byte(trimmed), diff); err != nil {
+		return nil, fmt.Errorf("Invalid numeric diff: %s", err)
+	}
+	return diff, nil
+}
+
+// decimal is an exact fixed-point number equal to unscaled * 10^-scale
+type decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// parseDecimal parses a number in plain decimal notation, with an optional
+// leading '-' and an optional fractional part. Exponents, hex literals and
+// special values are rejected so that every peer reads the same number
+func parseDecimal(s string) (*decimal, error) {
+	digits := s
+	if strings.HasPrefix(digits, "-") {
+		digits = digits[1:]
+	}
+	intPart, fracPart := digits, ""
+	if i := strings.IndexByte(digits, '.'); i != -1 {
+		intPart, fracPart = digits[:i], digits[i+1:]
+		if fracPart == "" {
+			return nil, fmt.Errorf("Invalid number %q", s)
+		}
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("Invalid number %q", s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return nil, fmt.Errorf("Invalid number %q", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+	return &decimal{unscaled: unscaled, scale: len(fracPart)}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// rescale returns the unscaled value of d expressed with the given, not smaller, scale
+func (d *decimal) rescale(scale int) *big.Int {
+	if scale == d.scale {
+		return new(big.Int).Set(d.unscaled)
+	}
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale-d.scale)), nil)
+	return new(big.Int).Mul(d.unscaled, factor)
+}
+
+func (d *decimal) add(o *decimal) *decimal {
+	scale := d.scale
+	if o.scale > scale {
+		scale = o.scale
+	}
+	return &decimal{unscaled: new(big.Int).Add(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+func (d *decimal) cmp(o *decimal) int {
+	scale := d.scale
+	if o.scale > scale {
+		scale = o.scale
+	}
+	return d.rescale(scale).Cmp(o.rescale(scale))
+}
+
+// String returns the canonical encoding of d: no leading zeros in the integer
+// part, no trailing zeros in the fractional part, no fractional part for
+// integral values and no sign for zero
+func (d *decimal) String() string {
+	abs := new(big.Int).Abs(d.unscaled).String()
+	scale := d.scale
+	for scale > 0 && strings.HasSuffix(abs, "0") && len(abs) > 1 {
+		abs = abs[:len(abs)-1]
+		scale--
+	}
+	if abs == "0" {
+		return "0"
+	}
+	if scale > 0 {
+		if len(abs) <= scale {
+			abs = strings.Repeat("0", scale-len(abs)+1) + abs
+		}
+		abs = abs[:len(abs)-scale] + "." + abs[len(abs)-scale:]
+	}
+	if d.unscaled.Sign() < 0 {
+		return "-" + abs
+	}
+	return abs
+}
+
+// numericResolve merges a numeric diff into the current value. The current value
+// defaults to zero if the key does not exist yet. When subtracting, the diff must
+// not be negative. If integral is set, the values must not have a fractional part
+func numericResolve(curValue []byte, diffValue []byte, subtract bool, integral bool) ([]byte, error) {
+	acc, err := numericAccumulatorFunc(subtract, integral)(curValue)
+	if err != nil {
+		return []byte(""), err
+	}
+	if err := acc.Add(diffValue); err != nil {
+		return []byte(""), err
+	}
+	return acc.Value(), nil
+}
+
+func bigIntAddResolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return numericResolve(curValue, diffValue, false, true)
+}
+
+func bigIntSubResolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return numericResolve(curValue, diffValue, true, true)
+}
+
+func decimalAddResolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return numericResolve(curValue, diffValue, false, false)
+}
+
+func decimalSubResolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return numericResolve(curValue, diffValue, true, false)
+}
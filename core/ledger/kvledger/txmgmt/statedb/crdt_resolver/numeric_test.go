@@ -0,0 +1,100 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const maxUint256 = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+
+func TestBigIntResolvers(t *testing.T) {
+	tests := []struct {
+		name    string
+		resolve func([]byte, []byte) ([]byte, error)
+		cur     string
+		diff    string
+		res     string
+		err     string
+	}{
+		{name: "add to missing key", resolve: bigIntAddResolve, diff: "7", res: "7"},
+		{name: "add beyond int64", resolve: bigIntAddResolve, cur: "9223372036854775807", diff: "1", res: "9223372036854775808"},
+		{name: "add negative", resolve: bigIntAddResolve, cur: "5", diff: "-7", res: "-2"},
+		{name: "canonical encoding", resolve: bigIntAddResolve, cur: "005", diff: "-5", res: "0"},
+		{name: "sub", resolve: bigIntSubResolve, cur: "10", diff: "3", res: "7"},
+		{name: "sub negative diff", resolve: bigIntSubResolve, cur: "10", diff: "-3", err: "Can't have negative diff"},
+		{name: "fraction", resolve: bigIntAddResolve, cur: "10", diff: "1.5", err: `Invalid integer "1.5"`},
+		{name: "exponent", resolve: bigIntAddResolve, cur: "10", diff: "1e3", err: `Invalid number "1e3"`},
+		{name: "hex", resolve: bigIntAddResolve, cur: "10", diff: "0x10", err: `Invalid number "0x10"`},
+		{name: "invalid current value", resolve: bigIntAddResolve, cur: "abc", diff: "1", err: `Invalid number "abc"`},
+		{
+			name:    "within bounds",
+			resolve: bigIntAddResolve,
+			cur:     "115792089237316195423570985008687907853269984665640564039457584007913129639934",
+			diff:    `{"value":"1","min":"0","max":"` + maxUint256 + `"}`,
+			res:     maxUint256,
+		},
+		{
+			name:    "above upper bound",
+			resolve: bigIntAddResolve,
+			cur:     maxUint256,
+			diff:    `{"value":"1","max":"` + maxUint256 + `"}`,
+			err:     "Result 115792089237316195423570985008687907853269984665640564039457584007913129639936 is above the upper bound " + maxUint256,
+		},
+		{
+			name:    "below lower bound",
+			resolve: bigIntSubResolve,
+			cur:     "3",
+			diff:    `{"value":"5","min":"0"}`,
+			err:     "Result -2 is below the lower bound 0",
+		},
+		{name: "invalid JSON diff", resolve: bigIntSubResolve, cur: "3", diff: `{"value":5}`, err: "Invalid numeric diff: json: cannot unmarshal number into Go struct field numericDiff.value of type string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.resolve([]byte(tt.cur), []byte(tt.diff))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.res, string(res))
+		})
+	}
+}
+
+func TestDecimalResolvers(t *testing.T) {
+	tests := []struct {
+		name    string
+		resolve func([]byte, []byte) ([]byte, error)
+		cur     string
+		diff    string
+		res     string
+		err     string
+	}{
+		{name: "add to missing key", resolve: decimalAddResolve, diff: "0.10", res: "0.1"},
+		{name: "add", resolve: decimalAddResolve, cur: "0.1", diff: "0.2", res: "0.3"},
+		{name: "add integral", resolve: decimalAddResolve, cur: "1.25", diff: "2.75", res: "4"},
+		{name: "canonical encoding", resolve: decimalAddResolve, cur: "0010.500", diff: "0", res: "10.5"},
+		{name: "add negative", resolve: decimalAddResolve, cur: "1", diff: "-1.005", res: "-0.005"},
+		{name: "sub", resolve: decimalSubResolve, cur: "10.5", diff: "0.25", res: "10.25"},
+		{name: "sub to zero", resolve: decimalSubResolve, cur: "-0.5", diff: "-0.5", err: "Can't have negative diff"},
+		{name: "missing integer part", resolve: decimalAddResolve, cur: "1", diff: ".5", err: `Invalid number ".5"`},
+		{name: "missing fractional part", resolve: decimalAddResolve, cur: "1", diff: "5.", err: `Invalid number "5."`},
+		{name: "exponent", resolve: decimalAddResolve, cur: "1", diff: "1.5e2", err: `Invalid number "1.5e2"`},
+		{name: "lower bound", resolve: decimalSubResolve, cur: "1.5", diff: `{"value":"1.51","min":"0"}`, err: "Result -0.01 is below the lower bound 0"},
+		{name: "upper bound", resolve: decimalAddResolve, cur: "99.99", diff: `{"value":"0.02","max":"100.00"}`, err: "Result 100.01 is above the upper bound 100"},
+		{name: "invalid bound", resolve: decimalAddResolve, cur: "1", diff: `{"value":"1","max":"inf"}`, err: `Invalid number "inf"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.resolve([]byte(tt.cur), []byte(tt.diff))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.res, string(res))
+		})
+	}
+}
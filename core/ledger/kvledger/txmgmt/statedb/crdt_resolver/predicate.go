@@ -0,0 +1,114 @@
+package crdt_resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+)
+
+// PredicateError is returned by CheckPredicates when a predicate does not hold
+// for the current value of a key, as opposed to a malformed predicate
+type PredicateError struct {
+	Operator kvrwset.CRDTPredicate_Operator
+	Reason   string
+}
+
+func (e *PredicateError) Error() string {
+	return fmt.Sprintf("Predicate %s failed: %s", e.Operator, e.Reason)
+}
+
+// CheckPredicates evaluates the predicates of a CRDT payload against the current value
+// of a key of the given resolution type. The numeric predicates compare the value of the
+// numeric keys and the total of the counters, a key that does not exist being zero.
+// A *PredicateError is returned for the first predicate that does not hold
+func CheckPredicates(curValue []byte, resType string, predicates []*kvrwset.CRDTPredicate) error {
+	for _, predicate := range predicates {
+		if err := checkPredicate(curValue, resType, predicate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkPredicate(curValue []byte, resType string, predicate *kvrwset.CRDTPredicate) error {
+	failed := func(format string, args ...interface{}) error {
+		return &PredicateError{Operator: predicate.Operator, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	switch predicate.Operator {
+	case kvrwset.CRDTPredicate_GREATER_OR_EQUAL, kvrwset.CRDTPredicate_LESS_OR_EQUAL:
+		cur, err := numericValue(curValue, resType)
+		if err != nil {
+			return mergeErrorf(TypeMismatch, "Predicate %s requires a numeric value: %s", predicate.Operator, err)
+		}
+		operand, err := parseDecimal(strings.TrimSpace(string(predicate.Operand)))
+		if err != nil {
+			return mergeErrorf(MalformedDiff, "Invalid operand of predicate %s: %s", predicate.Operator, err)
+		}
+		cmp := cur.cmp(operand)
+		if predicate.Operator == kvrwset.CRDTPredicate_GREATER_OR_EQUAL && cmp < 0 {
+			return failed("current value %s is less than %s", cur, operand)
+		}
+		if predicate.Operator == kvrwset.CRDTPredicate_LESS_OR_EQUAL && cmp > 0 {
+			return failed("current value %s is greater than %s", cur, operand)
+		}
+		return nil
+	case kvrwset.CRDTPredicate_EQUALS:
+		// the numbers are compared by their value, so that e.g. "5" equals "5.0"
+		if cur, err := numericValue(curValue, resType); err == nil {
+			if operand, err := parseDecimal(strings.TrimSpace(string(predicate.Operand))); err == nil {
+				if cur.cmp(operand) != 0 {
+					return failed("current value %s is not equal to %s", cur, operand)
+				}
+				return nil
+			}
+		}
+		if !bytes.Equal(curValue, predicate.Operand) {
+			return failed("current value is not equal to %q", predicate.Operand)
+		}
+		return nil
+	case kvrwset.CRDTPredicate_EXISTS:
+		if len(curValue) == 0 {
+			return failed("key does not exist")
+		}
+		return nil
+	case kvrwset.CRDTPredicate_NOT_EXISTS:
+		if len(curValue) != 0 {
+			return failed("key already exists")
+		}
+		return nil
+	case kvrwset.CRDTPredicate_SET_CONTAINS:
+		if !IsSet(resType) {
+			return mergeErrorf(MalformedDiff, "Predicate %s requires a set, got resolve type %s", predicate.Operator, resType)
+		}
+		contains, err := SetContains(curValue, string(predicate.Operand))
+		if err != nil {
+			return typeMismatch(err)
+		}
+		if !contains {
+			return failed("set does not contain %q", predicate.Operand)
+		}
+		return nil
+	default:
+		return mergeErrorf(MalformedDiff, "Unknown predicate operator %s", predicate.Operator)
+	}
+}
+
+// numericValue returns the number held by the current value of a key
+func numericValue(curValue []byte, resType string) (*decimal, error) {
+	if len(curValue) == 0 {
+		return &decimal{unscaled: new(big.Int)}, nil
+	}
+	if IsCounter(resType) {
+		state := &counterState{}
+		if err := json.Unmarshal(curValue, state); err != nil {
+			return nil, fmt.Errorf("Invalid counter value: %s", err)
+		}
+		return parseDecimal(state.Total)
+	}
+	return parseDecimal(strings.TrimSpace(string(curValue)))
+}
@@ -0,0 +1,78 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPredicates(t *testing.T) {
+	predicate := func(op kvrwset.CRDTPredicate_Operator, operand string) *kvrwset.CRDTPredicate {
+		return &kvrwset.CRDTPredicate{Operator: op, Operand: []byte(operand)}
+	}
+	counter := []byte(`{"components":{"Org1MSP":{"inc":"5","dec":"2"}},"total":"3"}`)
+	set := []byte(`{"elements":["gold","silver"]}`)
+
+	tests := []struct {
+		name      string
+		curValue  []byte
+		resType   string
+		predicate *kvrwset.CRDTPredicate
+		err       string
+	}{
+		{"gte holds", []byte("10"), "UintSub", predicate(kvrwset.CRDTPredicate_GREATER_OR_EQUAL, "10"), ""},
+		{"gte fails", []byte("9"), "UintSub", predicate(kvrwset.CRDTPredicate_GREATER_OR_EQUAL, "10"), "Predicate GREATER_OR_EQUAL failed: current value 9 is less than 10"},
+		{"gte on missing key", nil, "IntAdd", predicate(kvrwset.CRDTPredicate_GREATER_OR_EQUAL, "1"), "Predicate GREATER_OR_EQUAL failed: current value 0 is less than 1"},
+		{"lte on decimal", []byte("1.50"), "DecimalAdd", predicate(kvrwset.CRDTPredicate_LESS_OR_EQUAL, "1.5"), ""},
+		{"lte fails", []byte("1.51"), "DecimalAdd", predicate(kvrwset.CRDTPredicate_LESS_OR_EQUAL, "1.5"), "Predicate LESS_OR_EQUAL failed: current value 1.51 is greater than 1.5"},
+		{"gte on counter total", counter, PNCounter, predicate(kvrwset.CRDTPredicate_GREATER_OR_EQUAL, "3"), ""},
+		{"lte on counter total", counter, PNCounter, predicate(kvrwset.CRDTPredicate_LESS_OR_EQUAL, "2"), "Predicate LESS_OR_EQUAL failed: current value 3 is greater than 2"},
+		{"gte on non numeric value", []byte("abc"), "StringConcat", predicate(kvrwset.CRDTPredicate_GREATER_OR_EQUAL, "1"), `Predicate GREATER_OR_EQUAL requires a numeric value: Invalid number "abc"`},
+		{"gte with invalid operand", []byte("1"), "IntAdd", predicate(kvrwset.CRDTPredicate_GREATER_OR_EQUAL, "1e3"), `Invalid operand of predicate GREATER_OR_EQUAL: Invalid number "1e3"`},
+		{"equals numbers", []byte("5"), "IntAdd", predicate(kvrwset.CRDTPredicate_EQUALS, "5.0"), ""},
+		{"equals numbers fails", []byte("5"), "IntAdd", predicate(kvrwset.CRDTPredicate_EQUALS, "6"), "Predicate EQUALS failed: current value 5 is not equal to 6"},
+		{"equals bytes", []byte("abc"), "StringConcat", predicate(kvrwset.CRDTPredicate_EQUALS, "abc"), ""},
+		{"equals bytes fails", []byte("abc"), "StringConcat", predicate(kvrwset.CRDTPredicate_EQUALS, "ab"), `Predicate EQUALS failed: current value is not equal to "ab"`},
+		{"exists", []byte("abc"), "StringConcat", predicate(kvrwset.CRDTPredicate_EXISTS, ""), ""},
+		{"exists fails", nil, "StringConcat", predicate(kvrwset.CRDTPredicate_EXISTS, ""), "Predicate EXISTS failed: key does not exist"},
+		{"not exists", nil, "Set", predicate(kvrwset.CRDTPredicate_NOT_EXISTS, ""), ""},
+		{"not exists fails", []byte("abc"), "Set", predicate(kvrwset.CRDTPredicate_NOT_EXISTS, ""), "Predicate NOT_EXISTS failed: key already exists"},
+		{"set contains", set, ORSet, predicate(kvrwset.CRDTPredicate_SET_CONTAINS, "gold"), ""},
+		{"set contains fails", set, GSet, predicate(kvrwset.CRDTPredicate_SET_CONTAINS, "bronze"), `Predicate SET_CONTAINS failed: set does not contain "bronze"`},
+		{"set contains on missing key", nil, TwoPSet, predicate(kvrwset.CRDTPredicate_SET_CONTAINS, "gold"), `Predicate SET_CONTAINS failed: set does not contain "gold"`},
+		{"set contains on non set", []byte("gold"), "Set", predicate(kvrwset.CRDTPredicate_SET_CONTAINS, "gold"), "Predicate SET_CONTAINS requires a set, got resolve type Set"},
+		{"undefined operator", []byte("1"), "IntAdd", predicate(kvrwset.CRDTPredicate_UNDEFINED, "1"), "Unknown predicate operator UNDEFINED"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPredicates(tt.curValue, tt.resType, []*kvrwset.CRDTPredicate{tt.predicate})
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestCheckPredicatesError(t *testing.T) {
+	predicates := []*kvrwset.CRDTPredicate{
+		{Operator: kvrwset.CRDTPredicate_EXISTS},
+		{Operator: kvrwset.CRDTPredicate_GREATER_OR_EQUAL, Operand: []byte("10")},
+		{Operator: kvrwset.CRDTPredicate_LESS_OR_EQUAL, Operand: []byte("1")},
+	}
+
+	// the first predicate that does not hold is reported
+	err := CheckPredicates([]byte("5"), "IntAdd", predicates)
+	require.IsType(t, &PredicateError{}, err)
+	require.Equal(t, kvrwset.CRDTPredicate_GREATER_OR_EQUAL, err.(*PredicateError).Operator)
+
+	// the malformed predicates are not predicate failures
+	err = CheckPredicates([]byte("5"), "IntAdd", []*kvrwset.CRDTPredicate{{Operator: kvrwset.CRDTPredicate_EQUALS + 10}})
+	require.Error(t, err)
+	_, ok := err.(*PredicateError)
+	require.False(t, ok)
+}
@@ -0,0 +1,158 @@
+package crdt_resolver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+)
+
+const (
+	// LWWRegister is the resolution type of the last-writer-wins registers
+	LWWRegister = "LWWRegister"
+	// MVRegister is the resolution type of the multi-value registers
+	MVRegister = "MVRegister"
+)
+
+// heightAwareResolverFunc is an adapter that allows the use of an ordinary
+// function charging a meter as a crdt.HeightAwareResolver
+type heightAwareResolverFunc func(m *meter, curValue []byte, diffValue []byte, blockNum uint64, txNum uint64) ([]byte, error)
+
+func (f heightAwareResolverFunc) Resolve(curValue []byte, diffValue []byte) ([]byte, error) {
+	return []byte(""), fmt.Errorf("Resolver requires the height of the transaction")
+}
+
+func (f heightAwareResolverFunc) ResolveAt(curValue []byte, diffValue []byte, blockNum uint64, txNum uint64) ([]byte, error) {
+	return f(nil, curValue, diffValue, blockNum, txNum)
+}
+
+func (f heightAwareResolverFunc) resolveMetered(m *meter, curValue []byte, diffValue []byte, height *version.Height) ([]byte, error) {
+	if height == nil {
+		return f.Resolve(curValue, diffValue)
+	}
+	return f(m, curValue, diffValue, height.BlockNum, height.TxNum)
+}
+
+// registerHeight is the height of the transaction that wrote a register
+type registerHeight struct {
+	BlockNum uint64 `json:"blockNum"`
+	TxNum    uint64 `json:"txNum"`
+}
+
+func (h registerHeight) less(o registerHeight) bool {
+	return h.BlockNum < o.BlockNum || (h.BlockNum == o.BlockNum && h.TxNum < o.TxNum)
+}
+
+// lwwRegister is both the value of an LWW-Register key and the diff sent by chaincode,
+// e.g. {"value":"alice","timestamp":42}. The value can be any JSON value and the timestamp
+// is a logical timestamp chosen by the client. The write with the greatest timestamp wins
+// and the ties are broken by the height of the transactions, the latest one winning
+type lwwRegister struct {
+	Value     json.RawMessage `json:"value"`
+	Timestamp uint64          `json:"timestamp"`
+	Height    *registerHeight `json:"height,omitempty"`
+}
+
+func lwwRegisterResolve(_ *meter, curValue []byte, diffValue []byte, blockNum uint64, txNum uint64) ([]byte, error) {
+	diff := &lwwRegister{}
+	if err := json.Unmarshal(diffValue, diff); err != nil {
+		return []byte(""), mergeErrorf(MalformedDiff, "Invalid LWW-Register diff: %s", err)
+	}
+	if len(diff.Value) == 0 {
+		return []byte(""), mergeErrorf(MalformedDiff, "LWW-Register diff has no value")
+	}
+	diff.Height = &registerHeight{BlockNum: blockNum, TxNum: txNum}
+
+	if len(curValue) != 0 {
+		cur := &lwwRegister{}
+		if err := json.Unmarshal(curValue, cur); err != nil {
+			return []byte(""), mergeErrorf(TypeMismatch, "Invalid LWW-Register value: %s", err)
+		}
+		// the current value was committed at a lower height, hence
+		// it only wins if its timestamp is greater
+		if cur.Timestamp > diff.Timestamp {
+			return json.Marshal(cur)
+		}
+	}
+	return json.Marshal(diff)
+}
+
+// mvRegister is the value of a Multi-Value Register key. It holds the values of the
+// writes that are concurrent with each other, sorted by the height of the transactions,
+// e.g. {"values":[{"value":"alice","height":{"blockNum":5,"txNum":0}},{"value":"bob","height":{"blockNum":5,"txNum":1}}]}
+type mvRegister struct {
+	Values []*mvRegisterEntry `json:"values"`
+}
+
+type mvRegisterEntry struct {
+	Value  json.RawMessage `json:"value"`
+	Height registerHeight  `json:"height"`
+}
+
+// mvRegisterDelta is the diff of the Multi-Value Register resolver. A write
+// overwrites the values it observed and is concurrent with the other ones
+type mvRegisterDelta struct {
+	Value    json.RawMessage  `json:"value"`
+	Observed []registerHeight `json:"observed,omitempty"`
+}
+
+// newMVRegisterDelta converts the value written by chaincode to a Multi-Value Register
+// into the diff merged by the resolver. The write overwrites the values observed in the
+// committed value of the register, while the writes committed concurrently are kept
+func newMVRegisterDelta(observed []byte, value []byte) ([]byte, error) {
+	if !json.Valid(value) {
+		return nil, fmt.Errorf("Multi-Value Register value must be valid JSON")
+	}
+	delta := &mvRegisterDelta{Value: value}
+	if len(observed) != 0 {
+		observedState := &mvRegister{}
+		if err := json.Unmarshal(observed, observedState); err != nil {
+			return nil, fmt.Errorf("Invalid Multi-Value Register value: %s", err)
+		}
+		for _, entry := range observedState.Values {
+			delta.Observed = append(delta.Observed, entry.Height)
+		}
+	}
+	return json.Marshal(delta)
+}
+
+func mvRegisterResolve(m *meter, curValue []byte, diffValue []byte, blockNum uint64, txNum uint64) ([]byte, error) {
+	delta := &mvRegisterDelta{}
+	if err := json.Unmarshal(diffValue, delta); err != nil {
+		return []byte(""), mergeErrorf(MalformedDiff, "Invalid Multi-Value Register diff: %s", err)
+	}
+	if len(delta.Value) == 0 {
+		return []byte(""), mergeErrorf(MalformedDiff, "Multi-Value Register diff has no value")
+	}
+
+	cur := &mvRegister{}
+	if len(curValue) != 0 {
+		if err := json.Unmarshal(curValue, cur); err != nil {
+			return []byte(""), mergeErrorf(TypeMismatch, "Invalid Multi-Value Register value: %s", err)
+		}
+	}
+	if err := m.charge(len(cur.Values) + len(delta.Observed)); err != nil {
+		return []byte(""), err
+	}
+
+	// a write also overwrites the value written earlier by the same transaction
+	height := registerHeight{BlockNum: blockNum, TxNum: txNum}
+	overwritten := map[registerHeight]struct{}{height: {}}
+	for _, h := range delta.Observed {
+		overwritten[h] = struct{}{}
+	}
+	merged := &mvRegister{Values: []*mvRegisterEntry{}}
+	for _, entry := range cur.Values {
+		if _, ok := overwritten[entry.Height]; ok {
+			continue
+		}
+		if !entry.Height.less(height) {
+			return []byte(""), fmt.Errorf("Multi-Value Register contains a value written after height %d:%d", blockNum, txNum)
+		}
+		merged.Values = append(merged.Values, entry)
+	}
+	// the committing transaction is the latest one, so the entries stay sorted by height
+	merged.Values = append(merged.Values, &mvRegisterEntry{Value: delta.Value, Height: height})
+
+	return json.Marshal(merged)
+}
@@ -0,0 +1,73 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLWWRegisterResolve(t *testing.T) {
+	r := NewRegistry()
+
+	_, err := r.Resolve(nil, []byte(`{"value":"alice","timestamp":1}`), LWWRegister)
+	require.EqualError(t, err, "Resolver requires the height of the transaction")
+
+	value, err := r.ResolveAt(nil, []byte(`{"value":"alice","timestamp":5}`), LWWRegister, version.NewHeight(1, 0))
+	require.NoError(t, err)
+	require.Equal(t, `{"value":"alice","timestamp":5,"height":{"blockNum":1,"txNum":0}}`, string(value))
+
+	// an older timestamp loses even if it is committed later
+	res, err := r.ResolveAt(value, []byte(`{"value":"bob","timestamp":4}`), LWWRegister, version.NewHeight(2, 0))
+	require.NoError(t, err)
+	require.Equal(t, string(value), string(res))
+
+	// ties are broken by the height
+	res, err = r.ResolveAt(value, []byte(`{"value": {"name": "bob"}, "timestamp": 5}`), LWWRegister, version.NewHeight(2, 1))
+	require.NoError(t, err)
+	require.Equal(t, `{"value":{"name":"bob"},"timestamp":5,"height":{"blockNum":2,"txNum":1}}`, string(res))
+
+	_, err = r.ResolveAt(value, []byte(`{"timestamp":6}`), LWWRegister, version.NewHeight(2, 0))
+	require.EqualError(t, err, "LWW-Register diff has no value")
+
+	_, err = r.ResolveAt(value, []byte(`{"value":alice}`), LWWRegister, version.NewHeight(2, 0))
+	require.EqualError(t, err, "Invalid LWW-Register diff: invalid character 'a' looking for beginning of value")
+}
+
+func TestMVRegisterResolve(t *testing.T) {
+	r := NewRegistry()
+	write := func(cur []byte, observed []byte, value string, height *version.Height) []byte {
+		delta, err := newMVRegisterDelta(observed, []byte(value))
+		require.NoError(t, err)
+		res, err := r.ResolveAt(cur, delta, MVRegister, height)
+		require.NoError(t, err)
+		return res
+	}
+
+	value := write(nil, nil, `"alice"`, version.NewHeight(1, 0))
+	require.Equal(t, `{"values":[{"value":"alice","height":{"blockNum":1,"txNum":0}}]}`, string(value))
+
+	// two writes simulated against the same committed value are concurrent
+	committed := value
+	value = write(value, committed, `"bob"`, version.NewHeight(2, 0))
+	value = write(value, committed, `"carol"`, version.NewHeight(2, 1))
+	require.Equal(t,
+		`{"values":[{"value":"bob","height":{"blockNum":2,"txNum":0}},{"value":"carol","height":{"blockNum":2,"txNum":1}}]}`,
+		string(value),
+	)
+
+	// a later write that observed both of them resolves the conflict
+	value = write(value, value, `"dave"`, version.NewHeight(3, 0))
+	require.Equal(t, `{"values":[{"value":"dave","height":{"blockNum":3,"txNum":0}}]}`, string(value))
+
+	// a transaction overwrites its own writes
+	value = write(value, value, `"erin"`, version.NewHeight(4, 0))
+	value = write(value, nil, `"frank"`, version.NewHeight(4, 0))
+	require.Equal(t, `{"values":[{"value":"frank","height":{"blockNum":4,"txNum":0}}]}`, string(value))
+
+	_, err := r.ResolveAt(value, []byte(`{"value":"grace"}`), MVRegister, version.NewHeight(3, 0))
+	require.EqualError(t, err, "Multi-Value Register contains a value written after height 3:0")
+
+	_, err = newMVRegisterDelta(nil, []byte("grace"))
+	require.EqualError(t, err, "Multi-Value Register value must be valid JSON")
+}
@@ -0,0 +1,132 @@
+package crdt_resolver
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric/core/handlers/crdt"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+)
+
+// Registry maps resolution types to the resolvers that implement them.
+// A registry is populated when the peer starts and is only read afterwards,
+// hence it is not safe to call Register, EnableTestResolvers or SetLimits
+// concurrently with the other methods
+type Registry struct {
+	resolvers    map[string]crdt.Resolver
+	accumulators map[string]accumulatorFunc
+	limits       Limits
+}
+
+// NewRegistry constructs a registry that contains the builtin resolvers
+func NewRegistry() *Registry {
+	r := &Registry{
+		resolvers:    builtinResolvers(),
+		accumulators: builtinAccumulators(),
+	}
+	// the fields of a CRDT map are merged by the resolvers of this registry,
+	// including the ones registered later on
+	r.resolvers[CRDTMap] = &mapResolver{registry: r}
+	return r
+}
+
+// Register adds a resolver for the given resolution type, replacing
+// the existing one if any
+func (r *Registry) Register(resType string, resolver crdt.Resolver) {
+	r.resolvers[resType] = resolver
+	// the accumulator of a builtin resolver does not apply to the resolver replacing it
+	delete(r.accumulators, resType)
+}
+
+// EnableTestResolvers registers the resolvers that are only meant for testing. They must not be
+// available on the peers of a production network, as e.g. the Wait resolver lets a transaction
+// stall the commit of a block
+func (r *Registry) EnableTestResolvers() {
+	for resType, resolver := range testResolvers() {
+		r.Register(resType, resolver)
+	}
+}
+
+// SetLimits sets the limits the merges of the registry are subject to
+func (r *Registry) SetLimits(limits Limits) {
+	r.limits = limits
+}
+
+// Limits returns the limits the merges of the registry are subject to
+func (r *Registry) Limits() Limits {
+	return r.limits
+}
+
+// Lookup returns the resolver registered for the given resolution type
+func (r *Registry) Lookup(resType string) (crdt.Resolver, bool) {
+	resolver, ok := r.resolvers[resType]
+	return resolver, ok
+}
+
+// NewAccumulator returns an accumulator holding the current value of a key if the resolver
+// registered for the given resolution type supports accumulating diffs. A nil accumulator
+// and a nil error are returned otherwise. If the registry limits the size of the values, the
+// accumulator returns ErrNotAccumulated for the diffs it leaves to the resolver
+func (r *Registry) NewAccumulator(curValue []byte, resType string) (Accumulator, error) {
+	newAccumulator, ok := r.accumulators[resType]
+	if !ok {
+		return nil, nil
+	}
+	acc, err := newAccumulator(curValue)
+	if err != nil || r.limits.MaxValueSize == 0 {
+		return acc, err
+	}
+	return &boundedAccumulator{Accumulator: acc, size: len(curValue), maxSize: r.limits.MaxValueSize}, nil
+}
+
+// Types returns the sorted list of the resolution types supported by the registry
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.resolvers))
+	for resType := range r.resolvers {
+		types = append(types, resType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Resolve merges the diff into the current value using the resolver
+// registered for the given resolution type
+func (r *Registry) Resolve(curValue []byte, diffValue []byte, resType string) ([]byte, error) {
+	return r.ResolveAt(curValue, diffValue, resType, nil)
+}
+
+// ResolveAt is like Resolve but also passes the height of the committing
+// transaction, if known, to the resolvers that implement crdt.HeightAwareResolver
+func (r *Registry) ResolveAt(curValue []byte, diffValue []byte, resType string, height *version.Height) ([]byte, error) {
+	if r.limits == (Limits{}) {
+		return r.resolve(nil, curValue, diffValue, resType, height)
+	}
+	return r.resolveWithinLimits(curValue, diffValue, resType, height)
+}
+
+// resolve merges the diff into the current value regardless of the size limits of the registry. Every merge is
+// charged one step to the meter, the builtin resolvers charge the steps they take on top of it. The errors of the
+// resolvers are returned as a MergeError, MergeFailed unless the resolver classified them
+func (r *Registry) resolve(m *meter, curValue []byte, diffValue []byte, resType string, height *version.Height) ([]byte, error) {
+	resolver, ok := r.resolvers[resType]
+	if !ok {
+		return []byte(""), mergeErrorf(UnknownType, "Unknown resolve type %s", resType)
+	}
+	if err := m.charge(1); err != nil {
+		return []byte(""), err
+	}
+	var merged []byte
+	var err error
+	switch r := resolver.(type) {
+	case meteredResolver:
+		merged, err = r.resolveMetered(m, curValue, diffValue, height)
+	case crdt.HeightAwareResolver:
+		if height != nil {
+			merged, err = r.ResolveAt(curValue, diffValue, height.BlockNum, height.TxNum)
+		} else {
+			merged, err = r.Resolve(curValue, diffValue)
+		}
+	default:
+		merged, err = resolver.Resolve(curValue, diffValue)
+	}
+	return merged, mergeError(MergeFailed, err)
+}
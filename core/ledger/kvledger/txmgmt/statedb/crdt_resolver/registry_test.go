@@ -0,0 +1,68 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/handlers/crdt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryBuiltins(t *testing.T) {
+	r := NewRegistry()
+	require.Equal(t,
+		[]string{"ArrayAppend", "BigIntAdd", "BigIntSub", "CRDTMap", "DecimalAdd", "DecimalSub", "GCounter", "GSet", "IntAdd", "JSONMergePatch", "JSONPatch", "LWWRegister", "MVRegister", "ORSet", "PNCounter", "Set", "StringConcat", "TwoPSet", "UintSub"},
+		r.Types(),
+	)
+
+	res, err := r.Resolve([]byte("5"), []byte("7"), "IntAdd")
+	require.NoError(t, err)
+	require.Equal(t, []byte("12"), res)
+
+	res, err = r.Resolve([]byte("5"), []byte("-7"), "IntAdd")
+	require.NoError(t, err)
+	require.Equal(t, []byte("-2"), res)
+
+	res, err = r.Resolve([]byte("5"), []byte("3"), "UintSub")
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), res)
+
+	_, err = r.Resolve([]byte("5"), []byte("7"), "UintSub")
+	require.EqualError(t, err, "Negative result")
+
+	res, err = r.Resolve([]byte(`[1]`), []byte(`[2,3]`), "ArrayAppend")
+	require.NoError(t, err)
+	require.Equal(t, []byte(`[1,2,3]`), res)
+
+	_, err = r.Resolve(nil, []byte("1"), "IntAd")
+	require.EqualError(t, err, "Unknown resolve type IntAd")
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	max := crdt.ResolverFunc(func(curValue []byte, diffValue []byte) ([]byte, error) {
+		if string(diffValue) > string(curValue) {
+			return diffValue, nil
+		}
+		return curValue, nil
+	})
+	r.Register("Max", max)
+
+	_, ok := r.Lookup("Max")
+	require.True(t, ok)
+	require.Contains(t, r.Types(), "Max")
+
+	res, err := r.Resolve([]byte("b"), []byte("a"), "Max")
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), res)
+
+	// builtin resolvers can be overridden
+	r.Register("Set", max)
+	res, err = r.Resolve([]byte("b"), []byte("a"), "Set")
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), res)
+
+	// registries do not share their resolvers
+	res, err = NewRegistry().Resolve([]byte("b"), []byte("a"), "Set")
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), res)
+}
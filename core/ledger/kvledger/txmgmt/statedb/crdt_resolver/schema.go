@@ -0,0 +1,94 @@
+package crdt_resolver
+
+import (
+	"fmt"
+	"strings"
+
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+)
+
+// ValidateSchema checks that the key patterns of the schema are well formed.
+// A key pattern is either an exact key or a prefix followed by a single '*'
+func ValidateSchema(schema *lb.CRDTSchema) error {
+	patterns := map[string]struct{}{}
+	for _, keySchema := range schema.GetKeys() {
+		pattern := keySchema.KeyPattern
+		if pattern == "" {
+			return fmt.Errorf("CRDT schema contains an empty key pattern")
+		}
+		if i := strings.Index(pattern, "*"); i != -1 && i != len(pattern)-1 {
+			return fmt.Errorf("CRDT key pattern %s is invalid, '*' is only allowed at the end of the pattern", pattern)
+		}
+		if _, ok := patterns[pattern]; ok {
+			return fmt.Errorf("CRDT key pattern %s is defined more than once", pattern)
+		}
+		patterns[pattern] = struct{}{}
+		if len(keySchema.ResolutionTypes) == 0 {
+			return fmt.Errorf("CRDT key pattern %s has no resolution types", pattern)
+		}
+		for _, resType := range keySchema.ResolutionTypes {
+			if resType == "" {
+				return fmt.Errorf("CRDT key pattern %s contains an empty resolution type", pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckSchema returns an error if the schema does not allow the given resolution
+// type to be used on the key. The key is matched against the patterns in the order
+// they are defined and the first matching pattern decides. An empty schema allows
+// every resolution type on every key
+func CheckSchema(schema *lb.CRDTSchema, key string, resType string) error {
+	if len(schema.GetKeys()) == 0 {
+		return nil
+	}
+	for _, keySchema := range schema.Keys {
+		if !matchKeyPattern(keySchema.KeyPattern, key) {
+			continue
+		}
+		for _, allowed := range keySchema.ResolutionTypes {
+			if allowed == resType {
+				return nil
+			}
+		}
+		return mergeErrorf(SchemaViolation, "Resolve type %s is not allowed for key %s by pattern %s", resType, key, keySchema.KeyPattern)
+	}
+	return mergeErrorf(SchemaViolation, "Key %s does not match any pattern of the CRDT schema", key)
+}
+
+// valueTypes groups the builtin resolution types whose resolvers read the values written by one
+// another, e.g. the increments and the decrements of a balance. The integer values are decimal
+// values too, but a decimal value can't be read as an integer, hence the two groups are apart.
+// The other resolution types, e.g. Set that writes any bytes, are only compatible with themselves
+var valueTypes = map[string]string{
+	"IntAdd":       "integer",
+	"UintSub":      "integer",
+	"BigIntAdd":    "integer",
+	"BigIntSub":    "integer",
+	"DecimalAdd":   "decimal",
+	"DecimalSub":   "decimal",
+	JSONMergePatch: "json",
+	JSONPatch:      "json",
+}
+
+// CheckType returns an error if the key, whose metadata records the given CRDT type, can't be merged
+// with the given resolution type, i.e. if their resolvers do not share the encoding of the values.
+// A key with no CRDT type, i.e. an ordinary key or a key created before the CRDT types were recorded,
+// takes the resolution type of the first payload merged into it
+func CheckType(key string, keyType string, resType string) error {
+	if keyType == "" || keyType == resType {
+		return nil
+	}
+	if valueType, ok := valueTypes[keyType]; ok && valueType == valueTypes[resType] {
+		return nil
+	}
+	return mergeErrorf(TypeMismatch, "CRDT key %s holds a %s value, can't merge a %s payload", key, keyType, resType)
+}
+
+func matchKeyPattern(pattern string, key string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(key, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == key
+}
@@ -0,0 +1,148 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchema(t *testing.T) {
+	require.NoError(t, ValidateSchema(nil))
+	require.NoError(t, ValidateSchema(&lb.CRDTSchema{
+		Keys: []*lb.CRDTKeySchema{
+			{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+			{KeyPattern: "balance_*", ResolutionTypes: []string{"IntAdd", "UintSub"}},
+			{KeyPattern: "*", ResolutionTypes: []string{"Set"}},
+		},
+	}))
+
+	tests := []struct {
+		name      string
+		keySchema *lb.CRDTKeySchema
+		err       string
+	}{
+		{
+			name:      "empty pattern",
+			keySchema: &lb.CRDTKeySchema{ResolutionTypes: []string{"Set"}},
+			err:       "CRDT schema contains an empty key pattern",
+		},
+		{
+			name:      "wildcard in the middle",
+			keySchema: &lb.CRDTKeySchema{KeyPattern: "a*b", ResolutionTypes: []string{"Set"}},
+			err:       "CRDT key pattern a*b is invalid, '*' is only allowed at the end of the pattern",
+		},
+		{
+			name:      "no resolution types",
+			keySchema: &lb.CRDTKeySchema{KeyPattern: "a"},
+			err:       "CRDT key pattern a has no resolution types",
+		},
+		{
+			name:      "empty resolution type",
+			keySchema: &lb.CRDTKeySchema{KeyPattern: "a", ResolutionTypes: []string{"Set", ""}},
+			err:       "CRDT key pattern a contains an empty resolution type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchema(&lb.CRDTSchema{Keys: []*lb.CRDTKeySchema{tt.keySchema}})
+			require.EqualError(t, err, tt.err)
+		})
+	}
+
+	err := ValidateSchema(&lb.CRDTSchema{
+		Keys: []*lb.CRDTKeySchema{
+			{KeyPattern: "a", ResolutionTypes: []string{"Set"}},
+			{KeyPattern: "a", ResolutionTypes: []string{"IntAdd"}},
+		},
+	})
+	require.EqualError(t, err, "CRDT key pattern a is defined more than once")
+}
+
+func TestCheckSchema(t *testing.T) {
+	require.NoError(t, CheckSchema(nil, "any", "Set"))
+	require.NoError(t, CheckSchema(&lb.CRDTSchema{}, "any", "Set"))
+
+	schema := &lb.CRDTSchema{
+		Keys: []*lb.CRDTKeySchema{
+			{KeyPattern: "counter", ResolutionTypes: []string{"IntAdd"}},
+			{KeyPattern: "balance_*", ResolutionTypes: []string{"IntAdd", "UintSub"}},
+			{KeyPattern: "balance_frozen", ResolutionTypes: []string{"Set"}},
+		},
+	}
+
+	require.NoError(t, CheckSchema(schema, "counter", "IntAdd"))
+	require.NoError(t, CheckSchema(schema, "balance_", "UintSub"))
+	require.NoError(t, CheckSchema(schema, "balance_alice", "IntAdd"))
+
+	// the first matching pattern decides
+	require.EqualError(t, CheckSchema(schema, "balance_frozen", "Set"),
+		"Resolve type Set is not allowed for key balance_frozen by pattern balance_*")
+
+	require.EqualError(t, CheckSchema(schema, "counter", "StringConcat"),
+		"Resolve type StringConcat is not allowed for key counter by pattern counter")
+	require.EqualError(t, CheckSchema(schema, "counter2", "IntAdd"),
+		"Key counter2 does not match any pattern of the CRDT schema")
+}
+
+func TestCheckType(t *testing.T) {
+	require.NoError(t, CheckType("counter", "", "IntAdd"))
+	require.NoError(t, CheckType("counter", "IntAdd", "IntAdd"))
+	require.Error(t, CheckType("set", GSet, ORSet))
+	err := CheckType("counter", "IntAdd", "ORSet")
+	require.EqualError(t, err, "CRDT key counter holds a IntAdd value, can't merge a ORSet payload")
+	require.Equal(t, TypeMismatch, MergeFailureOf(err))
+}
+
+func TestCheckTypeMatrix(t *testing.T) {
+	// a value of each resolution type and a diff that can be merged into it
+	samples := map[string]struct{ value, diff string }{
+		"Set":          {"gold", "silver"},
+		"IntAdd":       {"5", "-1"},
+		"UintSub":      {"5", "1"},
+		"BigIntAdd":    {"5", "-1"},
+		"BigIntSub":    {"5", "1"},
+		"DecimalAdd":   {"1.5", "-0.25"},
+		"DecimalSub":   {"1.5", "0.25"},
+		"StringConcat": {"ab", "c"},
+		"ArrayAppend":  {`["a"]`, `["b"]`},
+		JSONMergePatch: {`{"a":1}`, `{"b":2}`},
+		JSONPatch:      {`{"a":1}`, `[{"op":"add","path":"/b","value":2}]`},
+	}
+	compatible := [][]string{
+		{"IntAdd", "UintSub", "BigIntAdd", "BigIntSub"},
+		{"DecimalAdd", "DecimalSub"},
+		{JSONMergePatch, JSONPatch},
+	}
+	expected := map[[2]string]bool{}
+	for resType := range samples {
+		expected[[2]string{resType, resType}] = true
+	}
+	for _, group := range compatible {
+		for _, keyType := range group {
+			for _, resType := range group {
+				expected[[2]string{keyType, resType}] = true
+			}
+		}
+	}
+
+	r := NewRegistry()
+	for keyType, keySample := range samples {
+		for resType, resSample := range samples {
+			err := CheckType("key", keyType, resType)
+			if !expected[[2]string{keyType, resType}] {
+				require.Error(t, err, "a %s key must not accept a %s payload", keyType, resType)
+				continue
+			}
+			require.NoError(t, err, "a %s key must accept a %s payload", keyType, resType)
+			// the resolver reads the values of the compatible types
+			_, err = r.Resolve([]byte(keySample.value), []byte(resSample.diff), resType)
+			require.NoError(t, err, "a %s payload must merge into a %s value", resType, keyType)
+		}
+	}
+	// the resolvers of the types that are kept apart fail to read the values
+	for _, pair := range [][2]string{{"Set", "IntAdd"}, {"DecimalAdd", "IntAdd"}, {"StringConcat", JSONMergePatch}} {
+		_, err := r.Resolve([]byte(samples[pair[0]].value), []byte(samples[pair[1]].diff), pair[1])
+		require.Error(t, err, "a %s payload must not merge into a %s value", pair[1], pair[0])
+	}
+}
@@ -0,0 +1,251 @@
+package crdt_resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	// GSet is the resolution type of the grow-only sets
+	GSet = "GSet"
+	// TwoPSet is the resolution type of the two-phase sets, whose
+	// elements can't be added again once they have been removed
+	TwoPSet = "TwoPSet"
+	// ORSet is the resolution type of the observed-remove sets, where
+	// a remove only affects the additions observed by the transaction
+	ORSet = "ORSet"
+)
+
+// setState is the value of a set key. Elements always holds the current members of
+// the set, sorted, regardless of the type of set, so that the membership of an element
+// can be tested without knowing how the set was built. Removed holds the tombstones of
+// a 2P-Set and Tags holds, for every member of an OR-Set, the IDs of the transactions
+// that added it and whose additions have not been removed yet
+type setState struct {
+	Elements []string            `json:"elements"`
+	Removed  []string            `json:"removed,omitempty"`
+	Tags     map[string][]string `json:"tags,omitempty"`
+}
+
+// setOps is the diff of the G-Set and 2P-Set resolvers, and the one sent by chaincode
+// for an OR-Set before it is converted by newORSetDelta
+type setOps struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// orSetDelta is the diff of the OR-Set resolver. It maps the elements
+// to the tags that are added and to the observed tags that are removed
+type orSetDelta struct {
+	Add    map[string][]string `json:"add,omitempty"`
+	Remove map[string][]string `json:"remove,omitempty"`
+}
+
+// IsSet returns true if the resolution type is one of the set CRDTs
+func IsSet(resType string) bool {
+	return resType == GSet || resType == TwoPSet || resType == ORSet
+}
+
+// SetContains returns true if the element is a member of the set stored in value
+func SetContains(value []byte, element string) (bool, error) {
+	if len(value) == 0 {
+		return false, nil
+	}
+	state, err := parseSetState(value)
+	if err != nil {
+		return false, err
+	}
+	i := sort.SearchStrings(state.Elements, element)
+	return i < len(state.Elements) && state.Elements[i] == element, nil
+}
+
+// newORSetDelta converts the operations sent by chaincode on an OR-Set into the diff
+// merged by the OR-Set resolver. The elements added are tagged with the ID of the
+// transaction, while the elements removed are resolved into the tags observed in the
+// committed value of the set. Additions that are committed concurrently are not
+// observed and hence survive the remove
+func newORSetDelta(txID string, observed []byte, ops []byte) ([]byte, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("OR-Set delta requires a transaction ID")
+	}
+	elementOps, err := parseSetOps(ops)
+	if err != nil {
+		return nil, err
+	}
+	observedState := &setState{}
+	if len(observed) != 0 {
+		if observedState, err = parseSetState(observed); err != nil {
+			return nil, err
+		}
+	}
+
+	delta := &orSetDelta{}
+	for _, element := range elementOps.Remove {
+		if tags := observedState.Tags[element]; len(tags) != 0 {
+			if delta.Remove == nil {
+				delta.Remove = map[string][]string{}
+			}
+			delta.Remove[element] = tags
+		}
+	}
+	for _, element := range elementOps.Add {
+		if delta.Add == nil {
+			delta.Add = map[string][]string{}
+		}
+		delta.Add[element] = []string{txID}
+	}
+	return json.Marshal(delta)
+}
+
+func parseSetState(value []byte) (*setState, error) {
+	state := &setState{}
+	if err := json.Unmarshal(value, state); err != nil {
+		return nil, fmt.Errorf("Invalid set value: %s", err)
+	}
+	return state, nil
+}
+
+func parseSetOps(diffValue []byte) (*setOps, error) {
+	ops := &setOps{}
+	if err := json.Unmarshal(diffValue, ops); err != nil {
+		return nil, fmt.Errorf("Invalid set diff: %s", err)
+	}
+	return ops, nil
+}
+
+type stringSet map[string]struct{}
+
+func newStringSet(elements ...[]string) stringSet {
+	s := stringSet{}
+	for _, e := range elements {
+		s.add(e...)
+	}
+	return s
+}
+
+func (s stringSet) add(elements ...string) {
+	for _, e := range elements {
+		s[e] = struct{}{}
+	}
+}
+
+func (s stringSet) contains(element string) bool {
+	_, ok := s[element]
+	return ok
+}
+
+// sorted returns the elements of the set in ascending order, or an
+// empty slice so that an empty set is encoded as [] rather than null
+func (s stringSet) sorted() []string {
+	res := make([]string, 0, len(s))
+	for e := range s {
+		res = append(res, e)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func gSetResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	cur := &setState{}
+	if len(curValue) != 0 {
+		var err error
+		if cur, err = parseSetState(curValue); err != nil {
+			return []byte(""), typeMismatch(err)
+		}
+	}
+	ops, err := parseSetOps(diffValue)
+	if err != nil {
+		return []byte(""), malformedDiff(err)
+	}
+	if len(ops.Remove) != 0 {
+		return []byte(""), mergeErrorf(MalformedDiff, "Can't remove elements from a G-Set")
+	}
+	if err := m.charge(len(cur.Elements) + len(ops.Add)); err != nil {
+		return []byte(""), err
+	}
+
+	return json.Marshal(&setState{Elements: newStringSet(cur.Elements, ops.Add).sorted()})
+}
+
+func twoPSetResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	cur := &setState{}
+	if len(curValue) != 0 {
+		var err error
+		if cur, err = parseSetState(curValue); err != nil {
+			return []byte(""), typeMismatch(err)
+		}
+	}
+	ops, err := parseSetOps(diffValue)
+	if err != nil {
+		return []byte(""), malformedDiff(err)
+	}
+	if err := m.charge(len(cur.Elements) + len(cur.Removed) + len(ops.Add) + len(ops.Remove)); err != nil {
+		return []byte(""), err
+	}
+
+	removed := newStringSet(cur.Removed, ops.Remove)
+	elements := stringSet{}
+	for _, e := range append(cur.Elements, ops.Add...) {
+		if !removed.contains(e) {
+			elements.add(e)
+		}
+	}
+
+	state := &setState{Elements: elements.sorted()}
+	if len(removed) != 0 {
+		state.Removed = removed.sorted()
+	}
+	return json.Marshal(state)
+}
+
+func orSetResolve(m *meter, curValue []byte, diffValue []byte) ([]byte, error) {
+	cur := &setState{}
+	if len(curValue) != 0 {
+		var err error
+		if cur, err = parseSetState(curValue); err != nil {
+			return []byte(""), typeMismatch(err)
+		}
+	}
+	delta := &orSetDelta{}
+	if err := json.Unmarshal(diffValue, delta); err != nil {
+		return []byte(""), mergeErrorf(MalformedDiff, "Invalid OR-Set diff: %s", err)
+	}
+	steps := 0
+	for _, elementTags := range []map[string][]string{cur.Tags, delta.Add, delta.Remove} {
+		for _, tags := range elementTags {
+			steps += 1 + len(tags)
+		}
+	}
+	if err := m.charge(steps); err != nil {
+		return []byte(""), err
+	}
+
+	tags := map[string]stringSet{}
+	for element, elementTags := range cur.Tags {
+		tags[element] = newStringSet(elementTags)
+	}
+	for element, elementTags := range delta.Add {
+		if _, ok := tags[element]; !ok {
+			tags[element] = stringSet{}
+		}
+		tags[element].add(elementTags...)
+	}
+	for element, elementTags := range delta.Remove {
+		for _, tag := range elementTags {
+			delete(tags[element], tag)
+		}
+	}
+
+	state := &setState{Tags: map[string][]string{}}
+	elements := stringSet{}
+	for element, elementTags := range tags {
+		if len(elementTags) == 0 {
+			continue
+		}
+		elements.add(element)
+		state.Tags[element] = elementTags.sorted()
+	}
+	state.Elements = elements.sorted()
+	return json.Marshal(state)
+}
@@ -0,0 +1,97 @@
+package crdt_resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGSetResolve(t *testing.T) {
+	res, err := gSetResolve(nil, nil, []byte(`{"add":["b","a","b"]}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["a","b"]}`, string(res))
+
+	res, err = gSetResolve(nil, res, []byte(`{"add":["c","a"]}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["a","b","c"]}`, string(res))
+
+	res, err = gSetResolve(nil, res, []byte(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["a","b","c"]}`, string(res))
+
+	_, err = gSetResolve(nil, res, []byte(`{"remove":["a"]}`))
+	require.EqualError(t, err, "Can't remove elements from a G-Set")
+
+	_, err = gSetResolve(nil, res, []byte(`["a"]`))
+	require.EqualError(t, err, "Invalid set diff: json: cannot unmarshal array into Go value of type crdt_resolver.setOps")
+}
+
+func TestTwoPSetResolve(t *testing.T) {
+	res, err := twoPSetResolve(nil, nil, []byte(`{"add":["b","a"]}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["a","b"]}`, string(res))
+
+	res, err = twoPSetResolve(nil, res, []byte(`{"remove":["a","z"]}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["b"],"removed":["a","z"]}`, string(res))
+
+	// removed elements can't be added again
+	res, err = twoPSetResolve(nil, res, []byte(`{"add":["a","c"]}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["b","c"],"removed":["a","z"]}`, string(res))
+
+	// the remove wins over an add of the same diff
+	res, err = twoPSetResolve(nil, res, []byte(`{"add":["d"],"remove":["b","d"]}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"elements":["c"],"removed":["a","b","d","z"]}`, string(res))
+}
+
+func TestORSetResolve(t *testing.T) {
+	apply := func(cur []byte, txID string, observed []byte, ops string) []byte {
+		delta, err := newORSetDelta(txID, observed, []byte(ops))
+		require.NoError(t, err)
+		res, err := orSetResolve(nil, cur, delta)
+		require.NoError(t, err)
+		return res
+	}
+
+	value := apply(nil, "tx1", nil, `{"add":["a","b"]}`)
+	require.Equal(t, `{"elements":["a","b"],"tags":{"a":["tx1"],"b":["tx1"]}}`, string(value))
+
+	// tx2 and tx3 are simulated against the same committed value: the remove of tx3
+	// does not observe the concurrent add of tx2, so the add wins
+	committed := value
+	value = apply(value, "tx2", committed, `{"add":["a"]}`)
+	value = apply(value, "tx3", committed, `{"remove":["a","b"]}`)
+	require.Equal(t, `{"elements":["a"],"tags":{"a":["tx2"]}}`, string(value))
+
+	// a remove that observed every add removes the element
+	value = apply(value, "tx4", value, `{"remove":["a"]}`)
+	require.Equal(t, `{"elements":[]}`, string(value))
+
+	// removed elements can be added again
+	value = apply(value, "tx5", value, `{"add":["b"]}`)
+	require.Equal(t, `{"elements":["b"],"tags":{"b":["tx5"]}}`, string(value))
+
+	_, err := newORSetDelta("", nil, []byte(`{"add":["a"]}`))
+	require.EqualError(t, err, "OR-Set delta requires a transaction ID")
+
+	_, err = orSetResolve(nil, nil, []byte(`{"add":["a"]}`))
+	require.EqualError(t, err, "Invalid OR-Set diff: json: cannot unmarshal array into Go struct field orSetDelta.add of type map[string][]string")
+}
+
+func TestSetContains(t *testing.T) {
+	contains, err := SetContains(nil, "a")
+	require.NoError(t, err)
+	require.False(t, contains)
+
+	value := []byte(`{"elements":["a","c"],"removed":["b"]}`)
+	for element, expected := range map[string]bool{"a": true, "b": false, "c": true, "d": false} {
+		contains, err = SetContains(value, element)
+		require.NoError(t, err)
+		require.Equal(t, expected, contains, element)
+	}
+
+	_, err = SetContains([]byte("5"), "a")
+	require.EqualError(t, err, "Invalid set value: json: cannot unmarshal number into Go value of type crdt_resolver.setState")
+}
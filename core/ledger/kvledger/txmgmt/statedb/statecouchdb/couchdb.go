@@ -1381,6 +1381,81 @@ func (dbclient *couchDatabase) batchRetrieveDocumentMetadata(keys []string) ([]*
 	return docMetadataArray, nil
 }
 
+// batchRetrieveDocuments retrieves the documents, along with their attachments, of the given keys.
+// The keys that do not exist or that were deleted are not part of the results
+func (dbclient *couchDatabase) batchRetrieveDocuments(keys []string) ([]*queryResult, error) {
+	couchdbLogger.Debugf("[%s] Entering BatchRetrieveDocuments()  keys=%s", dbclient.dbName, keys)
+
+	batchRetrieveURL, err := url.Parse(dbclient.couchInstance.url())
+	if err != nil {
+		couchdbLogger.Errorf("URL parse error: %s", err)
+		return nil, errors.Wrapf(err, "error parsing CouchDB URL: %s", dbclient.couchInstance.url())
+	}
+
+	queryParms := batchRetrieveURL.Query()
+	queryParms.Add("include_docs", "true")
+	queryParms.Add("attachments", "true") // get the attachments as well
+
+	keymap := make(map[string]interface{})
+
+	keymap["keys"] = keys
+
+	jsonKeys, err := json.Marshal(keymap)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling json data")
+	}
+
+	// get the number of retries
+	maxRetries := dbclient.couchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.handleRequest(http.MethodPost, "BatchRetrieveDocuments", batchRetrieveURL, jsonKeys, "", "", maxRetries, true, &queryParms, "_all_docs")
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	if couchdbLogger.IsEnabledFor(zapcore.DebugLevel) {
+		dump, _ := httputil.DumpResponse(resp, false)
+		// compact debug log by replacing carriage return / line feed with dashes to separate http headers
+		couchdbLogger.Debugf("[%s] HTTP Response: %s", dbclient.dbName, bytes.Replace(dump, []byte{0x0d, 0x0a}, []byte{0x20, 0x7c, 0x20}, -1))
+	}
+
+	// handle as JSON document
+	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body")
+	}
+
+	jsonResponse := &rangeQueryResponse{}
+	if err := json.Unmarshal(jsonResponseRaw, &jsonResponse); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling json data")
+	}
+
+	var results []*queryResult
+	for _, row := range jsonResponse.Rows {
+		// the missing and the deleted documents have no doc
+		if len(row.Doc) == 0 || bytes.Equal(row.Doc, []byte("null")) {
+			continue
+		}
+
+		docMetadata := &docMetadata{}
+		if err := json.Unmarshal(row.Doc, &docMetadata); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling json data")
+		}
+
+		var attachments []*attachmentInfo
+		for attachmentName, attachment := range docMetadata.AttachmentsInfo {
+			attachment.Name = attachmentName
+			attachments = append(attachments, attachment)
+		}
+		results = append(results, &queryResult{docMetadata.ID, row.Doc, attachments})
+	}
+
+	couchdbLogger.Debugf("[%s] Exiting BatchRetrieveDocuments()", dbclient.dbName)
+
+	return results, nil
+}
+
 func (dbclient *couchDatabase) insertDocuments(docs []*couchDoc) error {
 	responses, err := dbclient.batchUpdateDocuments(docs)
 	if err != nil {
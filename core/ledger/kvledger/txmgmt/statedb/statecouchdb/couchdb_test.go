@@ -176,6 +176,10 @@ func TestBadCouchDBInstance(t *testing.T) {
 	_, err = badDB.batchRetrieveDocumentMetadata(nil)
 	require.Error(t, err, "Error should have been thrown with batchRetrieveDocumentMetadata and invalid connection")
 
+	// Test batchRetrieveDocuments with bad connection
+	_, err = badDB.batchRetrieveDocuments(nil)
+	require.Error(t, err, "Error should have been thrown with batchRetrieveDocuments and invalid connection")
+
 	// Test batchUpdateDocuments with bad connection
 	_, err = badDB.batchUpdateDocuments(nil)
 	require.Error(t, err, "Error should have been thrown with batchUpdateDocuments and invalid connection")
@@ -812,6 +816,56 @@ func TestDBDeleteDocument(t *testing.T) {
 	require.Nil(t, readValue)
 }
 
+func TestDBBatchRetrieveDocuments(t *testing.T) {
+	config := testConfig()
+	couchDBEnv.startCouchDB(t)
+	config.Address = couchDBEnv.couchAddress
+	defer couchDBEnv.cleanup(config)
+	database := "testdbbatchretrievedocuments"
+
+	byteText := []byte(`This is a test document.  This is only a test`)
+	attachment := &attachmentInfo{
+		AttachmentBytes: byteText,
+		ContentType:     "application/octet-stream",
+		Length:          uint64(len(byteText)),
+		Name:            "valueBytes",
+	}
+
+	// create a new instance and database object
+	couchInstance, err := createCouchInstance(config, &disabled.Provider{})
+	require.NoError(t, err, "Error when trying to create couch instance")
+	db := couchDatabase{couchInstance: couchInstance, dbName: database}
+
+	// create a new database
+	errdb := db.createDatabaseIfNotExist()
+	require.NoError(t, errdb, "Error when trying to create database")
+
+	// save a JSON document, a document with an attachment and a document that is deleted
+	_, err = db.saveDoc("1", "", &couchDoc{jsonValue: assetJSON})
+	require.NoError(t, err, "Error when trying to save a document")
+	_, err = db.saveDoc("2", "", &couchDoc{jsonValue: []byte(`{"_id":"2"}`), attachments: []*attachmentInfo{attachment}})
+	require.NoError(t, err, "Error when trying to save a document")
+	_, err = db.saveDoc("3", "", &couchDoc{jsonValue: assetJSON})
+	require.NoError(t, err, "Error when trying to save a document")
+	require.NoError(t, db.deleteDoc("3", ""), "Error when trying to delete a document")
+
+	// the deleted and the missing documents are not part of the results
+	results, err := db.batchRetrieveDocuments([]string{"1", "2", "3", "4"})
+	require.NoError(t, err, "Error when attempting to retrieve documents")
+	require.Len(t, results, 2)
+
+	require.Equal(t, "1", results[0].id)
+	assetResp := &Asset{}
+	require.NoError(t, json.Unmarshal(results[0].value, assetResp))
+	require.Equal(t, "jerry", assetResp.Owner)
+	require.Empty(t, results[0].attachments)
+
+	require.Equal(t, "2", results[1].id)
+	require.Len(t, results[1].attachments, 1)
+	require.Equal(t, "valueBytes", results[1].attachments[0].Name)
+	require.Equal(t, byteText, results[1].attachments[0].AttachmentBytes)
+}
+
 func TestDBDeleteNonExistingDocument(t *testing.T) {
 	config := testConfig()
 	couchDBEnv.startCouchDB(t)
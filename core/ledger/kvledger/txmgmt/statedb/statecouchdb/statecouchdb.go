@@ -447,6 +447,77 @@ func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) erro
 	return nil
 }
 
+// LoadCommittedValues populates the committed values of the given keys, along with their
+// versions and revisions, into the cache populated by LoadCommittedVersions. The values are
+// read from the state cache when available, otherwise a bulk retrieve from couchdb is used.
+// committedValues cache is used for merging the CRDT payloads of a block without reading
+// the keys one by one. Unlike LoadCommittedVersions, the cache is extended rather than replaced
+func (vdb *VersionedDB) LoadCommittedValues(keys []*statedb.CompositeKey) error {
+	missingKeys := map[string][]string{}
+	committedValues := map[statedb.CompositeKey]*keyValue{}
+	for _, compositeKey := range keys {
+		ns, key := compositeKey.Namespace, compositeKey.Key
+		committedValues[*compositeKey] = nil
+		logger.Debugf("Load into value cache: %s~%s", ns, key)
+
+		if !vdb.cache.enabled(ns) {
+			missingKeys[ns] = append(missingKeys[ns], key)
+			continue
+		}
+		cv, err := vdb.cache.getState(vdb.chainName, ns, key)
+		if err != nil {
+			return err
+		}
+		if cv == nil {
+			missingKeys[ns] = append(missingKeys[ns], key)
+			continue
+		}
+		vv, err := constructVersionedValue(cv)
+		if err != nil {
+			return err
+		}
+		committedValues[*compositeKey] = &keyValue{key, string(cv.AdditionalInfo), vv}
+	}
+
+	nsValuesMap, err := vdb.retrieveValues(missingKeys)
+	logger.Debugf("missingKeys=%s", missingKeys)
+	if err != nil {
+		return err
+	}
+	for ns, nsValues := range nsValuesMap {
+		for _, kv := range nsValues {
+			committedValues[statedb.CompositeKey{Namespace: ns, Key: kv.key}] = kv
+			// as with GetState, the values read from the database are stored in the state cache
+			if vdb.cache.enabled(ns) {
+				cacheValue := constructCacheValue(kv.VersionedValue, kv.revision)
+				if err := vdb.cache.putState(vdb.chainName, ns, kv.key, cacheValue); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	vdb.verCacheLock.Lock()
+	defer vdb.verCacheLock.Unlock()
+	for compositeKey, kv := range committedValues {
+		if kv == nil {
+			vdb.committedDataCache.setValue(compositeKey.Namespace, compositeKey.Key, nil, "")
+			continue
+		}
+		vdb.committedDataCache.setValue(compositeKey.Namespace, compositeKey.Key, kv.VersionedValue, kv.revision)
+	}
+	return nil
+}
+
+// GetCachedValue returns the committed value from cache, which is nil if the key does not exist.
+// `LoadCommittedValues` function populates the cache
+func (vdb *VersionedDB) GetCachedValue(namespace string, key string) (*statedb.VersionedValue, bool) {
+	logger.Debugf("Retrieving cached value: %s~%s", key, namespace)
+	vdb.verCacheLock.RLock()
+	defer vdb.verCacheLock.RUnlock()
+	return vdb.committedDataCache.getValue(namespace, key)
+}
+
 // GetVersion implements method in VersionedDB interface
 func (vdb *VersionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
 	version, keyFound := vdb.GetCachedVersion(namespace, key)
@@ -782,7 +853,7 @@ func (vdb *VersionedDB) postCommitProcessing(committers []*committer, namespaces
 	}
 }
 
-// ClearCachedVersions clears committedVersions and revisionNumbers
+// ClearCachedVersions clears committedVersions, revisionNumbers and committedValues
 func (vdb *VersionedDB) ClearCachedVersions() {
 	logger.Debugf("Clear Cache")
 	vdb.verCacheLock.Lock()
@@ -1277,6 +1277,126 @@ func TestLoadCommittedVersion(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestLoadCommittedValue(t *testing.T) {
+	vdbEnv.init(t, []string{"lscc", "_lifecycle"})
+	defer vdbEnv.cleanup()
+
+	chainID := "testloadcommittedvalue"
+	db, err := vdbEnv.DBProvider.GetDBHandle(chainID, nil)
+	require.NoError(t, err)
+
+	// scenario: state cache has (ns1, key1). The db contains (ns1, key2) with
+	// a JSON value and (ns2, key1) with a binary value, while (ns2, key2) does
+	// not exist. The LoadCommittedValues will fetch the first key from the state
+	// cache and the remaining ones from the db
+	cacheValue := &CacheValue{
+		Value:          []byte("value1"),
+		Metadata:       []byte("meta1"),
+		Version:        version.NewHeight(1, 1).ToBytes(),
+		AdditionalInfo: []byte("rev1"),
+	}
+	require.NoError(t, vdbEnv.cache.putState(chainID, "ns1", "key1", cacheValue))
+
+	batch := statedb.NewUpdateBatch()
+	vv2 := &statedb.VersionedValue{Value: []byte(`{"total":"2"}`), Metadata: []byte("meta2"), Version: version.NewHeight(1, 2)}
+	batch.PutValAndMetadata("ns1", "key2", vv2.Value, vv2.Metadata, vv2.Version)
+	vv3 := &statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(1, 3)}
+	batch.Put("ns2", "key1", vv3.Value, vv3.Version)
+	savePoint := version.NewHeight(2, 2)
+	require.NoError(t, db.ApplyUpdates(batch, savePoint))
+
+	// the values were cached by the commit, so they are removed
+	// from the state cache to be fetched from the db
+	vdbEnv.cache.usrCache.Reset()
+	require.NoError(t, vdbEnv.cache.putState(chainID, "ns1", "key1", cacheValue))
+
+	vdb := db.(*VersionedDB)
+	vv, ok := vdb.GetCachedValue("ns1", "key1")
+	require.Nil(t, vv)
+	require.False(t, ok)
+
+	keys := []*statedb.CompositeKey{
+		{Namespace: "ns1", Key: "key1"},
+		{Namespace: "ns1", Key: "key2"},
+		{Namespace: "ns2", Key: "key1"},
+		{Namespace: "ns2", Key: "key2"},
+	}
+	require.NoError(t, vdb.LoadCommittedValues(keys))
+
+	vv, ok = vdb.GetCachedValue("ns1", "key1")
+	require.True(t, ok)
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("meta1"), Version: version.NewHeight(1, 1)}, vv)
+	vv, ok = vdb.GetCachedValue("ns1", "key2")
+	require.True(t, ok)
+	require.Equal(t, vv2, vv)
+	vv, ok = vdb.GetCachedValue("ns2", "key1")
+	require.True(t, ok)
+	require.Equal(t, vv3.Value, vv.Value)
+	require.Equal(t, vv3.Version, vv.Version)
+	vv, ok = vdb.GetCachedValue("ns2", "key2")
+	require.True(t, ok)
+	require.Nil(t, vv)
+
+	// the versions and the revisions are loaded along with the values
+	ver, ok := vdb.GetCachedVersion("ns1", "key2")
+	require.True(t, ok)
+	require.Equal(t, version.NewHeight(1, 2), ver)
+	require.Equal(t, "rev1", vdb.committedDataCache.revs["ns1"]["key1"])
+	require.NotEmpty(t, vdb.committedDataCache.revs["ns2"]["key1"])
+
+	// the values fetched from the db are stored in the state cache
+	cv, err := vdbEnv.cache.getState(chainID, "ns1", "key2")
+	require.NoError(t, err)
+	require.NotNil(t, cv)
+
+	vdb.ClearCachedVersions()
+	vv, ok = vdb.GetCachedValue("ns1", "key2")
+	require.Nil(t, vv)
+	require.False(t, ok)
+}
+
+func TestLoadCommittedValuesInBatches(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+
+	db, err := vdbEnv.DBProvider.GetDBHandle("testloadcommittedvaluesinbatches", nil)
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 5; i++ {
+		key := fmt.Sprintf("key%d", i)
+		batch.Put("ns1", key, []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 5)))
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns1", "key5", version.NewHeight(2, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+
+	// the keys are fetched from the db, two at a time
+	vdbEnv.cache.usrCache.Reset()
+	vdbEnv.config.MaxBatchUpdateSize = 2
+
+	vdb := db.(*VersionedDB)
+	var keys []*statedb.CompositeKey
+	for i := 1; i <= 6; i++ {
+		keys = append(keys, &statedb.CompositeKey{Namespace: "ns1", Key: fmt.Sprintf("key%d", i)})
+	}
+	require.NoError(t, vdb.LoadCommittedValues(keys))
+
+	for i := 1; i <= 4; i++ {
+		vv, ok := vdb.GetCachedValue("ns1", fmt.Sprintf("key%d", i))
+		require.True(t, ok)
+		require.Equal(t, []byte(fmt.Sprintf("value%d", i)), vv.Value)
+		require.Equal(t, version.NewHeight(1, uint64(i)), vv.Version)
+	}
+	// the deleted and the missing keys are cached as keys that do not exist
+	for _, key := range []string{"key5", "key6"} {
+		vv, ok := vdb.GetCachedValue("ns1", key)
+		require.True(t, ok)
+		require.Nil(t, vv)
+	}
+}
+
 func TestMissingRevisionRetrievalFromDB(t *testing.T) {
 	vdbEnv.init(t, nil)
 	defer vdbEnv.cleanup()
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"fmt"
+)
+
+// nsValueRetriever implements `batch` interface and wraps the function `retrieveNsValues`
+// for allowing parallel execution of this function for different namespaces
+type nsValueRetriever struct {
+	ns              string
+	db              *couchDatabase
+	keys            []string
+	executionResult []*keyValue
+}
+
+// subNsValueRetriever implements `batch` interface and wraps the function
+// `couchdb.batchRetrieveDocuments` for allowing parallel execution of
+// this function for different sets of keys within a namespace. Different sets
+// of keys are expected to be created based on the batch update size configured
+// for the database.
+type subNsValueRetriever nsValueRetriever
+
+// retrieveValues retrieves the committed values for a collection of `namespace-keys` combination.
+// The keys that do not exist are not part of the results
+func (vdb *VersionedDB) retrieveValues(nsKeysMap map[string][]string) (map[string][]*keyValue, error) {
+	// construct one batch per namespace
+	nsValueRetrievers := []batch{}
+	for ns, keys := range nsKeysMap {
+		db, err := vdb.getNamespaceDBHandle(ns)
+		if err != nil {
+			return nil, err
+		}
+		nsValueRetrievers = append(nsValueRetrievers, &nsValueRetriever{ns: ns, db: db, keys: keys})
+	}
+	if err := executeBatches(nsValueRetrievers); err != nil {
+		return nil, err
+	}
+	// accumulate results from each batch
+	executionResults := make(map[string][]*keyValue)
+	for _, r := range nsValueRetrievers {
+		nsValueRetriever := r.(*nsValueRetriever)
+		executionResults[nsValueRetriever.ns] = nsValueRetriever.executionResult
+	}
+	return executionResults, nil
+}
+
+// retrieveNsValues retrieves the committed values for a given namespace
+func retrieveNsValues(db *couchDatabase, keys []string) ([]*keyValue, error) {
+	// construct one batch per group of keys based on maxBatchSize
+	maxBatchSize := db.couchInstance.maxBatchUpdateSize()
+	batches := []batch{}
+	remainingKeys := keys
+	for {
+		numKeys := minimum(maxBatchSize, len(remainingKeys))
+		if numKeys == 0 {
+			break
+		}
+		batch := &subNsValueRetriever{db: db, keys: remainingKeys[:numKeys]}
+		batches = append(batches, batch)
+		remainingKeys = remainingKeys[numKeys:]
+	}
+	if err := executeBatches(batches); err != nil {
+		return nil, err
+	}
+	// accumulate results from each batch
+	var executionResults []*keyValue
+	for _, b := range batches {
+		executionResults = append(executionResults, b.(*subNsValueRetriever).executionResult...)
+	}
+	return executionResults, nil
+}
+
+func (r *nsValueRetriever) execute() error {
+	var err error
+	if r.executionResult, err = retrieveNsValues(r.db, r.keys); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *nsValueRetriever) String() string {
+	return fmt.Sprintf("nsValueRetriever:ns=%s, num keys=%d", r.ns, len(r.keys))
+}
+
+func (b *subNsValueRetriever) execute() error {
+	results, err := b.db.batchRetrieveDocuments(b.keys)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		kv, err := couchDocToKeyValue(&couchDoc{jsonValue: result.value, attachments: result.attachments})
+		if err != nil {
+			return err
+		}
+		b.executionResult = append(b.executionResult, kv)
+	}
+	return nil
+}
+
+func (b *subNsValueRetriever) String() string {
+	return fmt.Sprintf("subNsValueRetriever:ns=%s, num keys=%d", b.ns, len(b.keys))
+}
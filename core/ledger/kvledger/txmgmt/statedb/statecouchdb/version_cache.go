@@ -8,6 +8,7 @@ package statecouchdb
 
 import (
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 )
 
 type (
@@ -15,19 +16,23 @@ type (
 	revisions   map[string]nsRevisions
 	nsRevisions map[string]string
 	nsVersions  map[string]*version.Height
+	values      map[string]nsValues
+	nsValues    map[string]*statedb.VersionedValue
 )
 
 // versionsCache contains maps of versions and revisions.
 // Used as a local cache during bulk processing of a block.
 // versions - contains the committed versions and used for state validation of readsets
 // revisions - contains the committed revisions and used during commit phase for couchdb bulk updates
+// vals - contains the committed values of the keys merged by the CRDT payloads of the block
 type versionsCache struct {
 	vers versions
 	revs revisions
+	vals values
 }
 
 func newVersionCache() *versionsCache {
-	return &versionsCache{make(versions), make(revisions)}
+	return &versionsCache{make(versions), make(revisions), make(values)}
 }
 
 func (c *versionsCache) getVersion(ns, key string) (*version.Height, bool) {
@@ -54,3 +59,25 @@ func (c *versionsCache) setVerAndRev(ns, key string, ver *version.Height, rev st
 	c.vers[ns][key] = ver
 	c.revs[ns][key] = rev
 }
+
+func (c *versionsCache) getValue(ns, key string) (*statedb.VersionedValue, bool) {
+	val, ok := c.vals[ns][key]
+	if ok {
+		return val, true
+	}
+	return nil, false
+}
+
+// setValue sets the given committed value, which is nil if the key does not exist, into
+// cache for given ns/key along with its version and couch revision
+func (c *versionsCache) setValue(ns, key string, val *statedb.VersionedValue, rev string) {
+	var ver *version.Height
+	if val != nil {
+		ver = val.Version
+	}
+	c.setVerAndRev(ns, key, ver, rev)
+	if _, ok := c.vals[ns]; !ok {
+		c.vals[ns] = make(nsValues)
+	}
+	c.vals[ns][key] = val
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,3 +37,30 @@ func TestVersionCache(t *testing.T) {
 	require.False(t, found)
 	require.Nil(t, ver)
 }
+
+func TestVersionCacheValues(t *testing.T) {
+	verCache := newVersionCache()
+	val1 := &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}
+	verCache.setValue("ns1", "key1", val1, "rev1")
+	verCache.setValue("ns1", "key2", nil, "")
+
+	val, found := verCache.getValue("ns1", "key1")
+	require.True(t, found)
+	require.Equal(t, val1, val)
+	ver, found := verCache.getVersion("ns1", "key1")
+	require.True(t, found)
+	require.Equal(t, version.NewHeight(1, 1), ver)
+	require.Equal(t, "rev1", verCache.revs["ns1"]["key1"])
+
+	// a key that does not exist is cached as well
+	val, found = verCache.getValue("ns1", "key2")
+	require.True(t, found)
+	require.Nil(t, val)
+	ver, found = verCache.getVersion("ns1", "key2")
+	require.True(t, found)
+	require.Nil(t, ver)
+
+	val, found = verCache.getValue("ns2", "key1")
+	require.False(t, found)
+	require.Nil(t, val)
+}
@@ -7,12 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package statedb
 
 import (
-	"fmt"
 	"sort"
 
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	// "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/util"
 )
 
@@ -102,6 +103,8 @@ type NamespaceProvider interface {
 type BulkOptimizable interface {
 	LoadCommittedVersions(keys []*CompositeKey) error
 	GetCachedVersion(namespace, key string) (*version.Height, bool)
+	LoadCommittedValues(keys []*CompositeKey) error
+	GetCachedValue(namespace, key string) (*VersionedValue, bool)
 	ClearCachedVersions()
 }
 
@@ -161,7 +164,12 @@ type nsUpdates struct {
 	M map[string]*VersionedValue
 }
 
-const crdtPrefix string = "CRDTFIELD_"
+// CRDTPrefix is the prefix the earlier versions of the shim added to the CRDT keys. The CRDT keys
+// are now told apart by the CRDT type recorded in their metadata, see statemetadata.CRDTType. The
+// keys with the prefix remain CRDT keys under their full name and take a CRDT type the next time
+// a payload is merged into them. The transactions addressing such a key by its plain name are
+// redirected to it while the plain key holds no CRDT type
+const CRDTPrefix string = "CRDTFIELD_"
 
 func newNsUpdates() *nsUpdates {
 	return &nsUpdates{make(map[string]*VersionedValue)}
@@ -205,50 +213,137 @@ func (batch *UpdateBatch) PutValAndMetadata(ns string, key string, value []byte,
 	batch.Update(ns, key, &VersionedValue{value, metadata, version})
 }
 
+// CRDTMerge merges the data into the current value of a CRDT key, read from the batch or else via
+// getState, using the resolver registered for resType. A key with no CRDT type takes resType as its
+// type, otherwise a type that is not compatible, see crdt_resolver.CheckType, fails with a
+// crdt_resolver.TypeMismatch. A failed predicate is returned as a *crdt_resolver.PredicateError.
+// The other metadata of the key is carried forward. It returns the value the key had before the
+// merge, nil if the key does not exist
 func (batch *UpdateBatch) CRDTMerge(getState func(ns string, key string) (*VersionedValue, error),
-	ns string, key string, data []byte, resType string, metadata []byte, version *version.Height) (*VersionedValue, error) {
+	resolvers *crdt_resolver.Registry, ns string, key string, data []byte, resType string, predicates []*kvrwset.CRDTPredicate,
+	version *version.Height) (*VersionedValue, error) {
 
-	if len(key) < len(crdtPrefix) || key[0:len(crdtPrefix)] != crdtPrefix {
-		return nil, fmt.Errorf("Wrong prefix for crdt field. Should be '%s', but got '%s'", crdtPrefix, key[0:len(crdtPrefix)])
+	curVV, err := batch.crdtCurrentState(getState, ns, key)
+	if err != nil {
+		return nil, err
 	}
 
-	curVV := batch.getOrCreateNsUpdates(ns).M[key]
+	curValue := make([]byte, 0)
+	var metadata []byte
+
+	if curVV != nil {
+		curValue = curVV.Value
+		metadata = curVV.Metadata
+	}
 
-	if curVV == nil {
-		// Get value from database
-		curState, err := getState(ns, key)
+	keyType, err := statemetadata.GetCRDTType(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := crdt_resolver.CheckType(key, keyType, resType); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
+	if err := crdt_resolver.CheckPredicates(curValue, resType, predicates); err != nil {
+		return nil, err
+	}
+
+	// Merge data using resType
+	mergedValue, err := resolvers.ResolveAt(curValue, data, resType, version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType == "" {
+		if metadata, err = statemetadata.SetCRDTType(metadata, resType); err != nil {
 			return nil, err
 		}
+	}
+
+	batch.Update(ns, key, &VersionedValue{mergedValue, metadata, version})
+
+	return curVV, nil
+}
 
-		curVV = curState
+// CRDTReset replaces the value of a CRDT key with the data merged into an empty value using the
+// resolver registered for resType, which becomes the CRDT type of the key whatever its previous
+// type. Like CRDTMerge, it carries the other metadata of the key forward and returns the value
+// the key had before the reset, nil if the key does not exist
+func (batch *UpdateBatch) CRDTReset(getState func(ns string, key string) (*VersionedValue, error),
+	resolvers *crdt_resolver.Registry, ns string, key string, data []byte, resType string,
+	version *version.Height) (*VersionedValue, error) {
+
+	curVV, err := batch.crdtCurrentState(getState, ns, key)
+	if err != nil {
+		return nil, err
 	}
 
-	curValue := make([]byte, 0)
+	resetValue, err := resolvers.ResolveAt(make([]byte, 0), data, resType, version)
+	if err != nil {
+		return nil, err
+	}
 
+	var metadata []byte
 	if curVV != nil {
-		curValue = curVV.Value
+		metadata = curVV.Metadata
+	}
+	if metadata, err = statemetadata.SetCRDTType(metadata, resType); err != nil {
+		return nil, err
 	}
+	batch.Update(ns, key, &VersionedValue{resetValue, metadata, version})
 
-	// Merge data using resType
-	fmt.Println(resType)
-	mergedValue, err := crdt_resolver.Resolve(curValue, data, resType)
+	return curVV, nil
+}
 
+// CRDTDelete deletes a CRDT key along with its metadata, including its CRDT type. A CRDT payload
+// merged later on recreates the key from an empty value.
+// It returns the value the key had before the delete, nil if the key does not exist
+func (batch *UpdateBatch) CRDTDelete(getState func(ns string, key string) (*VersionedValue, error),
+	ns string, key string, version *version.Height) (*VersionedValue, error) {
+
+	curVV, err := batch.crdtCurrentState(getState, ns, key)
 	if err != nil {
 		return nil, err
 	}
 
-	batch.Update(ns, key, &VersionedValue{mergedValue, metadata, version})
+	batch.Delete(ns, key, version)
 
 	return curVV, nil
 }
 
+// crdtCurrentState returns the current value of a CRDT key, looked up in the batch first
+// and then via getState. A key deleted in the batch is reported as not existing
+func (batch *UpdateBatch) crdtCurrentState(getState func(ns string, key string) (*VersionedValue, error),
+	ns string, key string) (*VersionedValue, error) {
+
+	if curVV, ok := batch.getOrCreateNsUpdates(ns).M[key]; ok {
+		if curVV.Value == nil {
+			return nil, nil
+		}
+		return curVV, nil
+	}
+	return getState(ns, key)
+}
+
 // Delete deletes a Key and associated value
 func (batch *UpdateBatch) Delete(ns string, key string, version *version.Height) {
 	batch.Update(ns, key, &VersionedValue{nil, nil, version})
 }
 
+// Remove removes the entry of a key from the batch, if any, so that the
+// batch leaves the key as it is in the state db
+func (batch *UpdateBatch) Remove(ns string, key string) {
+	nsUpdates, ok := batch.Updates[ns]
+	if !ok {
+		return
+	}
+	delete(nsUpdates.M, key)
+	if len(nsUpdates.M) == 0 {
+		delete(batch.Updates, ns)
+	}
+}
+
 // Exists checks whether the given key exists in the batch
 func (batch *UpdateBatch) Exists(ns string, key string) bool {
 	nsUpdates, ok := batch.Updates[ns]
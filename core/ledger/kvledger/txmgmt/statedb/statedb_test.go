@@ -145,6 +145,29 @@ func checkItrResults(t *testing.T, itr QueryResultsIterator, expectedResults []*
 	itr.Close()
 }
 
+func TestRemove(t *testing.T) {
+	batch := NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Delete("ns1", "key2", version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(1, 3))
+
+	// the deleted key is no longer deleted by the batch
+	batch.Remove("ns1", "key2")
+	require.False(t, batch.Exists("ns1", "key2"))
+	require.True(t, batch.Exists("ns1", "key1"))
+
+	// the namespace is removed along with its last key
+	batch.Remove("ns2", "key1")
+	require.Equal(t, []string{"ns1"}, batch.GetUpdatedNamespaces())
+
+	// removing the keys that are not in the batch is a no-op
+	batch.Remove("ns1", "key3")
+	batch.Remove("ns3", "key1")
+	expectedBatch := NewUpdateBatch()
+	expectedBatch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	require.Equal(t, expectedBatch, batch)
+}
+
 func TestMergeUpdateBatch(t *testing.T) {
 	batch1 := NewUpdateBatch()
 	batch1.Put("ns1", "key1", []byte("batch1_value1"), version.NewHeight(1, 1))
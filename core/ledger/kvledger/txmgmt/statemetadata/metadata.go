@@ -7,10 +7,22 @@ SPDX-License-Identifier: Apache-2.0
 package statemetadata
 
 import (
+	"sort"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 )
 
+// CRDTType is the name of the metadata entry holding the resolution type of a CRDT key.
+// The entry is recorded by the CRDT payloads merged into the key and can't be written by
+// the chaincodes
+const CRDTType = "CRDT_TYPE"
+
+// CRDTHash is the name of the metadata entry of a private CRDT value holding the hash chained by the
+// CRDT payloads merged into the value, i.e. the hash of its hashed key at the version of the value.
+// The entry is only recorded in the private data, the hashed key holds the hash as its value
+const CRDTHash = "CRDT_HASH"
+
 // Serialize serializes metadata entries for storing in statedb
 func Serialize(metadataEntries []*kvrwset.KVMetadataEntry) ([]byte, error) {
 	metadata := &kvrwset.KVMetadataWrite{Entries: metadataEntries}
@@ -32,3 +44,55 @@ func Deserialize(metadataBytes []byte) (map[string][]byte, error) {
 	}
 	return m, nil
 }
+
+// GetCRDTType returns the resolution type recorded in the metadata of a key, if any
+func GetCRDTType(metadataBytes []byte) (string, error) {
+	metadata, err := Deserialize(metadataBytes)
+	if err != nil {
+		return "", err
+	}
+	return string(metadata[CRDTType]), nil
+}
+
+// SetCRDTType records the resolution type of a CRDT key in its metadata, the other entries being kept
+func SetCRDTType(metadataBytes []byte, resType string) ([]byte, error) {
+	return setEntry(metadataBytes, CRDTType, []byte(resType))
+}
+
+// GetCRDTHash returns the hash chained by the CRDT payloads merged into a private value, if any
+func GetCRDTHash(metadataBytes []byte) ([]byte, error) {
+	metadata, err := Deserialize(metadataBytes)
+	if err != nil {
+		return nil, err
+	}
+	return metadata[CRDTHash], nil
+}
+
+// SetCRDTHash records the hash chained by the CRDT payloads merged into a private value in its metadata,
+// the other entries being kept
+func SetCRDTHash(metadataBytes []byte, hash []byte) ([]byte, error) {
+	return setEntry(metadataBytes, CRDTHash, hash)
+}
+
+// setEntry sets an entry of the metadata. The entries are serialized in the order of their names,
+// so that the metadata is the same on every peer
+func setEntry(metadataBytes []byte, name string, value []byte) ([]byte, error) {
+	metadata, err := Deserialize(metadataBytes)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		metadata = map[string][]byte{}
+	}
+	metadata[name] = value
+	names := make([]string, 0, len(metadata))
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]*kvrwset.KVMetadataEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, &kvrwset.KVMetadataEntry{Name: name, Value: metadata[name]})
+	}
+	return Serialize(entries)
+}
@@ -31,3 +31,56 @@ func TestSerializeDeSerialize(t *testing.T) {
 	}
 	require.Equal(t, expectedMetadata, deserializedMetadata)
 }
+
+func TestCRDTType(t *testing.T) {
+	crdtType, err := GetCRDTType(nil)
+	require.NoError(t, err)
+	require.Empty(t, crdtType)
+
+	metadata, err := Serialize([]*kvrwset.KVMetadataEntry{{Name: "VALIDATION_PARAMETER", Value: []byte("policy")}})
+	require.NoError(t, err)
+	metadata, err = SetCRDTType(metadata, "IntAdd")
+	require.NoError(t, err)
+	crdtType, err = GetCRDTType(metadata)
+	require.NoError(t, err)
+	require.Equal(t, "IntAdd", crdtType)
+	deserializedMetadata, err := Deserialize(metadata)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		CRDTType:               []byte("IntAdd"),
+		"VALIDATION_PARAMETER": []byte("policy"),
+	}, deserializedMetadata)
+
+	// the entries are serialized in the order of their names
+	expected, err := Serialize([]*kvrwset.KVMetadataEntry{
+		{Name: CRDTType, Value: []byte("ORSet")},
+		{Name: "VALIDATION_PARAMETER", Value: []byte("policy")},
+	})
+	require.NoError(t, err)
+	metadata, err = SetCRDTType(metadata, "ORSet")
+	require.NoError(t, err)
+	require.Equal(t, expected, metadata)
+
+	_, err = GetCRDTType([]byte("corrupted"))
+	require.Error(t, err)
+}
+
+func TestCRDTHash(t *testing.T) {
+	hash, err := GetCRDTHash(nil)
+	require.NoError(t, err)
+	require.Nil(t, hash)
+
+	metadata, err := SetCRDTType(nil, "IntAdd")
+	require.NoError(t, err)
+	metadata, err = SetCRDTHash(metadata, []byte("hash"))
+	require.NoError(t, err)
+	hash, err = GetCRDTHash(metadata)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hash"), hash)
+	crdtType, err := GetCRDTType(metadata)
+	require.NoError(t, err)
+	require.Equal(t, "IntAdd", crdtType)
+
+	_, err = GetCRDTHash([]byte("corrupted"))
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txmgr
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+)
+
+// GetCRDTStateRangeScanIterator implements method in interface `ledger.QueryExecutor`. Only the CRDT keys
+// within the range are returned. No range query info is recorded, as the CRDT keys are merged at commit
+func (q *queryExecutor) GetCRDTStateRangeScanIterator(namespace, startKey, endKey string) (commonledger.ResultsIterator, error) {
+	if err := q.checkDone(); err != nil {
+		return nil, err
+	}
+	dbItr, err := q.txmgr.db.GetStateRangeScanIterator(namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: &crdtKeysItr{dbItr}}, nil
+}
+
+// ExecuteCRDTQuery implements method in interface `ledger.QueryExecutor`. The results of the query are
+// restricted to the CRDT keys and no read is recorded
+func (q *queryExecutor) ExecuteCRDTQuery(namespace, query string) (commonledger.ResultsIterator, error) {
+	if err := q.checkDone(); err != nil {
+		return nil, err
+	}
+	dbItr, err := q.txmgr.db.ExecuteQuery(namespace, query)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: &crdtKeysItr{dbItr}}, nil
+}
+
+// crdtKeysItr skips the keys returned by the underlying iterator that are not CRDT keys, i.e.
+// the keys with no CRDT type in their metadata, apart from the keys still named with the legacy prefix
+type crdtKeysItr struct {
+	statedb.ResultsIterator
+}
+
+// Next implements method in interface statedb.ResultsIterator
+func (itr *crdtKeysItr) Next() (*statedb.VersionedKV, error) {
+	for {
+		kv, err := itr.ResultsIterator.Next()
+		if err != nil || kv == nil {
+			return nil, err
+		}
+		if strings.HasPrefix(kv.Key, statedb.CRDTPrefix) {
+			return kv, nil
+		}
+		crdtType, err := statemetadata.GetCRDTType(kv.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if crdtType != "" {
+			return kv, nil
+		}
+	}
+}
+
+// pendingCRDTResultsItr merges the CRDT payloads added by the transaction being simulated
+// into the values returned by an iterator over CRDT keys
+type pendingCRDTResultsItr struct {
+	commonledger.ResultsIterator
+	txsim *txSimulator
+}
+
+// Next implements method in interface ledger.ResultsIterator
+func (itr *pendingCRDTResultsItr) Next() (commonledger.QueryResult, error) {
+	queryResult, err := itr.ResultsIterator.Next()
+	if err != nil || queryResult == nil {
+		return nil, err
+	}
+	kv := queryResult.(*queryresult.KV)
+	value, err := itr.txsim.mergePendingCRDTPayloads(kv.Namespace, kv.Key, kv.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &queryresult.KV{
+		Namespace: kv.Namespace,
+		Key:       kv.Key,
+		Value:     value,
+	}, nil
+}
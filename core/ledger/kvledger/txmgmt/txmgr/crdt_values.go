@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txmgr
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
+)
+
+// retainedFromKey holds the number of the first block whose CRDT values are retained. The key can't clash
+// with the keys of the values, which start with the length of the encoded block number
+var retainedFromKey = []byte{'r'}
+
+// CRDTValuesNotRetainedError is returned for a block whose CRDT values are no longer retained
+type CRDTValuesNotRetainedError struct {
+	BlockNum     uint64
+	RetainedFrom uint64
+}
+
+func (e *CRDTValuesNotRetainedError) Error() string {
+	return fmt.Sprintf("the CRDT values of block [%d] are no longer retained, the values are retained from block [%d]",
+		e.BlockNum, e.RetainedFrom)
+}
+
+// crdtValuesKeeper keeps track of the values the public CRDT keys merged by the valid transactions of a block
+// have right after each transaction, so that they can be delivered to the clients along with the block.
+// The values are recorded once the block is committed to the state database. Only the values of the last
+// retention blocks are kept, zero keeps the values of every block
+type crdtValuesKeeper struct {
+	db        *leveldbhelper.DBHandle
+	retention uint64
+}
+
+func newCRDTValuesKeeper(ledgerid string, provider *bookkeeping.Provider, retention uint64) *crdtValuesKeeper {
+	return &crdtValuesKeeper{provider.GetDBHandle(ledgerid, bookkeeping.CRDTValues), retention}
+}
+
+// update records the values of the CRDT keys merged by the transactions of the given block and prunes the
+// values of the blocks that fall out of the retention. As a block is committed again when the state is
+// rebuilt, the values recorded before for the block are overwritten
+func (k *crdtValuesKeeper) update(blockNum uint64, txsStatInfo []*validation.TxStatInfo) error {
+	updateBatch := k.db.NewUpdateBatch()
+	for txNum, txStatInfo := range txsStatInfo {
+		if len(txStatInfo.CRDTValues) == 0 {
+			continue
+		}
+		value, err := proto.Marshal(&peer.TxCRDTValues{
+			TxId:   txStatInfo.TxIDFromChannelHeader,
+			Values: txStatInfo.CRDTValues,
+		})
+		if err != nil {
+			return err
+		}
+		updateBatch.Put(encodeCRDTValuesKey(blockNum, uint64(txNum)), value)
+	}
+	if err := k.addPruneEntries(blockNum, updateBatch); err != nil {
+		return err
+	}
+	if updateBatch.Len() == 0 {
+		return nil
+	}
+	return k.db.WriteBatch(updateBatch, true)
+}
+
+// addPruneEntries adds to the batch the deletes of the values of the blocks that are no longer retained
+// once the given block is committed
+func (k *crdtValuesKeeper) addPruneEntries(blockNum uint64, updateBatch *leveldbhelper.UpdateBatch) error {
+	if k.retention == 0 || blockNum < k.retention {
+		return nil
+	}
+	retainFrom := blockNum - k.retention + 1
+	retainedFrom, err := k.retainedFrom()
+	if err != nil {
+		return err
+	}
+	if retainFrom <= retainedFrom {
+		return nil
+	}
+	itr, err := k.db.GetIterator(encodeCRDTValuesKey(0, 0), encodeCRDTValuesKey(retainFrom, 0))
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		updateBatch.Delete(append([]byte{}, itr.Key()...))
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	updateBatch.Put(retainedFromKey, util.EncodeOrderPreservingVarUint64(retainFrom))
+	return nil
+}
+
+func (k *crdtValuesKeeper) retainedFrom() (uint64, error) {
+	value, err := k.db.Get(retainedFromKey)
+	if err != nil || value == nil {
+		return 0, err
+	}
+	retainedFrom, _, err := util.DecodeOrderPreservingVarUint64(value)
+	return retainedFrom, err
+}
+
+// retrieve returns the values of the CRDT keys merged by the transactions of the given block, by transaction
+// number. An error is returned if the values of the block are no longer retained
+func (k *crdtValuesKeeper) retrieve(blockNum uint64) (map[uint64]*peer.TxCRDTValues, error) {
+	retainedFrom, err := k.retainedFrom()
+	if err != nil {
+		return nil, err
+	}
+	if blockNum < retainedFrom {
+		return nil, &CRDTValuesNotRetainedError{BlockNum: blockNum, RetainedFrom: retainedFrom}
+	}
+
+	itr, err := k.db.GetIterator(encodeCRDTValuesKey(blockNum, 0), encodeCRDTValuesKey(blockNum+1, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+
+	values := map[uint64]*peer.TxCRDTValues{}
+	for itr.Next() {
+		_, n, err := util.DecodeOrderPreservingVarUint64(itr.Key())
+		if err != nil {
+			return nil, err
+		}
+		txNum, _, err := util.DecodeOrderPreservingVarUint64(itr.Key()[n:])
+		if err != nil {
+			return nil, err
+		}
+		txValues := &peer.TxCRDTValues{}
+		if err := proto.Unmarshal(itr.Value(), txValues); err != nil {
+			return nil, err
+		}
+		values[txNum] = txValues
+	}
+	return values, itr.Error()
+}
+
+func encodeCRDTValuesKey(blockNum, txNum uint64) []byte {
+	return append(util.EncodeOrderPreservingVarUint64(blockNum), util.EncodeOrderPreservingVarUint64(txNum)...)
+}
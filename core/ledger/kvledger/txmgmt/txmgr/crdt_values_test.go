@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txmgr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRDTValuesKeeper(t *testing.T) {
+	bookkeepingEnv := bookkeeping.NewTestEnv(t)
+	defer bookkeepingEnv.Cleanup()
+	keeper := newCRDTValuesKeeper("ledger1", bookkeepingEnv.TestProvider, 0)
+
+	values := func(key, value string) []*peer.CRDTValue {
+		return []*peer.CRDTValue{{Namespace: "ns1", Key: key, Value: []byte(value)}}
+	}
+	require.NoError(t, keeper.update(5, []*validation.TxStatInfo{
+		{TxIDFromChannelHeader: "tx0", CRDTValues: values("key1", "1")},
+		{TxIDFromChannelHeader: "tx1"},
+		{TxIDFromChannelHeader: "tx2", CRDTValues: values("key1", "3")},
+	}))
+	require.NoError(t, keeper.update(6, []*validation.TxStatInfo{
+		{TxIDFromChannelHeader: "tx3", CRDTValues: values("key2", "4")},
+	}))
+	// a block without CRDT values
+	require.NoError(t, keeper.update(7, []*validation.TxStatInfo{{TxIDFromChannelHeader: "tx4"}}))
+
+	retrieved, err := keeper.retrieve(5)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 2)
+	require.True(t, proto.Equal(&peer.TxCRDTValues{TxId: "tx0", Values: values("key1", "1")}, retrieved[0]))
+	require.True(t, proto.Equal(&peer.TxCRDTValues{TxId: "tx2", Values: values("key1", "3")}, retrieved[2]))
+
+	retrieved, err = keeper.retrieve(6)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 1)
+	require.True(t, proto.Equal(&peer.TxCRDTValues{TxId: "tx3", Values: values("key2", "4")}, retrieved[0]))
+
+	retrieved, err = keeper.retrieve(7)
+	require.NoError(t, err)
+	require.Empty(t, retrieved)
+
+	// the values of a block prepared again are overwritten
+	require.NoError(t, keeper.update(6, []*validation.TxStatInfo{
+		{TxIDFromChannelHeader: "tx3", CRDTValues: values("key2", "5")},
+	}))
+	retrieved, err = keeper.retrieve(6)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&peer.TxCRDTValues{TxId: "tx3", Values: values("key2", "5")}, retrieved[0]))
+}
+
+func TestCRDTValuesKeeperRetention(t *testing.T) {
+	bookkeepingEnv := bookkeeping.NewTestEnv(t)
+	defer bookkeepingEnv.Cleanup()
+	keeper := newCRDTValuesKeeper("ledger1", bookkeepingEnv.TestProvider, 2)
+
+	for blockNum := uint64(1); blockNum <= 4; blockNum++ {
+		require.NoError(t, keeper.update(blockNum, []*validation.TxStatInfo{
+			{TxIDFromChannelHeader: "tx", CRDTValues: []*peer.CRDTValue{{Namespace: "ns1", Key: "key1", Value: []byte("1")}}},
+		}))
+	}
+
+	// only the values of the last two blocks are kept
+	for _, blockNum := range []uint64{1, 2} {
+		_, err := keeper.retrieve(blockNum)
+		require.EqualError(t, err, fmt.Sprintf("the CRDT values of block [%d] are no longer retained, the values are retained from block [3]", blockNum))
+	}
+	for _, blockNum := range []uint64{3, 4} {
+		retrieved, err := keeper.retrieve(blockNum)
+		require.NoError(t, err)
+		require.Len(t, retrieved, 1)
+	}
+	itr, err := keeper.db.GetIterator(encodeCRDTValuesKey(0, 0), encodeCRDTValuesKey(3, 0))
+	require.NoError(t, err)
+	defer itr.Release()
+	require.False(t, itr.Next())
+
+	// a block without CRDT values prunes the values as well
+	require.NoError(t, keeper.update(5, []*validation.TxStatInfo{{TxIDFromChannelHeader: "tx"}}))
+	_, err = keeper.retrieve(3)
+	require.Error(t, err)
+	retrieved, err := keeper.retrieve(4)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 1)
+}
@@ -8,12 +8,15 @@ package txmgr
 
 import (
 	"bytes"
+	"runtime"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/snapshot"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -23,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/pvtstatepurgemgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/queryutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -44,6 +48,9 @@ type LockBasedTxMgr struct {
 	oldBlockCommit      sync.Mutex
 	currentUpdates      *currentUpdates
 	hashFunc            rwsetutil.HashFunc
+	crdtResolvers       *crdt_resolver.Registry
+	crdtValuesKeeper    *crdtValuesKeeper
+	pvtdataOfBlock      PvtdataOfBlockFunc
 }
 
 // pvtdataPurgeMgr wraps the actual purge manager and an additional flag 'usedOnce'
@@ -54,9 +61,10 @@ type pvtdataPurgeMgr struct {
 }
 
 type currentUpdates struct {
-	block     *common.Block
-	batch     *privacyenabledstate.UpdateBatch
-	listeners []ledger.StateListener
+	block       *common.Block
+	batch       *privacyenabledstate.UpdateBatch
+	txsStatInfo []*validation.TxStatInfo
+	listeners   []ledger.StateListener
 }
 
 func (c *currentUpdates) blockNum() uint64 {
@@ -83,6 +91,14 @@ type Initializer struct {
 	CCInfoProvider      ledger.DeployedChaincodeInfoProvider
 	CustomTxProcessors  map[common.HeaderType]ledger.CustomTxProcessor
 	HashFunc            rwsetutil.HashFunc
+	CRDTResolvers       *crdt_resolver.Registry
+	// CRDTMergeWorkers defaults to the number of CPUs, see ledger.CRDTConfig
+	CRDTMergeWorkers int
+	// CRDTValuesRetention is the number of the last blocks whose CRDT values are kept, see ledger.CRDTConfig
+	CRDTValuesRetention uint64
+	// PvtdataOfBlock lets the CRDT payloads of the private data the peer holds be replayed along with the
+	// reconciled private data of the CRDT keys, see RemoveStaleAndCommitPvtDataOfOldBlocks. It may be nil
+	PvtdataOfBlock PvtdataOfBlockFunc
 }
 
 // NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
@@ -91,15 +107,27 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		return nil, errors.New("create new lock based TxMgr failed: passed in nil ledger hasher")
 	}
 
+	crdtResolvers := initializer.CRDTResolvers
+	if crdtResolvers == nil {
+		crdtResolvers = crdt_resolver.NewRegistry()
+	}
+	crdtMergeWorkers := initializer.CRDTMergeWorkers
+	if crdtMergeWorkers <= 0 {
+		crdtMergeWorkers = runtime.NumCPU()
+	}
+
 	if err := initializer.DB.Open(); err != nil {
 		return nil, err
 	}
 	txmgr := &LockBasedTxMgr{
-		ledgerid:       initializer.LedgerID,
-		db:             initializer.DB,
-		stateListeners: initializer.StateListeners,
-		ccInfoProvider: initializer.CCInfoProvider,
-		hashFunc:       initializer.HashFunc,
+		ledgerid:         initializer.LedgerID,
+		db:               initializer.DB,
+		stateListeners:   initializer.StateListeners,
+		ccInfoProvider:   initializer.CCInfoProvider,
+		hashFunc:         initializer.HashFunc,
+		crdtResolvers:    crdtResolvers,
+		crdtValuesKeeper: newCRDTValuesKeeper(initializer.LedgerID, initializer.BookkeepingProvider, initializer.CRDTValuesRetention),
+		pvtdataOfBlock:   initializer.PvtdataOfBlock,
 	}
 	pvtstatePurgeMgr, err := pvtstatepurgemgmt.InstantiatePurgeMgr(
 		initializer.LedgerID,
@@ -114,10 +142,21 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		txmgr,
 		initializer.DB,
 		initializer.CustomTxProcessors,
-		initializer.HashFunc)
+		initializer.HashFunc,
+		crdtResolvers,
+		txmgr,
+		crdtMergeWorkers)
 	return txmgr, nil
 }
 
+// GetCRDTValuesByNum returns the values the public CRDT keys merged by the valid transactions of the given block
+// have right after each transaction, by transaction number. The values are not known for the blocks committed
+// before the ledger recorded them, nor for a block whose commit to the state database was the last one before
+// a crash of the peer. An error is returned for the blocks whose values are no longer retained
+func (txmgr *LockBasedTxMgr) GetCRDTValuesByNum(blockNum uint64) (map[uint64]*peer.TxCRDTValues, error) {
+	return txmgr.crdtValuesKeeper.retrieve(blockNum)
+}
+
 // GetLastSavepoint returns the block num recorded in savepoint,
 // returns 0 if NO savepoint is found
 func (txmgr *LockBasedTxMgr) GetLastSavepoint() (*version.Height, error) {
@@ -185,7 +224,7 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 		txmgr.reset()
 		return nil, nil, nil, err
 	}
-	txmgr.currentUpdates = &currentUpdates{block: block, batch: batch}
+	txmgr.currentUpdates = &currentUpdates{block: block, batch: batch, txsStatInfo: txstatsInfo}
 	if err := txmgr.invokeNamespaceListeners(); err != nil {
 		txmgr.reset()
 		return nil, nil, nil, err
@@ -200,7 +239,8 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 // (1) constructs the unique pvt data from the passed reconciledPvtdata
 // (2) acquire a lock on oldBlockCommit
 // (3) checks for stale pvtData by comparing [version, valueHash] and removes stale data
-// (4) creates update batch from the the non-stale pvtData
+// (4) creates update batch from the the non-stale pvtData, along with the values of the CRDT keys the
+// reconciled CRDT payloads are merged into, which are replayed against the hashed keys
 // (5) update the BTL bookkeeping managed by the purge manager and update expiring keys.
 // (6) commit the non-stale pvt data to the stateDB
 // This function assumes that the passed input contains only transactions that had been
@@ -230,9 +270,16 @@ func (txmgr *LockBasedTxMgr) RemoveStaleAndCommitPvtDataOfOldBlocks(reconciledPv
 	// versions and use the one with the higher version
 	logger.Debug("Constructing unique pvtData by removing duplicate entries")
 	uniquePvtData, err := constructUniquePvtData(reconciledPvtdata)
-	if len(uniquePvtData) == 0 || err != nil {
+	if err != nil {
+		return err
+	}
+	crdtReplay, err := constructPvtCRDTReplay(reconciledPvtdata)
+	if err != nil {
 		return err
 	}
+	if len(uniquePvtData) == 0 && len(crdtReplay) == 0 {
+		return nil
+	}
 
 	// (3) remove the pvt data which does not matches the hashed
 	// value stored in the public state
@@ -243,6 +290,10 @@ func (txmgr *LockBasedTxMgr) RemoveStaleAndCommitPvtDataOfOldBlocks(reconciledPv
 
 	// (4) create the update batch from the uniquePvtData
 	batch := uniquePvtData.transformToUpdateBatch()
+	logger.Debug("Replaying the CRDT payloads of the reconciled pvtData")
+	if err := crdtReplay.apply(txmgr.db, txmgr.crdtResolvers, txmgr.pvtdataOfBlock, batch); err != nil {
+		return err
+	}
 
 	// (5) update bookkeeping in the purge manager and update toPurgeList
 	// (i.e., the list of expiry keys). As the expiring keys would have
@@ -481,6 +532,25 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 	return nil
 }
 
+// CRDTSchema implements method in interface `validation.CRDTSchemaProvider`. The schema
+// is read from the committed chaincode definition of the namespace
+func (txmgr *LockBasedTxMgr) CRDTSchema(ns string) (*lifecycle.CRDTSchema, error) {
+	if txmgr.ccInfoProvider == nil {
+		return nil, nil
+	}
+	committedStateQueryExecuter := &queryutil.QECombiner{
+		QueryExecuters: []queryutil.QueryExecuter{txmgr.db},
+	}
+	ccInfo, err := txmgr.ccInfoProvider.ChaincodeInfo(txmgr.ledgerid, ns, committedStateQueryExecuter)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not get the CRDT schema of namespace %s", ns)
+	}
+	if ccInfo == nil {
+		return nil, nil
+	}
+	return ccInfo.CRDTSchema, nil
+}
+
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	// wait for background go routine to finish else the timing issue causes a nil pointer inside goleveldb code
@@ -550,6 +620,12 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	txmgr.clearCache()
 	logger.Debugf("Updates committed to state database and the write lock is released")
 
+	// the values of the CRDT keys are only recorded once the state is committed, so that the values of a
+	// block that fails to commit are never delivered
+	if err := txmgr.crdtValuesKeeper.update(txmgr.currentUpdates.blockNum(), txmgr.currentUpdates.txsStatInfo); err != nil {
+		return err
+	}
+
 	// purge manager should be called (in this call the purge mgr removes the expiry entries from schedules) after committing to statedb
 	if err := txmgr.pvtdataPurgeMgr.BlockCommitDone(); err != nil {
 		return err
@@ -0,0 +1,238 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txmgr
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+	"github.com/hyperledger/fabric/core/ledger/util"
+)
+
+// PvtdataOfBlockFunc returns the private data of the given collections that the peer holds for the valid
+// transactions of a committed block
+type PvtdataOfBlockFunc func(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
+
+// pvtCRDTOp is a CRDT payload, or a write, of the private data on a private key
+type pvtCRDTOp struct {
+	height *version.Height
+	// seq orders the operations of a transaction, the payloads precede the writes as at commit
+	seq     int
+	payload *kvrwset.CRDTPayload
+	write   *kvrwset.KVWrite
+}
+
+type pvtCRDTKey struct {
+	key  string
+	hash privacyenabledstate.HashedCompositeKey
+}
+
+// pvtCRDTReplay holds the operations of the reconciled private data on the private keys that a reconciled
+// CRDT payload is merged into. Unlike a write, a payload can't be committed on its own, since the value of a
+// CRDT key is the merge of every payload. Instead, the operations of the reconciled private data and of the
+// private data the peer already holds are replayed in block order on the private value of the key, and the
+// result is only committed if the hash chained along the way matches the hash of the hashed key, see
+// validation.pvtCRDTMerger
+type pvtCRDTReplay map[pvtCRDTKey][]*pvtCRDTOp
+
+func constructPvtCRDTReplay(reconciledPvtdata map[uint64][]*ledger.TxPvtData) (pvtCRDTReplay, error) {
+	replay, writes := make(pvtCRDTReplay), make(pvtCRDTReplay)
+	for blkNum, blockPvtData := range reconciledPvtdata {
+		for _, txPvtData := range blockPvtData {
+			txPayloads, txWrites, err := pvtCRDTOpsOf(txPvtData, version.NewHeight(blkNum, txPvtData.SeqInBlock))
+			if err != nil {
+				return nil, err
+			}
+			for k, ops := range txPayloads {
+				replay[k] = append(replay[k], ops...)
+			}
+			for k, ops := range txWrites {
+				writes[k] = append(writes[k], ops...)
+			}
+		}
+	}
+	for k := range replay {
+		replay.add(k, writes[k])
+	}
+	return replay, nil
+}
+
+// pvtCRDTOpsOf returns the CRDT payloads and the writes of the private data of a transaction
+func pvtCRDTOpsOf(txPvtData *ledger.TxPvtData, height *version.Height) (payloads, writes pvtCRDTReplay, err error) {
+	payloads, writes = make(pvtCRDTReplay), make(pvtCRDTReplay)
+	for _, nsPvtData := range txPvtData.WriteSet.GetNsPvtRwset() {
+		for _, collPvtData := range nsPvtData.CollectionPvtRwset {
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(collPvtData.Rwset, kvRWSet); err != nil {
+				return nil, nil, err
+			}
+			newKey := func(key string) pvtCRDTKey {
+				return pvtCRDTKey{key, privacyenabledstate.HashedCompositeKey{
+					Namespace:      nsPvtData.Namespace,
+					CollectionName: collPvtData.CollectionName,
+					KeyHash:        string(util.ComputeStringHash(key)),
+				}}
+			}
+			seq := 0
+			for _, payload := range kvRWSet.CrdtPayload {
+				k := newKey(payload.Key)
+				payloads[k] = append(payloads[k], &pvtCRDTOp{height: height, seq: seq, payload: payload})
+				seq++
+			}
+			for _, write := range kvRWSet.Writes {
+				k := newKey(write.Key)
+				writes[k] = append(writes[k], &pvtCRDTOp{height: height, seq: seq, write: write})
+				seq++
+			}
+		}
+	}
+	return payloads, writes, nil
+}
+
+// add adds the operations on a key, keeping the operations of the key in block order
+func (replay pvtCRDTReplay) add(k pvtCRDTKey, ops []*pvtCRDTOp) {
+	keyOps := append(replay[k], ops...)
+	sort.SliceStable(keyOps, func(i, j int) bool {
+		if c := keyOps[i].height.Compare(keyOps[j].height); c != 0 {
+			return c < 0
+		}
+		return keyOps[i].seq < keyOps[j].seq
+	})
+	replay[k] = keyOps
+}
+
+// apply replays the operations on the private values of the keys and adds the values whose chained hash
+// matches the hash of the hashed key to the batch, at the version of the hashed key. The operations of the
+// transactions whose private data the peer already holds, that were not merged into the stale value of the
+// key, are loaded by pvtdataOfBlock, if set. The keys whose value still misses some private data are left stale
+func (replay pvtCRDTReplay) apply(db *privacyenabledstate.DB, resolvers *crdt_resolver.Registry,
+	pvtdataOfBlock PvtdataOfBlockFunc, batch *privacyenabledstate.UpdateBatch) error {
+	for k, ops := range replay {
+		ns, coll := k.hash.Namespace, k.hash.CollectionName
+		committedHash, err := db.GetValueHash(ns, coll, []byte(k.hash.KeyHash))
+		if err != nil {
+			return err
+		}
+		if committedHash == nil {
+			continue
+		}
+		pvtVV, err := db.GetPrivateData(ns, coll, k.key)
+		if err != nil {
+			return err
+		}
+		var value, valueHash, metadata []byte
+		var ver *version.Height
+		if pvtVV != nil {
+			value, metadata, ver = pvtVV.Value, pvtVV.Metadata, pvtVV.Version
+			if valueHash, err = statemetadata.GetCRDTHash(metadata); err != nil {
+				return err
+			}
+			// the values that are not merged by the peer are stored without the hash of their hashed key
+			if valueHash == nil {
+				valueHash = util.ComputeHash(value)
+			}
+		}
+		if version.AreSame(ver, committedHash.Version) {
+			continue
+		}
+
+		if pvtdataOfBlock != nil {
+			from := ops[0].height.BlockNum
+			if ver != nil {
+				from = ver.BlockNum
+			}
+			if ops, err = replay.withHeldOps(k, from, committedHash.Version.BlockNum, pvtdataOfBlock); err != nil {
+				return err
+			}
+		}
+
+		var replayed []byte
+		replayedMatch := false
+		for i, op := range ops {
+			if ver != nil && op.height.Compare(ver) <= 0 {
+				continue
+			}
+			if value, valueHash, err = op.replay(value, valueHash, resolvers); err != nil {
+				logger.Warningf("CRDT error <%s> while replaying the private data of key [%s] in collection [%s:%s] at height [%s]",
+					err, k.key, ns, coll, op.height)
+				break
+			}
+			// the value is only checked once every operation of the transaction is replayed
+			if i+1 < len(ops) && version.AreSame(ops[i+1].height, op.height) {
+				continue
+			}
+			if valueHash != nil && bytes.Equal(valueHash, committedHash.Value) {
+				replayed, replayedMatch = value, true
+			}
+		}
+		if !replayedMatch {
+			logger.Debugf("Private data of CRDT key [%s] in collection [%s:%s] does not lead to the hash of the hashed key, the key is left stale",
+				k.key, ns, coll)
+			continue
+		}
+		if metadata, err = statemetadata.SetCRDTHash(metadata, committedHash.Value); err != nil {
+			return err
+		}
+		batch.PvtUpdates.PutValAndMetadata(ns, coll, k.key, replayed, metadata, committedHash.Version)
+	}
+	return nil
+}
+
+// withHeldOps returns the operations on the key of the reconciled private data along with the ones of the
+// private data the peer holds for the blocks in the given range, for the transactions that are not reconciled
+func (replay pvtCRDTReplay) withHeldOps(k pvtCRDTKey, fromBlock, toBlock uint64, pvtdataOfBlock PvtdataOfBlockFunc) ([]*pvtCRDTOp, error) {
+	reconciled := map[version.Height]bool{}
+	for _, op := range replay[k] {
+		reconciled[*op.height] = true
+	}
+	filter := ledger.NewPvtNsCollFilter()
+	filter.Add(k.hash.Namespace, k.hash.CollectionName)
+	held := pvtCRDTReplay{}
+	for blkNum := fromBlock; blkNum <= toBlock; blkNum++ {
+		blockPvtData, err := pvtdataOfBlock(blkNum, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, txPvtData := range blockPvtData {
+			height := version.NewHeight(blkNum, txPvtData.SeqInBlock)
+			if reconciled[*height] {
+				continue
+			}
+			payloads, writes, err := pvtCRDTOpsOf(txPvtData, height)
+			if err != nil {
+				return nil, err
+			}
+			held.add(k, append(payloads[k], writes[k]...))
+		}
+	}
+	held.add(k, replay[k])
+	return held[k], nil
+}
+
+// replay applies the operation to the value of the key and to the hash of its hashed key
+func (op *pvtCRDTOp) replay(value, valueHash []byte, resolvers *crdt_resolver.Registry) ([]byte, []byte, error) {
+	switch {
+	case op.payload != nil:
+		merged, err := resolvers.ResolveAt(value, op.payload.Data, op.payload.ResolutionType, op.height)
+		if err != nil {
+			return nil, nil, err
+		}
+		return merged, util.ComputeHash(append(append([]byte{}, valueHash...), util.ComputeHash(op.payload.Data)...)), nil
+	case rwsetutil.IsKVWriteDelete(op.write):
+		return nil, nil, nil
+	default:
+		return op.write.Value, util.ComputeHash(op.write.Value), nil
+	}
+}
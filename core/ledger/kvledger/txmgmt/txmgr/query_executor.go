@@ -212,19 +212,46 @@ func (q *queryExecutor) ExecuteQueryWithPagination(namespace, query, bookmark st
 
 // GetPrivateData implements method in interface `ledger.QueryExecutor`
 func (q *queryExecutor) GetPrivateData(ns, coll, key string) ([]byte, error) {
-	if err := q.validateCollName(ns, coll); err != nil {
+	val, ver, err := q.getPrivateData(ns, coll, key)
+	if err != nil {
 		return nil, err
 	}
-	if err := q.checkDone(); err != nil {
+	if q.collectReadset {
+		q.rwsetBuilder.AddToHashedReadSet(ns, coll, key, ver)
+		q.privateReads.Add(ns, coll)
+	}
+	return val, nil
+}
+
+// GetPrivateCRDTState implements method in interface `ledger.QueryExecutor`. As for the
+// public CRDT keys, the key is not added to the read-set
+func (q *queryExecutor) GetPrivateCRDTState(ns, coll, key string) ([]byte, error) {
+	val, _, err := q.getPrivateData(ns, coll, key)
+	if err != nil {
 		return nil, err
 	}
+	if q.collectReadset {
+		q.privateReads.Add(ns, coll)
+	}
+	return val, nil
+}
+
+// getPrivateData returns the value and the version of a private data item, checking
+// that the peer holds the private data matching the version of the hashed key
+func (q *queryExecutor) getPrivateData(ns, coll, key string) ([]byte, *version.Height, error) {
+	if err := q.validateCollName(ns, coll); err != nil {
+		return nil, nil, err
+	}
+	if err := q.checkDone(); err != nil {
+		return nil, nil, err
+	}
 
 	var err error
 	var hashVersion *version.Height
 	var versionedValue *statedb.VersionedValue
 
 	if versionedValue, err = q.txmgr.db.GetPrivateData(ns, coll, key); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// metadata is always nil for private data - because, the metadata is part of the hashed key (instead of raw key)
@@ -232,19 +259,15 @@ func (q *queryExecutor) GetPrivateData(ns, coll, key string) ([]byte, error) {
 
 	keyHash := util.ComputeStringHash(key)
 	if hashVersion, err = q.txmgr.db.GetKeyHashVersion(ns, coll, keyHash); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if !version.AreSame(hashVersion, ver) {
-		return nil, errors.Errorf(
+		return nil, nil, errors.Errorf(
 			"private data matching public hash version is not available. Public hash version = %s, Private data version = %s",
 			hashVersion, ver,
 		)
 	}
-	if q.collectReadset {
-		q.rwsetBuilder.AddToHashedReadSet(ns, coll, key, ver)
-		q.privateReads.Add(ns, coll)
-	}
-	return val, nil
+	return val, ver, nil
 }
 
 func (q *queryExecutor) GetPrivateDataHash(ns, coll, key string) ([]byte, error) {
@@ -7,14 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package txmgr
 
 import (
+	"math"
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/pkg/errors"
 )
 
+// pendingCRDTHeight is the height at which the CRDT payloads of the transaction being
+// simulated are merged into the committed values. It is above the height of any committed
+// transaction and can't be mistaken for the one the transaction will be committed at
+var pendingCRDTHeight = version.NewHeight(math.MaxUint64, 0)
+
 // txSimulator is a transaction simulator used in `LockBasedTxMgr`
 type txSimulator struct {
 	*queryExecutor
@@ -43,8 +55,290 @@ func (s *txSimulator) SetState(ns string, key string, value []byte) error {
 	return s.checkStateMetadata(ns, key)
 }
 
-func (s *txSimulator) SetCRDT(ns string, resType string, key string, value []byte) error {
-	s.rwsetBuilder.AddToCRDT(ns, resType, key, value)
+// GetCRDTState implements method in interface `ledger.QueryExecutor`. The CRDT payloads added
+// by the transaction for the key are merged into the committed value, so that the chaincode
+// reads the value the key would have if the transaction were committed right away
+func (s *txSimulator) GetCRDTState(ns, key string) ([]byte, error) {
+	if err := s.checkDone(); err != nil {
+		return nil, err
+	}
+	key, err := s.crdtKey(ns, key)
+	if err != nil {
+		return nil, err
+	}
+	val, err := s.queryExecutor.GetCRDTState(ns, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.mergePendingCRDTPayloads(ns, key, val)
+}
+
+// GetCRDTStateRangeScanIterator implements method in interface `ledger.QueryExecutor`. The CRDT payloads
+// added by the transaction are merged into the values of the keys returned. The keys not committed yet
+// are not returned
+func (s *txSimulator) GetCRDTStateRangeScanIterator(ns, startKey, endKey string) (commonledger.ResultsIterator, error) {
+	itr, err := s.queryExecutor.GetCRDTStateRangeScanIterator(ns, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return &pendingCRDTResultsItr{itr, s}, nil
+}
+
+// ExecuteCRDTQuery implements method in interface `ledger.QueryExecutor`. The query is evaluated against
+// the committed values, then the CRDT payloads added by the transaction are merged into the values returned
+func (s *txSimulator) ExecuteCRDTQuery(ns, query string) (commonledger.ResultsIterator, error) {
+	itr, err := s.queryExecutor.ExecuteCRDTQuery(ns, query)
+	if err != nil {
+		return nil, err
+	}
+	return &pendingCRDTResultsItr{itr, s}, nil
+}
+
+func (s *txSimulator) mergePendingCRDTPayloads(ns, key string, val []byte) ([]byte, error) {
+	var err error
+	for _, payload := range s.rwsetBuilder.GetCRDTPayloads(ns, key) {
+		switch payload.Operation {
+		case kvrwset.CRDTPayload_DELETE:
+			val = nil
+			continue
+		case kvrwset.CRDTPayload_RESET:
+			val = []byte{}
+		}
+		// the predicates of the pending payloads were checked when they were added
+		val, err = s.txmgr.crdtResolvers.ResolveAt(val, payload.Data, payload.ResolutionType, pendingCRDTHeight)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to merge the pending CRDT writes of key [%s] in namespace [%s]", key, ns)
+		}
+	}
+	return val, nil
+}
+
+// crdtType returns the CRDT type the key would have if the transaction were committed right away,
+// i.e. the type recorded in the committed metadata of the key updated by the pending CRDT payloads
+func (s *txSimulator) crdtType(ns, key string) (string, error) {
+	metadata, err := s.txmgr.db.GetStateMetadata(ns, key)
+	if err != nil {
+		return "", err
+	}
+	keyType, err := statemetadata.GetCRDTType(metadata)
+	if err != nil {
+		return "", err
+	}
+	for _, payload := range s.rwsetBuilder.GetCRDTPayloads(ns, key) {
+		switch {
+		case payload.Operation == kvrwset.CRDTPayload_DELETE:
+			keyType = ""
+		case payload.Operation == kvrwset.CRDTPayload_RESET || keyType == "":
+			keyType = payload.ResolutionType
+		}
+	}
+	return keyType, nil
+}
+
+// privateCRDTType returns the CRDT type the private key would have if the transaction were committed right away
+func (s *txSimulator) privateCRDTType(ns, coll, key string) (string, error) {
+	metadata, err := s.txmgr.db.GetPrivateDataMetadataByHash(ns, coll, util.ComputeStringHash(key))
+	if err != nil {
+		return "", err
+	}
+	keyType, err := statemetadata.GetCRDTType(metadata)
+	if err != nil || keyType != "" {
+		return keyType, err
+	}
+	if payloads := s.rwsetBuilder.GetPvtCRDTPayloads(ns, coll, key); len(payloads) != 0 {
+		keyType = payloads[0].ResolutionType
+	}
+	return keyType, nil
+}
+
+// crdtKey returns the key a chaincode addresses as the CRDT key `key`. The earlier versions of the shim
+// prefixed the CRDT keys with statedb.CRDTPrefix, hence while the key holds no CRDT type, the prefixed key,
+// if it exists, is read and merged into instead, so that a chaincode moved to the current shim keeps its
+// existing CRDT keys. A reset gives the key a CRDT type, which makes the chaincode address the key itself
+func (s *txSimulator) crdtKey(ns, key string) (string, error) {
+	if strings.HasPrefix(key, statedb.CRDTPrefix) {
+		return key, nil
+	}
+	keyType, err := s.crdtType(ns, key)
+	if err != nil || keyType != "" {
+		return key, err
+	}
+	legacyKey := statedb.CRDTPrefix + key
+	if payloads := s.rwsetBuilder.GetCRDTPayloads(ns, legacyKey); len(payloads) != 0 {
+		if payloads[len(payloads)-1].Operation == kvrwset.CRDTPayload_DELETE {
+			return key, nil
+		}
+		return legacyKey, nil
+	}
+	vv, err := s.txmgr.db.GetState(ns, legacyKey)
+	if err != nil || vv == nil {
+		return key, err
+	}
+	return legacyKey, nil
+}
+
+// privateCRDTKey returns the private key a chaincode addresses as the CRDT key `key`, see crdtKey
+func (s *txSimulator) privateCRDTKey(ns, coll, key string) (string, error) {
+	if strings.HasPrefix(key, statedb.CRDTPrefix) {
+		return key, nil
+	}
+	keyType, err := s.privateCRDTType(ns, coll, key)
+	if err != nil || keyType != "" {
+		return key, err
+	}
+	legacyKey := statedb.CRDTPrefix + key
+	if len(s.rwsetBuilder.GetPvtCRDTPayloads(ns, coll, legacyKey)) != 0 {
+		return legacyKey, nil
+	}
+	vv, err := s.txmgr.db.GetValueHash(ns, coll, util.ComputeStringHash(legacyKey))
+	if err != nil || vv == nil {
+		return key, err
+	}
+	return legacyKey, nil
+}
+
+// SetCRDT implements method in interface `ledger.TxSimulator`. The CRDT type of the key is checked,
+// the predicates are evaluated and the diff is merged, without being recorded, into the value of the
+// key read by the transaction, so that the unknown resolution types, the mismatching types, the
+// malformed diffs and the predicates that already fail are rejected before the transaction is ordered
+func (s *txSimulator) SetCRDT(ns string, resType string, key string, value []byte, predicates []*kvrwset.CRDTPredicate) error {
+	if err := s.checkDone(); err != nil {
+		return err
+	}
+	if _, ok := s.txmgr.crdtResolvers.Lookup(resType); !ok {
+		return errors.Errorf("txid [%s]: unknown CRDT resolve type [%s]", s.txid, resType)
+	}
+	key, err := s.crdtKey(ns, key)
+	if err != nil {
+		return err
+	}
+	keyType, err := s.crdtType(ns, key)
+	if err != nil {
+		return err
+	}
+	if err := crdt_resolver.CheckType(key, keyType, resType); err != nil {
+		return errors.WithMessagef(err, "txid [%s]: invalid %s diff for key [%s] in namespace [%s]", s.txid, resType, key, ns)
+	}
+	observed, err := s.GetCRDTState(ns, key)
+	if err != nil {
+		return err
+	}
+	if err := crdt_resolver.CheckPredicates(observed, resType, predicates); err != nil {
+		return errors.WithMessagef(err, "txid [%s]: predicate on key [%s] in namespace [%s] does not hold", s.txid, key, ns)
+	}
+	merged, err := s.txmgr.crdtResolvers.ResolveAt(observed, value, resType, pendingCRDTHeight)
+	if err != nil {
+		return errors.WithMessagef(err, "txid [%s]: invalid %s diff for key [%s] in namespace [%s]", s.txid, resType, key, ns)
+	}
+	if err := s.checkWritePrecondition(key, merged); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddToCRDT(ns, resType, key, value, predicates)
+	return nil
+}
+
+// ResetCRDT implements method in interface `ledger.TxSimulator`. As in SetCRDT, the value is
+// merged, without being recorded, into an empty value so that a malformed value is rejected
+// before the transaction is ordered
+func (s *txSimulator) ResetCRDT(ns string, resType string, key string, value []byte) error {
+	if err := s.checkDone(); err != nil {
+		return err
+	}
+	if _, ok := s.txmgr.crdtResolvers.Lookup(resType); !ok {
+		return errors.Errorf("txid [%s]: unknown CRDT resolve type [%s]", s.txid, resType)
+	}
+	reset, err := s.txmgr.crdtResolvers.ResolveAt([]byte{}, value, resType, pendingCRDTHeight)
+	if err != nil {
+		return errors.WithMessagef(err, "txid [%s]: invalid %s value for key [%s] in namespace [%s]", s.txid, resType, key, ns)
+	}
+	if err := s.checkWritePrecondition(key, reset); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddResetToCRDT(ns, resType, key, value)
+	return nil
+}
+
+// DeleteCRDT implements method in interface `ledger.TxSimulator`
+func (s *txSimulator) DeleteCRDT(ns string, key string) error {
+	if err := s.checkDone(); err != nil {
+		return err
+	}
+	key, err := s.crdtKey(ns, key)
+	if err != nil {
+		return err
+	}
+	if err := s.checkWritePrecondition(key, nil); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddDeleteToCRDT(ns, key)
+	return nil
+}
+
+// GetPrivateCRDTState implements method in interface `ledger.QueryExecutor`. The CRDT payloads added
+// by the transaction for the key are merged into the committed value
+func (s *txSimulator) GetPrivateCRDTState(ns, coll, key string) ([]byte, error) {
+	if err := s.checkDone(); err != nil {
+		return nil, err
+	}
+	key, err := s.privateCRDTKey(ns, coll, key)
+	if err != nil {
+		return nil, err
+	}
+	val, err := s.queryExecutor.GetPrivateCRDTState(ns, coll, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, payload := range s.rwsetBuilder.GetPvtCRDTPayloads(ns, coll, key) {
+		val, err = s.txmgr.crdtResolvers.ResolveAt(val, payload.Data, payload.ResolutionType, pendingCRDTHeight)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to merge the pending CRDT writes of key [%s] in collection [%s:%s]", key, ns, coll)
+		}
+	}
+	return val, nil
+}
+
+// SetPrivateCRDT implements method in interface `ledger.TxSimulator`. Besides the checks of SetCRDT,
+// the CRDT schema of the namespace is enforced here, as the peers that do not hold the private data
+// can't check the key at commit
+func (s *txSimulator) SetPrivateCRDT(ns, coll, resType, key string, value []byte) error {
+	if err := s.queryExecutor.validateCollName(ns, coll); err != nil {
+		return err
+	}
+	if err := s.checkDone(); err != nil {
+		return err
+	}
+	if _, ok := s.txmgr.crdtResolvers.Lookup(resType); !ok {
+		return errors.Errorf("txid [%s]: unknown CRDT resolve type [%s]", s.txid, resType)
+	}
+	schema, err := s.txmgr.CRDTSchema(ns)
+	if err != nil {
+		return err
+	}
+	if err := crdt_resolver.CheckSchema(schema, strings.TrimPrefix(key, statedb.CRDTPrefix), resType); err != nil {
+		return errors.WithMessagef(err, "txid [%s]: key [%s] in collection [%s:%s] violates the CRDT schema", s.txid, key, ns, coll)
+	}
+	if key, err = s.privateCRDTKey(ns, coll, key); err != nil {
+		return err
+	}
+	keyType, err := s.privateCRDTType(ns, coll, key)
+	if err != nil {
+		return err
+	}
+	if err := crdt_resolver.CheckType(key, keyType, resType); err != nil {
+		return errors.WithMessagef(err, "txid [%s]: invalid %s diff for key [%s] in collection [%s:%s]", s.txid, resType, key, ns, coll)
+	}
+	observed, err := s.GetPrivateCRDTState(ns, coll, key)
+	if err != nil {
+		return err
+	}
+	merged, err := s.txmgr.crdtResolvers.ResolveAt(observed, value, resType, pendingCRDTHeight)
+	if err != nil {
+		return errors.WithMessagef(err, "txid [%s]: invalid %s diff for key [%s] in collection [%s:%s]", s.txid, resType, key, ns, coll)
+	}
+	if err := s.checkWritePrecondition(key, merged); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddToPvtAndHashedCRDT(ns, coll, resType, key, value)
 	return nil
 }
 
@@ -93,6 +387,9 @@ func (s *txSimulator) SetStateMultipleKeys(namespace string, kvs map[string][]by
 
 // SetStateMetadata implements method in interface `ledger.TxSimulator`
 func (s *txSimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	if _, ok := metadata[statemetadata.CRDTType]; ok {
+		return errors.Errorf("txid [%s]: metadata entry [%s] is only set by the CRDT payloads", s.txid, statemetadata.CRDTType)
+	}
 	if err := s.checkWritePrecondition(key, nil); err != nil {
 		return err
 	}
@@ -159,6 +456,9 @@ func (s *txSimulator) SetPrivateDataMetadata(namespace, collection, key string,
 	if err := s.queryExecutor.validateCollName(namespace, collection); err != nil {
 		return err
 	}
+	if _, ok := metadata[statemetadata.CRDTType]; ok {
+		return errors.Errorf("txid [%s]: metadata entry [%s] is only set by the CRDT payloads", s.txid, statemetadata.CRDTType)
+	}
 	if err := s.checkWritePrecondition(key, nil); err != nil {
 		return err
 	}
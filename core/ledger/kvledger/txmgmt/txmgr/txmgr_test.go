@@ -17,14 +17,19 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+	"github.com/hyperledger/fabric/core/ledger/mock"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/stretchr/testify/require"
@@ -183,6 +188,612 @@ func testTxSimulatorWithExistingData(t *testing.T, env testEnv) {
 	require.Equal(t, version.NewHeight(1, 0), vv.Version)
 }
 
+func TestTxSimulatorCRDTReadYourOwnWrites(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testtxsimulatorcrdtreadyourownwrites", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	key := statedb.CRDTPrefix + "key1"
+
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	require.NoError(t, s1.SetCRDT("ns1", "IntAdd", key, []byte("5"), nil))
+	value, err := s1.GetCRDTState("ns1", key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("5"), value)
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	// the pending diffs are merged into the committed value in the order they were added
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", key, []byte("3"), nil))
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", key, []byte("-1"), nil))
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", statedb.CRDTPrefix+"key2", []byte("10"), nil))
+	require.NoError(t, s2.SetCRDT("ns2", "IntAdd", key, []byte("20"), nil))
+	value, err = s2.GetCRDTState("ns1", key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("7"), value)
+
+	// the resolvers that need the height of the transaction can be read as well
+	lwwKey := statedb.CRDTPrefix + "key3"
+	require.NoError(t, s2.SetCRDT("ns1", "LWWRegister", lwwKey, []byte(`{"value":"alice","timestamp":1}`), nil))
+	value, err = s2.GetCRDTState("ns1", lwwKey)
+	require.NoError(t, err)
+	require.Contains(t, string(value), `"value":"alice"`)
+
+	s2.Done()
+
+	// the simulation does not change the committed value
+	s3, _ := txMgr.NewTxSimulator("test_tx3")
+	value, err = s3.GetCRDTState("ns1", key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("5"), value)
+	s3.Done()
+}
+
+func TestCRDTValuesRecordedAtCommit(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testcrdtvaluesrecordedatcommit", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	bg, _ := testutil.NewBlockGenerator(t, "testLedger", false)
+	key := statedb.CRDTPrefix + "key1"
+
+	prepareBlock := func(diff string) *common.Block {
+		s, _ := txMgr.NewTxSimulator("test_tx")
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte(diff), nil))
+		s.Done()
+		txRWSet, _ := s.GetTxSimulationResults()
+		rwSetBytes, _ := proto.Marshal(txRWSet.PubSimulationResults)
+		block := bg.NextBlock([][]byte{rwSetBytes})
+		_, _, _, err := txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block}, true)
+		require.NoError(t, err)
+		return block
+	}
+
+	// the values of a prepared block are only recorded once the block is committed
+	block := prepareBlock("5")
+	values, err := txMgr.GetCRDTValuesByNum(block.Header.Number)
+	require.NoError(t, err)
+	require.Empty(t, values)
+	require.NoError(t, txMgr.Commit())
+	values, err = txMgr.GetCRDTValuesByNum(block.Header.Number)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, []byte("5"), values[0].Values[0].Value)
+
+	// the values of a block rolled back are never recorded
+	block = prepareBlock("3")
+	txMgr.Rollback()
+	values, err = txMgr.GetCRDTValuesByNum(block.Header.Number)
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	// the transactions of a block merging into the same key record the value they produced
+	var txs [][]byte
+	for i, diff := range []string{"1", "2"} {
+		s, _ := txMgr.NewTxSimulator(fmt.Sprintf("test_tx%d", i))
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte(diff), nil))
+		s.Done()
+		txRWSet, _ := s.GetTxSimulationResults()
+		rwSetBytes, _ := proto.Marshal(txRWSet.PubSimulationResults)
+		txs = append(txs, rwSetBytes)
+	}
+	block = bg.NextBlock(txs)
+	_, _, _, err = txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block}, true)
+	require.NoError(t, err)
+	require.NoError(t, txMgr.Commit())
+	values, err = txMgr.GetCRDTValuesByNum(block.Header.Number)
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	require.Equal(t, []byte("6"), values[0].Values[0].Value)
+	require.Equal(t, []byte("8"), values[1].Values[0].Value)
+}
+
+func TestCRDTSchemaErrorAbortsCommit(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testcrdtschemaerrorabortscommit", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	bg, _ := testutil.NewBlockGenerator(t, "testLedger", false)
+
+	s, _ := txMgr.NewTxSimulator("test_tx")
+	require.NoError(t, s.SetCRDT("ns1", "IntAdd", "key1", []byte("5"), nil))
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	rwSetBytes, _ := proto.Marshal(txRWSet.PubSimulationResults)
+
+	// the schema can't be read, which does not tell whether the transaction is valid
+	ccInfoProvider := &mock.DeployedChaincodeInfoProvider{}
+	ccInfoProvider.ChaincodeInfoReturns(nil, fmt.Errorf("chaincode definition unavailable"))
+	txMgr.ccInfoProvider = ccInfoProvider
+	_, _, _, err := txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: bg.NextBlock([][]byte{rwSetBytes})}, true)
+	require.EqualError(t, err, "could not get the CRDT schema of namespace ns1: chaincode definition unavailable")
+}
+
+func TestTxSimulatorResetAndDeleteCRDT(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testtxsimulatorresetanddeletecrdt", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	key := "key1"
+
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	require.NoError(t, s1.SetCRDT("ns1", "IntAdd", key, []byte("5"), nil))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	require.EqualError(t, s2.ResetCRDT("ns1", "IntAd", key, []byte("1")), "txid [test_tx2]: unknown CRDT resolve type [IntAd]")
+	require.EqualError(t, s2.ResetCRDT("ns1", "IntAdd", key, []byte("one")),
+		`txid [test_tx2]: invalid IntAdd value for key [key1] in namespace [ns1]: strconv.Atoi: parsing "one": invalid syntax`)
+
+	// the pending resets and deletes are applied in the order they were added
+	readValue := func(s ledger.TxSimulator) []byte {
+		value, err := s.GetCRDTState("ns1", key)
+		require.NoError(t, err)
+		return value
+	}
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", key, []byte("3"), nil))
+	require.Equal(t, []byte("8"), readValue(s2))
+	require.NoError(t, s2.ResetCRDT("ns1", "IntAdd", key, []byte("2")))
+	require.Equal(t, []byte("2"), readValue(s2))
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", key, []byte("1"), nil))
+	require.Equal(t, []byte("3"), readValue(s2))
+	require.NoError(t, s2.DeleteCRDT("ns1", key))
+	require.Nil(t, readValue(s2))
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", key, []byte("4"), nil))
+	require.Equal(t, []byte("4"), readValue(s2))
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet2.PubSimulationResults)
+
+	s3, _ := txMgr.NewTxSimulator("test_tx3")
+	require.Equal(t, []byte("4"), readValue(s3))
+	require.NoError(t, s3.DeleteCRDT("ns1", key))
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3.PubSimulationResults)
+
+	// the deleted key is removed from the state db, hence from the range queries
+	qe, _ := txMgr.NewQueryExecutor("test_tx4")
+	defer qe.Done()
+	value, err := qe.GetCRDTState("ns1", key)
+	require.NoError(t, err)
+	require.Nil(t, value)
+	itr, err := qe.GetCRDTStateRangeScanIterator("ns1", "", "")
+	require.NoError(t, err)
+	defer itr.Close()
+	result, err := itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func TestTxSimulatorCRDTRangeQuery(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testtxsimulatorcrdtrangequery", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	compositeKey := "\x00obj\x00a\x00"
+	legacyKey := statedb.CRDTPrefix + "key4"
+
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	require.NoError(t, s1.SetState("ns1", "A", []byte("a")))
+	require.NoError(t, s1.SetState("ns1", "key0", []byte("0")))
+	require.NoError(t, s1.SetState("ns1", "z", []byte("z")))
+	require.NoError(t, s1.SetState("ns1", legacyKey, []byte("8")))
+	require.NoError(t, s1.SetCRDT("ns1", "IntAdd", "key1", []byte("1"), nil))
+	require.NoError(t, s1.SetCRDT("ns1", "IntAdd", "key2", []byte("2"), nil))
+	require.NoError(t, s1.SetCRDT("ns1", "IntAdd", compositeKey, []byte("3"), nil))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", "key2", []byte("5"), nil))
+	require.NoError(t, s2.SetCRDT("ns1", "IntAdd", "key3", []byte("4"), nil))
+	collect := func(itr commonledger.ResultsIterator, err error) map[string]string {
+		require.NoError(t, err)
+		defer itr.Close()
+		kvs := map[string]string{}
+		for {
+			res, err := itr.Next()
+			require.NoError(t, err)
+			if res == nil {
+				return kvs
+			}
+			kv := res.(*queryresult.KV)
+			kvs[kv.Key] = string(kv.Value)
+		}
+	}
+
+	// only the committed CRDT keys are returned, with the pending payloads merged into their values,
+	// the keys still named with the legacy prefix being returned as well
+	require.Equal(t,
+		map[string]string{
+			"key1":       "1",
+			"key2":       "7",
+			compositeKey: "3",
+			legacyKey:    "8",
+		},
+		collect(s2.GetCRDTStateRangeScanIterator("ns1", "", "")),
+	)
+	require.Equal(t,
+		map[string]string{"key2": "7"},
+		collect(s2.GetCRDTStateRangeScanIterator("ns1", "key2", "z")),
+	)
+	require.Empty(t, collect(s2.GetCRDTStateRangeScanIterator("ns1", "A", "B")))
+
+	// rich queries are not supported by leveldb
+	_, err := s2.ExecuteCRDTQuery("ns1", `{"selector":{"owner":"alice"}}`)
+	require.EqualError(t, err, "ExecuteQuery not supported for leveldb")
+	s2.Done()
+
+	// no read nor range query info is recorded
+	txRWSet2, err := s2.GetTxSimulationResults()
+	require.NoError(t, err)
+	kvRWSet := &kvrwset.KVRWSet{}
+	require.NoError(t, proto.Unmarshal(txRWSet2.PubSimulationResults.NsRwset[0].Rwset, kvRWSet))
+	require.Empty(t, kvRWSet.Reads)
+	require.Empty(t, kvRWSet.RangeQueriesInfo)
+	require.Len(t, kvRWSet.CrdtPayload, 2)
+}
+
+func TestTxSimulatorSetCRDTValidation(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testtxsimulatorsetcrdtvalidation", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	key := "key1"
+
+	// an ordinary key is turned into a CRDT key by its first CRDT payload
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	require.NoError(t, s1.SetState("ns1", key, []byte("10")))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	err := s2.SetCRDT("ns1", "IntAd", key, []byte("1"), nil)
+	require.EqualError(t, err, "txid [test_tx2]: unknown CRDT resolve type [IntAd]")
+
+	err = s2.SetCRDT("ns1", "UintSub", key, []byte("one"), nil)
+	require.EqualError(t, err, `txid [test_tx2]: invalid UintSub diff for key [key1] in namespace [ns1]: strconv.Atoi: parsing "one": invalid syntax`)
+
+	// the diffs are checked against the committed value and the earlier writes of the transaction
+	require.NoError(t, s2.SetCRDT("ns1", "UintSub", key, []byte("6"), nil))
+	err = s2.SetCRDT("ns1", "UintSub", key, []byte("6"), nil)
+	require.EqualError(t, err, "txid [test_tx2]: invalid UintSub diff for key [key1] in namespace [ns1]: Negative result")
+
+	// the key now holds a UintSub value, neither a payload of an incompatible type nor a metadata write can change it
+	err = s2.SetCRDT("ns1", "StringConcat", key, []byte("1"), nil)
+	require.EqualError(t, err, "txid [test_tx2]: invalid StringConcat diff for key [key1] in namespace [ns1]: CRDT key key1 holds a UintSub value, can't merge a StringConcat payload")
+	err = s2.SetStateMetadata("ns1", key, map[string][]byte{statemetadata.CRDTType: []byte("IntAdd")})
+	require.EqualError(t, err, "txid [test_tx2]: metadata entry [CRDT_TYPE] is only set by the CRDT payloads")
+
+	// the predicates are checked against the value read by the transaction
+	atLeast := func(amount string) []*kvrwset.CRDTPredicate {
+		return []*kvrwset.CRDTPredicate{{Operator: kvrwset.CRDTPredicate_GREATER_OR_EQUAL, Operand: []byte(amount)}}
+	}
+	err = s2.SetCRDT("ns1", "UintSub", key, []byte("1"), atLeast("5"))
+	require.EqualError(t, err, "txid [test_tx2]: predicate on key [key1] in namespace [ns1] does not hold: Predicate GREATER_OR_EQUAL failed: current value 4 is less than 5")
+
+	// the rejected diffs are not recorded
+	s2.Done()
+	txRWSet2, err := s2.GetTxSimulationResults()
+	require.NoError(t, err)
+	payloads := txRWSet2.PubSimulationResults.NsRwset[0]
+	kvRWSet := &kvrwset.KVRWSet{}
+	require.NoError(t, proto.Unmarshal(payloads.Rwset, kvRWSet))
+	require.Len(t, kvRWSet.CrdtPayload, 1)
+	require.Equal(t, []byte("6"), kvRWSet.CrdtPayload[0].Data)
+}
+
+func TestTxSimulatorPrivateCRDT(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testtxsimulatorprivatecrdt", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	populateCollConfigForTest(t, txMgr, []collConfigkey{{"ns1", "coll1"}}, version.NewHeight(1, 1))
+	bg, _ := testutil.NewBlockGenerator(t, "testLedger", false)
+	key := statedb.CRDTPrefix + "key1"
+
+	// commitBlock commits a block with a transaction per simulation, the private data
+	// being available for the transactions whose index is in withPvtData. It returns the
+	// private data of every transaction
+	commitBlock := func(simulations []func(s ledger.TxSimulator), withPvtData ...uint64) []*ledger.TxPvtData {
+		var txs [][]byte
+		var allPvtData []*ledger.TxPvtData
+		pvtData := map[uint64]*ledger.TxPvtData{}
+		for i, simulate := range simulations {
+			s, _ := txMgr.NewTxSimulator(fmt.Sprintf("test_tx%d", i))
+			simulate(s)
+			s.Done()
+			simRes, err := s.GetTxSimulationResults()
+			require.NoError(t, err)
+			pubBytes, err := proto.Marshal(simRes.PubSimulationResults)
+			require.NoError(t, err)
+			txs = append(txs, pubBytes)
+			txPvtData := &ledger.TxPvtData{SeqInBlock: uint64(i), WriteSet: simRes.PvtSimulationResults}
+			allPvtData = append(allPvtData, txPvtData)
+			for _, txNum := range withPvtData {
+				if txNum == uint64(i) {
+					pvtData[txNum] = txPvtData
+				}
+			}
+		}
+		block := bg.NextBlock(txs)
+		_, _, _, err := txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block, PvtData: pvtData}, true)
+		require.NoError(t, err)
+		require.NoError(t, txMgr.Commit())
+		return allPvtData
+	}
+	put := func(resType string, diffs ...string) func(s ledger.TxSimulator) {
+		return func(s ledger.TxSimulator) {
+			for _, diff := range diffs {
+				require.NoError(t, s.SetPrivateCRDT("ns1", "coll1", resType, key, []byte(diff)))
+			}
+		}
+	}
+	checkValue := func(expectedValue string) {
+		qe, _ := txMgr.NewQueryExecutor("test_query")
+		defer qe.Done()
+		value, err := qe.GetPrivateCRDTState("ns1", "coll1", key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(expectedValue), value)
+	}
+	// every peer computes the same hash, whether it holds the private data or not
+	expectedHash := []byte(nil)
+	checkHash := func(expectedVersion *version.Height, diffs ...string) {
+		for _, diff := range diffs {
+			expectedHash = util.ComputeHash(append(append([]byte{}, expectedHash...), util.ComputeHash([]byte(diff))...))
+		}
+		vv, err := testEnv.getVDB().GetPrivateDataHash("ns1", "coll1", key)
+		require.NoError(t, err)
+		require.Equal(t, expectedHash, vv.Value)
+		require.Equal(t, expectedVersion, vv.Version)
+	}
+	checkStale := func(hashVersion, pvtVersion *version.Height) {
+		qe, _ := txMgr.NewQueryExecutor("test_query")
+		defer qe.Done()
+		_, err := qe.GetPrivateCRDTState("ns1", "coll1", key)
+		require.EqualError(t, err, fmt.Sprintf("private data matching public hash version is not available. Public hash version = %s, Private data version = %s",
+			hashVersion, pvtVersion))
+	}
+
+	// the pending diffs of the transaction are merged into the value it reads
+	s, _ := txMgr.NewTxSimulator("test_tx")
+	require.NoError(t, s.SetPrivateCRDT("ns1", "coll1", "BigIntAdd", key, []byte("5")))
+	value, err := s.GetPrivateCRDTState("ns1", "coll1", key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("5"), value)
+	err = s.SetPrivateCRDT("ns1", "coll1", "BigIntAdd", key, []byte("five"))
+	require.EqualError(t, err, `txid [test_tx]: invalid BigIntAdd diff for key [CRDTFIELD_key1] in collection [ns1:coll1]: Invalid number "five"`)
+	s.Done()
+
+	commitBlock([]func(s ledger.TxSimulator){put("BigIntAdd", "5")}, 0)
+	checkValue("5")
+	checkHash(version.NewHeight(1, 0), "5")
+
+	// the key took the type of its first payload, recorded in the metadata of its hash
+	s, _ = txMgr.NewTxSimulator("test_tx")
+	err = s.SetPrivateCRDT("ns1", "coll1", "GSet", key, []byte(`{"add":["a"]}`))
+	require.EqualError(t, err, "txid [test_tx]: invalid GSet diff for key [CRDTFIELD_key1] in collection [ns1:coll1]: CRDT key CRDTFIELD_key1 holds a BigIntAdd value, can't merge a GSet payload")
+	s.Done()
+
+	// the payloads of a transaction are merged in order
+	commitBlock([]func(s ledger.TxSimulator){put("BigIntAdd", "3", "-1")}, 0)
+	checkValue("7")
+	checkHash(version.NewHeight(2, 0), "3", "-1")
+
+	// a payload that can't be merged at commit time leaves the key needing repair
+	bounded := `{"value":"2","max":"10"}`
+	commitBlock([]func(s ledger.TxSimulator){put("BigIntAdd", bounded), put("BigIntAdd", bounded)}, 0, 1)
+	checkHash(version.NewHeight(3, 1), bounded, bounded)
+	checkStale(version.NewHeight(3, 1), version.NewHeight(3, 0))
+
+	// a write repairs the key
+	commitBlock([]func(s ledger.TxSimulator){func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetPrivateData("ns1", "coll1", key, []byte("7")))
+	}}, 0)
+	checkValue("7")
+	expectedHash = util.ComputeHash([]byte("7"))
+	checkHash(version.NewHeight(4, 0))
+
+	// without the private data the value can't be merged and becomes stale
+	pvtData := commitBlock([]func(s ledger.TxSimulator){put("BigIntAdd", "10")})
+	checkHash(version.NewHeight(5, 0), "10")
+	s, _ = txMgr.NewTxSimulator("test_tx")
+	err = s.SetPrivateCRDT("ns1", "coll1", "BigIntAdd", key, []byte("1"))
+	require.EqualError(t, err, "private data matching public hash version is not available. Public hash version = {BlockNum: 5, TxNum: 0}, Private data version = {BlockNum: 4, TxNum: 0}")
+	s.Done()
+
+	// the reconciled private data is replayed on the stale value
+	require.NoError(t, txMgr.RemoveStaleAndCommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{5: pvtData}))
+	checkValue("17")
+	pvtVV, err := testEnv.getVDB().GetPrivateData("ns1", "coll1", key)
+	require.NoError(t, err)
+	replayedHash, err := statemetadata.GetCRDTHash(pvtVV.Metadata)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, replayedHash)
+
+	// the private data the peer holds for the later transactions is replayed along with the reconciled one
+	pvtData = commitBlock([]func(s ledger.TxSimulator){put("BigIntAdd", "1"), put("BigIntAdd", "2")}, 1)
+	checkHash(version.NewHeight(6, 1), "1", "2")
+	checkStale(version.NewHeight(6, 1), version.NewHeight(5, 0))
+	txMgr.pvtdataOfBlock = func(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
+		if blockNum == 6 {
+			return pvtData[1:], nil
+		}
+		return nil, nil
+	}
+	require.NoError(t, txMgr.RemoveStaleAndCommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{6: pvtData[:1]}))
+	checkValue("20")
+
+	// a key that still misses some private data is left stale
+	missingPvtData := commitBlock([]func(s ledger.TxSimulator){put("BigIntAdd", "3"), put("BigIntAdd", "4")})
+	require.NoError(t, txMgr.RemoveStaleAndCommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{7: missingPvtData[1:]}))
+	checkHash(version.NewHeight(7, 1), "3", "4")
+	checkStale(version.NewHeight(7, 1), version.NewHeight(6, 1))
+}
+
+func TestCRDTKeyMetadata(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testcrdtkeymetadata", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	populateCollConfigForTest(t, txMgr, []collConfigkey{{"ns1", "coll1"}}, version.NewHeight(1, 1))
+	bg, _ := testutil.NewBlockGenerator(t, "testLedger", false)
+	key := statedb.CRDTPrefix + "key1"
+	metadata := map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")}
+
+	commit := func(simulate func(s ledger.TxSimulator)) {
+		s, _ := txMgr.NewTxSimulator("test_tx")
+		simulate(s)
+		s.Done()
+		simRes, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubBytes, err := proto.Marshal(simRes.PubSimulationResults)
+		require.NoError(t, err)
+		block := bg.NextBlock([][]byte{pubBytes})
+		pvtData := map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}}
+		_, _, _, err = txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block, PvtData: pvtData}, true)
+		require.NoError(t, err)
+		require.NoError(t, txMgr.Commit())
+	}
+	// the CRDT type of the keys is recorded alongside the metadata set by the transactions
+	checkResults := func(expectedVal, expectedPvtVal string, metadata map[string][]byte) {
+		qe, _ := txMgr.NewQueryExecutor("test_query")
+		defer qe.Done()
+		expectedMetadata := map[string][]byte{statemetadata.CRDTType: []byte("IntAdd")}
+		for name, entry := range metadata {
+			expectedMetadata[name] = entry
+		}
+		checkTestQueryResults(t, qe, "ns1", key, []byte(expectedVal), expectedMetadata)
+		checkPvtdataTestQueryResults(t, qe, "ns1", "coll1", key, []byte(expectedPvtVal), expectedMetadata)
+
+		// the private value records the hash of its hashed key in a metadata entry of its own
+		pvtVV, err := testEnv.getVDB().GetPrivateData("ns1", "coll1", key)
+		require.NoError(t, err)
+		hashVV, err := testEnv.getVDB().GetPrivateDataHash("ns1", "coll1", key)
+		require.NoError(t, err)
+		pvtMetadata, err := statemetadata.Deserialize(pvtVV.Metadata)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]byte{statemetadata.CRDTHash: hashVV.Value}, pvtMetadata)
+	}
+
+	// the metadata can be set in the transaction that creates the keys
+	commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("5"), nil))
+		require.NoError(t, s.SetStateMetadata("ns1", key, metadata))
+		require.NoError(t, s.SetPrivateCRDT("ns1", "coll1", "IntAdd", key, []byte("50")))
+		require.NoError(t, s.SetPrivateDataMetadata("ns1", "coll1", key, metadata))
+	})
+	checkResults("5", "50", metadata)
+
+	// the merges keep the metadata
+	commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("1"), nil))
+		require.NoError(t, s.SetPrivateCRDT("ns1", "coll1", "IntAdd", key, []byte("10")))
+	})
+	checkResults("6", "60", metadata)
+
+	// a metadata-only update keeps the merged values
+	updatedMetadata := map[string][]byte{"VALIDATION_PARAMETER": []byte("updated-policy")}
+	commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetStateMetadata("ns1", key, updatedMetadata))
+		require.NoError(t, s.SetPrivateDataMetadata("ns1", "coll1", key, updatedMetadata))
+	})
+	checkResults("6", "60", updatedMetadata)
+
+	commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("1"), nil))
+		require.NoError(t, s.SetPrivateCRDT("ns1", "coll1", "IntAdd", key, []byte("10")))
+	})
+	checkResults("7", "70", updatedMetadata)
+}
+
+func TestCRDTLegacyKeyFallback(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testcrdtlegacykeyfallback", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	populateCollConfigForTest(t, txMgr, []collConfigkey{{"ns1", "coll1"}}, version.NewHeight(1, 1))
+	bg, _ := testutil.NewBlockGenerator(t, "testLedger", false)
+	key, legacyKey := "counter", statedb.CRDTPrefix+"counter"
+
+	commit := func(simulate func(s ledger.TxSimulator)) *rwsetutil.TxRwSet {
+		s, _ := txMgr.NewTxSimulator("test_tx")
+		simulate(s)
+		s.Done()
+		simRes, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubBytes, err := proto.Marshal(simRes.PubSimulationResults)
+		require.NoError(t, err)
+		block := bg.NextBlock([][]byte{pubBytes})
+		pvtData := map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}}
+		_, _, _, err = txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block, PvtData: pvtData}, true)
+		require.NoError(t, err)
+		require.NoError(t, txMgr.Commit())
+		txRWSet := &rwsetutil.TxRwSet{}
+		require.NoError(t, txRWSet.FromProtoBytes(pubBytes))
+		return txRWSet
+	}
+	checkValues := func(key, expectedVal, expectedPvtVal string) {
+		s, _ := txMgr.NewTxSimulator("test_query")
+		defer s.Done()
+		value, err := s.GetCRDTState("ns1", key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(expectedVal), value)
+		value, err = s.GetPrivateCRDTState("ns1", "coll1", key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(expectedPvtVal), value)
+	}
+
+	// the keys as the earlier versions of the shim left them, i.e. prefixed and without a CRDT type
+	commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetState("ns1", legacyKey, []byte("5")))
+		require.NoError(t, s.SetPrivateData("ns1", "coll1", legacyKey, []byte("50")))
+	})
+	checkValues(key, "5", "50")
+
+	// the diffs addressed to the key are merged into the prefixed key
+	txRWSet := commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("1"), nil))
+		require.NoError(t, s.SetPrivateCRDT("ns1", "coll1", "IntAdd", key, []byte("10")))
+	})
+	require.Equal(t, legacyKey, txRWSet.NsRwSets[0].KvRwSet.CrdtPayload[0].Key)
+	checkValues(key, "6", "60")
+	checkValues(legacyKey, "6", "60")
+
+	// a reset gives the key a CRDT type, which moves the chaincode to the key itself
+	commit(func(s ledger.TxSimulator) {
+		value, err := s.GetCRDTState("ns1", key)
+		require.NoError(t, err)
+		require.NoError(t, s.ResetCRDT("ns1", "IntAdd", key, value))
+		require.NoError(t, s.DeleteCRDT("ns1", legacyKey))
+		value, err = s.GetCRDTState("ns1", key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("6"), value)
+	})
+	commit(func(s ledger.TxSimulator) {
+		require.NoError(t, s.SetCRDT("ns1", "IntAdd", key, []byte("1"), nil))
+	})
+	s, _ := txMgr.NewTxSimulator("test_query")
+	defer s.Done()
+	value, err := s.GetCRDTState("ns1", key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("7"), value)
+	value, err = s.GetCRDTState("ns1", legacyKey)
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
 func TestTxValidation(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -8,16 +8,21 @@ package validation
 
 import (
 	"bytes"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	"github.com/hyperledger/fabric/protoutil"
@@ -31,6 +36,13 @@ type PostOrderSimulatorProvider interface {
 	NewTxSimulator(txid string) (ledger.TxSimulator, error)
 }
 
+// CRDTSchemaProvider provides the CRDT schema of the chaincode definition committed for a namespace.
+// A nil schema is returned for the namespaces that do not declare one. An error aborts the commit of
+// the block, only a payload the schema does not allow invalidates its transaction
+type CRDTSchemaProvider interface {
+	CRDTSchema(ns string) (*lifecycle.CRDTSchema, error)
+}
+
 // CommitBatchPreparer performs validation and prepares the final batch that is to be committed to the statedb
 type CommitBatchPreparer struct {
 	postOrderSimulatorProvider PostOrderSimulatorProvider
@@ -47,6 +59,22 @@ type TxStatInfo struct {
 	ChaincodeID           *peer.ChaincodeID
 	ChaincodeEventData    []byte
 	NumCollections        int
+	// CRDTValues holds the values the public CRDT keys merged by a valid transaction have right after the transaction
+	CRDTValues []*peer.CRDTValue
+	// CRDTMerges holds the merges of the public CRDT payloads of a valid transaction or, if the transaction
+	// was invalidated because a payload failed to merge, the failed merge
+	CRDTMerges []*CRDTMergeStat
+}
+
+// CRDTMergeStat describes the merge of a public CRDT payload of a transaction
+type CRDTMergeStat struct {
+	Namespace      string
+	Key            string
+	ResolutionType string
+	// Failure is the validation code of the transaction if the payload failed to merge, empty otherwise
+	Failure string
+	// MergeTime is the time taken to merge the payload into the value of the key
+	MergeTime time.Duration
 }
 
 // NewCommitBatchPreparer constructs a validator that internally manages statebased validator and in addition
@@ -56,13 +84,19 @@ func NewCommitBatchPreparer(
 	db *privacyenabledstate.DB,
 	customTxProcessors map[common.HeaderType]ledger.CustomTxProcessor,
 	hashFunc rwsetutil.HashFunc,
+	crdtResolvers *crdt_resolver.Registry,
+	crdtSchemaProvider CRDTSchemaProvider,
+	crdtMergeWorkers int,
 ) *CommitBatchPreparer {
 	return &CommitBatchPreparer{
 		postOrderSimulatorProvider,
 		db,
 		&validator{
-			db:       db,
-			hashFunc: hashFunc,
+			db:                 db,
+			hashFunc:           hashFunc,
+			crdtResolvers:      crdtResolvers,
+			crdtSchemaProvider: crdtSchemaProvider,
+			crdtMergeWorkers:   crdtMergeWorkers,
 		},
 		customTxProcessors,
 	}
@@ -100,6 +134,7 @@ func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.Bl
 		p.db,
 		pubAndHashUpdates,
 		blockAndPvtdata.PvtData,
+		p.validator.crdtResolvers,
 	); err != nil {
 		return nil, nil, nil, err
 	}
@@ -111,6 +146,10 @@ func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.Bl
 	for i := range txsFilter {
 		txsStatInfo[i].ValidationCode = txsFilter.Flag(i)
 	}
+	for _, tx := range internalBlock.txs {
+		txsStatInfo[tx.indexInBlock].CRDTValues = tx.crdtValues
+		txsStatInfo[tx.indexInBlock].CRDTMerges = tx.crdtMerges
+	}
 	return &privacyenabledstate.UpdateBatch{
 		PubUpdates:  pubAndHashUpdates.publicUpdates,
 		HashUpdates: pubAndHashUpdates.hashUpdates,
@@ -118,21 +157,58 @@ func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.Bl
 	}, purgeUpdates, txsStatInfo, nil
 }
 
+// crdtValues returns the values the public CRDT keys merged by a transaction have in the update batch of the block,
+// i.e. the values of the keys right after the transaction while the batch is prepared
+func crdtValues(txRWSet *rwsetutil.TxRwSet, updates *privacyenabledstate.PubUpdateBatch) []*peer.CRDTValue {
+	var values []*peer.CRDTValue
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		seen := map[string]struct{}{}
+		for _, payload := range nsRWSet.KvRwSet.CrdtPayload {
+			if _, ok := seen[payload.Key]; ok {
+				continue
+			}
+			seen[payload.Key] = struct{}{}
+			vv := updates.Get(ns, payload.Key)
+			if vv == nil {
+				continue
+			}
+			values = append(values, &peer.CRDTValue{
+				Namespace: ns,
+				Key:       payload.Key,
+				Value:     vv.Value,
+				IsDelete:  vv.Value == nil,
+			})
+		}
+	}
+	return values
+}
+
 // validateAndPreparePvtBatch pulls out the private write-set for the transactions that are marked as valid
 // by the internal public data validator. Finally, it validates (if not already self-endorsed) the pvt rwset against the
-// corresponding hash present in the public rwset
+// corresponding hash present in the public rwset. The CRDT payloads of the private rwset are merged into
+// the private data by the peers that hold it, see pvtCRDTMerger
 func validateAndPreparePvtBatch(
 	blk *block,
 	db *privacyenabledstate.DB,
 	pubAndHashUpdates *publicAndHashUpdates,
 	pvtdata map[uint64]*ledger.TxPvtData,
+	crdtResolvers *crdt_resolver.Registry,
 ) (*privacyenabledstate.PvtUpdateBatch, error) {
 	pvtUpdates := privacyenabledstate.NewPvtUpdateBatch()
 	metadataUpdates := metadataUpdates{}
+	crdtMerger := newPvtCRDTMerger(db, pubAndHashUpdates, crdtResolvers)
 	for _, tx := range blk.txs {
 		if tx.validationCode != peer.TxValidationCode_VALID {
 			continue
 		}
+		txHeight := version.NewHeight(blk.num, uint64(tx.indexInBlock))
+		// the hashed keys are tracked even if the private data of the transaction is missing,
+		// so that the later payloads of the keys are not merged into a stale value
+		hashedVersions, err := crdtMerger.trackHashedWrites(tx.rwset, txHeight)
+		if err != nil {
+			return nil, err
+		}
 		if !tx.containsPvtWrites() {
 			continue
 		}
@@ -146,11 +222,14 @@ func validateAndPreparePvtBatch(
 			}
 		}
 		var pvtRWSet *rwsetutil.TxPvtRwSet
-		var err error
 		if pvtRWSet, err = rwsetutil.TxPvtRwSetFromProtoMsg(txPvtdata.WriteSet); err != nil {
 			return nil, err
 		}
-		addPvtRWSetToPvtUpdateBatch(pvtRWSet, pvtUpdates, version.NewHeight(blk.num, uint64(tx.indexInBlock)))
+		// as for the public data, the writes of the transaction override its CRDT payloads
+		if err := crdtMerger.merge(pvtRWSet, pvtUpdates, hashedVersions, txHeight); err != nil {
+			return nil, err
+		}
+		addPvtRWSetToPvtUpdateBatch(pvtRWSet, pvtUpdates, txHeight)
 		addEntriesToMetadataUpdates(metadataUpdates, pvtRWSet)
 	}
 	if err := incrementPvtdataVersionIfNeeded(metadataUpdates, pvtUpdates, pubAndHashUpdates, db); err != nil {
@@ -279,12 +358,27 @@ func preprocessProtoBlock(postOrderSimulatorProvider PostOrderSimulatorProvider,
 				id:                      chdr.TxId,
 				rwset:                   txRWSet,
 				containsPostOrderWrites: containsPostOrderWrites,
+				creatorMSPID:            creatorMSPID(payload),
 			})
 		}
 	}
 	return b, txsStatInfo, nil
 }
 
+// creatorMSPID returns the MSP ID of the creator of the transaction, an empty string if it can't be
+// found, in which case the diffs of the counters merged by the transaction are not attributed to it
+func creatorMSPID(payload *common.Payload) string {
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(payload.GetHeader().GetSignatureHeader())
+	if err != nil {
+		return ""
+	}
+	creator, err := protoutil.UnmarshalSerializedIdentity(signatureHeader.Creator)
+	if err != nil {
+		return ""
+	}
+	return creator.Mspid
+}
+
 func processNonEndorserTx(
 	txEnv *common.Envelope,
 	txid string,
@@ -393,6 +487,215 @@ func incrementPvtdataVersionIfNeeded(
 	return nil
 }
 
+// pvtCRDTMerger merges the CRDT payloads of the private rwsets into the private data. A payload is only
+// merged into a private value at the version the hashed key had before the transaction; otherwise the peer
+// missed some private data and the value is left stale. A payload that fails to merge leaves the key in
+// need of repair: it keeps its previous version, so that it is not readable, until a transaction writes it.
+// The merged value records the chained hash of the key, see statemetadata.CRDTHash, so that the missed
+// payloads can be replayed once reconciled
+type pvtCRDTMerger struct {
+	db *privacyenabledstate.DB
+	// updates holds the hashed updates of the block, whose metadata tells the CRDT keys apart
+	updates   *publicAndHashUpdates
+	resolvers *crdt_resolver.Registry
+	// hashedVersions holds the versions of the hashed keys written by the preceding transactions of the block
+	hashedVersions map[privacyenabledstate.HashedCompositeKey]*version.Height
+	// valueHashes holds the hashes of the hashed keys written by the transactions of the block, nil for the deleted keys
+	valueHashes map[privacyenabledstate.HashedCompositeKey][]byte
+}
+
+func newPvtCRDTMerger(db *privacyenabledstate.DB, updates *publicAndHashUpdates, resolvers *crdt_resolver.Registry) *pvtCRDTMerger {
+	return &pvtCRDTMerger{
+		db:             db,
+		updates:        updates,
+		resolvers:      resolvers,
+		hashedVersions: make(map[privacyenabledstate.HashedCompositeKey]*version.Height),
+		valueHashes:    make(map[privacyenabledstate.HashedCompositeKey][]byte),
+	}
+}
+
+// trackHashedWrites records the hashed keys written by a valid transaction at the height of the
+// transaction. It returns the versions the keys merged or updated with metadata by the transaction
+// had before it
+func (m *pvtCRDTMerger) trackHashedWrites(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height) (map[privacyenabledstate.HashedCompositeKey]*version.Height, error) {
+	prevVersions := make(map[privacyenabledstate.HashedCompositeKey]*version.Height)
+	trackPrevVersion := func(key privacyenabledstate.HashedCompositeKey) error {
+		if _, ok := prevVersions[key]; ok {
+			return nil
+		}
+		prevVersion, ok := m.hashedVersions[key]
+		if !ok {
+			var err error
+			if prevVersion, err = m.db.GetKeyHashVersion(key.Namespace, key.CollectionName, []byte(key.KeyHash)); err != nil {
+				return err
+			}
+		}
+		prevVersions[key] = prevVersion
+		return nil
+	}
+	for _, nsRwSet := range txRWSet.NsRwSets {
+		for _, collRwSet := range nsRwSet.CollHashedRwSets {
+			key := privacyenabledstate.HashedCompositeKey{
+				Namespace:      nsRwSet.NameSpace,
+				CollectionName: collRwSet.CollectionName,
+			}
+			for _, crdtHash := range collRwSet.HashedRwSet.CrdtPayloadHashes {
+				key.KeyHash = string(crdtHash.KeyHash)
+				if err := trackPrevVersion(key); err != nil {
+					return nil, err
+				}
+			}
+			for _, metadataWrite := range collRwSet.HashedRwSet.MetadataWrites {
+				key.KeyHash = string(metadataWrite.KeyHash)
+				if err := trackPrevVersion(key); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for _, nsRwSet := range txRWSet.NsRwSets {
+		for _, collRwSet := range nsRwSet.CollHashedRwSets {
+			key := privacyenabledstate.HashedCompositeKey{
+				Namespace:      nsRwSet.NameSpace,
+				CollectionName: collRwSet.CollectionName,
+			}
+			for _, crdtHash := range collRwSet.HashedRwSet.CrdtPayloadHashes {
+				key.KeyHash = string(crdtHash.KeyHash)
+				m.hashedVersions[key] = txHeight
+				prevHash, ok := m.valueHashes[key]
+				if !ok {
+					prevVV, err := m.db.GetCommittedValueHash(key.Namespace, key.CollectionName, crdtHash.KeyHash)
+					if err != nil {
+						return nil, err
+					}
+					if prevVV != nil {
+						prevHash = prevVV.Value
+					}
+				}
+				// as in applyPvtCRDTHashes
+				m.valueHashes[key] = util.ComputeHash(append(append([]byte{}, prevHash...), crdtHash.DataHash...))
+			}
+			for _, kvWriteHash := range collRwSet.HashedRwSet.HashedWrites {
+				key.KeyHash = string(kvWriteHash.KeyHash)
+				m.hashedVersions[key] = txHeight
+				m.valueHashes[key] = kvWriteHash.ValueHash
+			}
+			for _, metadataWrite := range collRwSet.HashedRwSet.MetadataWrites {
+				key.KeyHash = string(metadataWrite.KeyHash)
+				// the metadata update of a key that does not exist is dropped
+				if prevVersions[key] != nil || version.AreSame(m.hashedVersions[key], txHeight) {
+					m.hashedVersions[key] = txHeight
+				}
+			}
+		}
+	}
+	return prevVersions, nil
+}
+
+// merge merges the CRDT payloads of a private rwset into the private updates, at the height of the transaction
+func (m *pvtCRDTMerger) merge(pvtRWSet *rwsetutil.TxPvtRwSet, pvtUpdates *privacyenabledstate.PvtUpdateBatch,
+	prevVersions map[privacyenabledstate.HashedCompositeKey]*version.Height, txHeight *version.Height) error {
+	for _, nsPvtRwSet := range pvtRWSet.NsPvtRwSet {
+		for _, collPvtRwSet := range nsPvtRwSet.CollPvtRwSets {
+			ns, coll := nsPvtRwSet.NameSpace, collPvtRwSet.CollectionName
+			// the values of the keys once the payloads of the transaction are merged along with the metadata
+			// of the values, and the keys that are left unchanged, because their value is stale or needs repair
+			merged := map[string][]byte{}
+			metadata := map[string][]byte{}
+			unchanged := map[string]bool{}
+			for _, crdt := range collPvtRwSet.KvRwSet.CrdtPayload {
+				if unchanged[crdt.Key] {
+					continue
+				}
+				curValue, ok := merged[crdt.Key]
+				if !ok {
+					curVV, err := retrieveLatestVal(ns, coll, crdt.Key, pvtUpdates, m.db)
+					if err != nil {
+						return err
+					}
+					var curVersion *version.Height
+					if curVV != nil {
+						curValue, curVersion = curVV.Value, curVV.Version
+						metadata[crdt.Key] = curVV.Metadata
+					}
+					hashedKey := privacyenabledstate.HashedCompositeKey{
+						Namespace:      ns,
+						CollectionName: coll,
+						KeyHash:        string(util.ComputeStringHash(crdt.Key)),
+					}
+					if !version.AreSame(curVersion, prevVersions[hashedKey]) {
+						logger.Warningf("Private data of CRDT key [%s] in collection [%s:%s] is missing merges, version [%s] does not match hashed version [%s]. Not merging the payload of transaction at height [%s]",
+							crdt.Key, ns, coll, curVersion, prevVersions[hashedKey], txHeight)
+						unchanged[crdt.Key] = true
+						continue
+					}
+				}
+				mergedValue, err := m.resolvers.ResolveAt(curValue, crdt.Data, crdt.ResolutionType, txHeight)
+				if err != nil {
+					logger.Errorf("CRDT error <%s> while merging the private data of key [%s] in collection [%s:%s] at height [%s]. "+
+						"The key needs repair, it can't be read until a transaction writes it", err, crdt.Key, ns, coll, txHeight)
+					unchanged[crdt.Key] = true
+					delete(merged, crdt.Key)
+					continue
+				}
+				merged[crdt.Key] = mergedValue
+			}
+			for key, value := range merged {
+				valueHash := m.valueHashes[privacyenabledstate.HashedCompositeKey{
+					Namespace:      ns,
+					CollectionName: coll,
+					KeyHash:        string(util.ComputeStringHash(key)),
+				}]
+				valueMetadata, err := statemetadata.SetCRDTHash(metadata[key], valueHash)
+				if err != nil {
+					return err
+				}
+				pvtUpdates.PutValAndMetadata(ns, coll, key, value, valueMetadata, txHeight)
+			}
+			// a metadata update moves the hashed key to the height of the transaction. The hash of a CRDT key
+			// is not the hash of its value, hence, unlike for the other keys, the version of the private value
+			// is moved here, provided that the value is not stale
+			for _, metadataWrite := range collPvtRwSet.KvRwSet.MetadataWrites {
+				if unchanged[metadataWrite.Key] {
+					continue
+				}
+				isCRDTKey, err := m.isCRDTKey(ns, coll, metadataWrite.Key)
+				if err != nil {
+					return err
+				}
+				if !isCRDTKey {
+					continue
+				}
+				curVV, err := retrieveLatestVal(ns, coll, metadataWrite.Key, pvtUpdates, m.db)
+				if err != nil {
+					return err
+				}
+				hashedKey := privacyenabledstate.HashedCompositeKey{
+					Namespace:      ns,
+					CollectionName: coll,
+					KeyHash:        string(util.ComputeStringHash(metadataWrite.Key)),
+				}
+				if curVV != nil && version.AreSame(curVV.Version, prevVersions[hashedKey]) {
+					// a metadata update does not change the hash of the hashed key
+					pvtUpdates.PutValAndMetadata(ns, coll, metadataWrite.Key, curVV.Value, curVV.Metadata, txHeight)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isCRDTKey tells whether a private key is a CRDT key, i.e. whether its hashed key records a CRDT type, which
+// the metadata updates carry forward, or it has the prefix of the CRDT keys created by the earlier versions of the shim
+func (m *pvtCRDTMerger) isCRDTKey(ns, coll, key string) (bool, error) {
+	if strings.HasPrefix(key, statedb.CRDTPrefix) {
+		return true, nil
+	}
+	keyType, err := m.updates.pvtCRDTType(ns, coll, util.ComputeStringHash(key), m.db)
+	return keyType != "", err
+}
+
 type collKey struct {
 	ns, coll, key string
 }
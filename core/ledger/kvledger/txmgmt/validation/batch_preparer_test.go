@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/flogging/floggingtest"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -23,6 +24,8 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation/mock"
 	mocklgr "github.com/hyperledger/fabric/core/ledger/mock"
 	lutils "github.com/hyperledger/fabric/core/ledger/util"
@@ -123,7 +126,7 @@ func TestValidateAndPreparePvtBatch(t *testing.T) {
 	require.NoError(t, err)
 	addPvtRWSetToPvtUpdateBatch(tx1TxPvtRWSet, expectedPvtUpdates, version.NewHeight(uint64(10), uint64(0)))
 
-	actualPvtUpdates, err := validateAndPreparePvtBatch(mvccValidatedBlock, testDB, nil, pvtDataMap)
+	actualPvtUpdates, err := validateAndPreparePvtBatch(mvccValidatedBlock, testDB, nil, pvtDataMap, nil)
 	require.NoError(t, err)
 	require.Equal(t, expectedPvtUpdates, actualPvtUpdates)
 
@@ -297,7 +300,7 @@ func TestTxStatsInfoWithConfigTx(t *testing.T) {
 	defer testDBEnv.Cleanup()
 	testDB := testDBEnv.GetDBHandle("emptydb")
 
-	v := NewCommitBatchPreparer(nil, testDB, nil, testHashFunc)
+	v := NewCommitBatchPreparer(nil, testDB, nil, testHashFunc, crdt_resolver.NewRegistry(), nil, 0)
 
 	gb := testutil.ConstructTestBlocks(t, 1)[0]
 	_, _, txStatsInfo, err := v.ValidateAndPrepareBatch(&ledger.BlockAndPvtData{Block: gb}, true)
@@ -329,7 +332,7 @@ func TestTXMgrContainsPostOrderWrites(t *testing.T) {
 		common.HeaderType_CONFIG: fakeTxProcessor,
 	}
 
-	v := NewCommitBatchPreparer(mockSimulatorProvider, testDB, customTxProcessors, testHashFunc)
+	v := NewCommitBatchPreparer(mockSimulatorProvider, testDB, customTxProcessors, testHashFunc, crdt_resolver.NewRegistry(), nil, 0)
 	blocks := testutil.ConstructTestBlocks(t, 2)
 
 	// block with config tx that produces post order writes
@@ -369,7 +372,7 @@ func TestTxStatsInfo(t *testing.T) {
 	defer testDBEnv.Cleanup()
 	testDB := testDBEnv.GetDBHandle("emptydb")
 
-	v := NewCommitBatchPreparer(nil, testDB, nil, testHashFunc)
+	v := NewCommitBatchPreparer(nil, testDB, nil, testHashFunc, crdt_resolver.NewRegistry(), nil, 0)
 
 	// create a block with 4 endorser transactions
 	tx1SimulationResults, _ := testutilGenerateTxSimulationResultsAsBytes(t,
@@ -482,6 +485,135 @@ func TestTxStatsInfo(t *testing.T) {
 	require.Equal(t, expectedTxStatInfo, txStatsInfo)
 }
 
+func TestCRDTValues(t *testing.T) {
+	counter := statedb.CRDTPrefix + "counter"
+	deleted := statedb.CRDTPrefix + "deleted"
+	b := rwsetutil.NewRWSetBuilder()
+	b.AddToCRDT("ns1", "IntAdd", counter, []byte("1"), nil)
+	b.AddToCRDT("ns1", "IntAdd", counter, []byte("2"), nil)
+	b.AddToCRDT("ns1", "IntAdd", deleted, []byte("1"), nil)
+	b.AddToCRDT("ns2", "IntAdd", counter, []byte("1"), nil)
+	b.AddToWriteSet("ns1", "key1", []byte("value1"))
+	txRWSet := getTestPubSimulationRWSet(t, b)[0]
+
+	updates := privacyenabledstate.NewPubUpdateBatch()
+	updates.Put("ns1", counter, []byte("42"), version.NewHeight(1, 0))
+	updates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	updates.Delete("ns1", deleted, version.NewHeight(1, 0))
+
+	// the values are the ones of the update batch, once per key, and
+	// the keys left out of the update batch are skipped
+	require.Equal(t, []*peer.CRDTValue{
+		{Namespace: "ns1", Key: counter, Value: []byte("42")},
+		{Namespace: "ns1", Key: deleted, IsDelete: true},
+	}, crdtValues(txRWSet, updates))
+}
+
+func TestPvtCRDTMerger(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	committedHeight := version.NewHeight(1, 0)
+	batch := privacyenabledstate.NewUpdateBatch()
+	policy, err := statemetadata.Serialize([]*kvrwset.KVMetadataEntry{{Name: "VALIDATION_PARAMETER", Value: []byte("policy")}})
+	require.NoError(t, err)
+	batch.PvtUpdates.PutValAndMetadata("ns1", "coll1", "counter", []byte("5"), policy, committedHeight)
+	for _, key := range []string{"stale", "failing"} {
+		batch.PvtUpdates.Put("ns1", "coll1", key, []byte("5"), committedHeight)
+	}
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(batch, committedHeight))
+
+	b := rwsetutil.NewRWSetBuilder()
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "counter", []byte("2"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "counter", []byte("3"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "fresh", []byte("1"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "stale", []byte("1"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "failing", []byte("1"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "failing", []byte("not a number"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "failing", []byte("1"))
+	simRes, err := b.GetTxSimulationResults()
+	require.NoError(t, err)
+	pvtRWSet, err := rwsetutil.TxPvtRwSetFromProtoMsg(simRes.PvtSimulationResults)
+	require.NoError(t, err)
+
+	hashedKey := func(key string) privacyenabledstate.HashedCompositeKey {
+		return privacyenabledstate.HashedCompositeKey{Namespace: "ns1", CollectionName: "coll1", KeyHash: string(lutils.ComputeStringHash(key))}
+	}
+	prevVersions := map[privacyenabledstate.HashedCompositeKey]*version.Height{
+		hashedKey("counter"): committedHeight,
+		// the peer missed the private data of a transaction that merged into the key
+		hashedKey("stale"):   version.NewHeight(1, 1),
+		hashedKey("failing"): committedHeight,
+	}
+
+	txHeight := version.NewHeight(2, 0)
+	merger := newPvtCRDTMerger(db, newPubAndHashUpdates(), crdt_resolver.NewRegistry())
+	// the hash of the hashed key once the transaction is tracked is recorded in the metadata of the merged value
+	merger.valueHashes[hashedKey("counter")] = []byte("counter-hash")
+	pvtUpdates := privacyenabledstate.NewPvtUpdateBatch()
+	require.NoError(t, merger.merge(pvtRWSet, pvtUpdates, prevVersions, txHeight))
+
+	counter := pvtUpdates.Get("ns1", "coll1", "counter")
+	require.Equal(t, []byte("10"), counter.Value)
+	require.Equal(t, txHeight, counter.Version)
+	metadata, err := statemetadata.Deserialize(counter.Metadata)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		statemetadata.CRDTHash: []byte("counter-hash"),
+		"VALIDATION_PARAMETER": []byte("policy"),
+	}, metadata)
+	fresh := pvtUpdates.Get("ns1", "coll1", "fresh")
+	require.Equal(t, []byte("1"), fresh.Value)
+	require.Equal(t, txHeight, fresh.Version)
+	// the stale key is left stale
+	require.Nil(t, pvtUpdates.Get("ns1", "coll1", "stale"))
+	// the key a payload fails to merge into is left at its version, none of the payloads of the transaction is merged
+	require.Nil(t, pvtUpdates.Get("ns1", "coll1", "failing"))
+}
+
+func TestPvtCRDTMergerTracksHashedKeys(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.HashUpdates.Put("ns1", "coll1", lutils.ComputeStringHash("counter"), lutils.ComputeHash([]byte("5")), version.NewHeight(1, 0))
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 0)))
+
+	var builders []*rwsetutil.RWSetBuilder
+	add := func() *rwsetutil.RWSetBuilder {
+		b := rwsetutil.NewRWSetBuilder()
+		builders = append(builders, b)
+		return b
+	}
+	add().AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "counter", []byte("2"))
+	b := add()
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "counter", []byte("3"))
+	b.AddToPvtAndHashedCRDT("ns1", "coll1", "IntAdd", "fresh", []byte("1"))
+	add().AddToPvtAndHashedWriteSet("ns1", "coll1", "fresh", []byte("7"))
+
+	// the tracked hashes are the ones of the hashed updates of the block
+	updates := newPubAndHashUpdates()
+	merger := newPvtCRDTMerger(db, updates, crdt_resolver.NewRegistry())
+	for i, txRWSet := range getTestPubSimulationRWSet(t, builders...) {
+		txHeight := version.NewHeight(2, uint64(i))
+		_, err := merger.trackHashedWrites(txRWSet, txHeight)
+		require.NoError(t, err)
+		require.NoError(t, updates.applyPvtCRDTHashes(txRWSet, txHeight, db))
+		require.NoError(t, updates.applyWriteSet(txRWSet, txHeight, db, false))
+		for _, key := range []string{"counter", "fresh"} {
+			keyHash := lutils.ComputeStringHash(key)
+			hashedKey := privacyenabledstate.HashedCompositeKey{Namespace: "ns1", CollectionName: "coll1", KeyHash: string(keyHash)}
+			if vv := updates.hashUpdates.Get("ns1", "coll1", string(keyHash)); vv != nil {
+				require.Equal(t, vv.Value, merger.valueHashes[hashedKey], "key %s after transaction %d", key, i)
+			}
+		}
+	}
+}
+
 func testutilSampleTxSimulationResults(t *testing.T, key string) *ledger.TxSimulationResults {
 	rwSetBuilder := rwsetutil.NewRWSetBuilder()
 	// public rws ns1 + ns2
@@ -675,3 +807,15 @@ func Test_preprocessProtoBlock_processNonEndorserTx(t *testing.T) {
 	require.Equal(t, expectedPreprocessedBlock, internalBlock)
 	require.Equal(t, expectedTxStatInfo, txsStatInfo)
 }
+
+func TestCreatorMSPID(t *testing.T) {
+	creator := protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")})
+	payload := &common.Payload{Header: &common.Header{
+		SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator}),
+	}}
+	require.Equal(t, "Org1MSP", creatorMSPID(payload))
+
+	payload.Header.SignatureHeader = []byte("garbage")
+	require.Equal(t, "", creatorMSPID(payload))
+	require.Equal(t, "", creatorMSPID(&common.Payload{}))
+}
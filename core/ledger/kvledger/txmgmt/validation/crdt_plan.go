@@ -0,0 +1,357 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+)
+
+// crdtPlan merges the CRDT payloads of the public keys of a block before the transactions
+// are validated. The payloads of a key are merged in transaction order assuming that every
+// transaction of the block is valid, and the keys are merged concurrently. The consecutive
+// payloads of a key whose resolver supports accumulating diffs are accumulated without
+// encoding the intermediate values.
+//
+// While validating the transactions, the outcome of the merges of a key are used as long as
+// the transactions that merged into the key turned out valid or invalid as assumed. Once a
+// transaction did not, the key diverges from the plan and its remaining payloads are merged
+// one by one as if there were no plan. Hence a plan does not change the outcome of a block.
+// The keys written, reset or deleted by a transaction of the block are left out of the plan
+type crdtPlan struct {
+	keys     map[statedb.CompositeKey]*crdtKeyPlan
+	txKeys   map[int][]*crdtKeyPlan
+	blockNum uint64
+}
+
+// crdtKeyPlan holds the planned merges of a key
+type crdtKeyPlan struct {
+	ns, key   string
+	committed *statedb.VersionedValue
+	ops       []*crdtOp
+	// txOps maps the transactions to the range of their ops
+	txOps map[int][2]int
+	// failedTxs holds the transactions whose payloads failed to merge into the key
+	failedTxs map[int]bool
+	// committedType is the CRDT type recorded in the committed metadata of the key, if any
+	committedType string
+	// typedMetadata holds the metadata of a key with no committed CRDT type once it takes the
+	// resolution type of one of its payloads
+	typedMetadata map[string][]byte
+	// txValues holds the value of the key once the payloads of a transaction are merged, for the
+	// transactions whose payloads merged
+	txValues map[int]*crdtTxValue
+	// cursor is the index of the next op to be used
+	cursor   int
+	diverged bool
+}
+
+// crdtTxValue is the value of a key and its CRDT type once the payloads of a transaction are merged
+type crdtTxValue struct {
+	value   []byte
+	keyType string
+}
+
+// crdtOp is a payload merged into a key and the outcome of the merge
+type crdtOp struct {
+	txIndex int
+	height  *version.Height
+	payload *kvrwset.CRDTPayload
+	err     error
+	// mergeTime is the time taken to merge the payload
+	mergeTime time.Duration
+}
+
+// newCRDTPlan plans the merges of the CRDT payloads of the block, using up to the given number of goroutines
+func newCRDTPlan(blk *block, db *privacyenabledstate.DB, resolvers *crdt_resolver.Registry, workers int) (*crdtPlan, error) {
+	written := map[statedb.CompositeKey]struct{}{}
+	for _, tx := range blk.txs {
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				written[statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: kvWrite.Key}] = struct{}{}
+			}
+			for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+				written[statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: metadataWrite.Key}] = struct{}{}
+			}
+			for _, payload := range nsRWSet.KvRwSet.CrdtPayload {
+				if payload.Operation != kvrwset.CRDTPayload_MERGE {
+					written[statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: payload.Key}] = struct{}{}
+				}
+			}
+		}
+	}
+
+	p := &crdtPlan{
+		keys:     map[statedb.CompositeKey]*crdtKeyPlan{},
+		txKeys:   map[int][]*crdtKeyPlan{},
+		blockNum: blk.num,
+	}
+	var keyPlans []*crdtKeyPlan
+	for _, tx := range blk.txs {
+		if tx.crdtOverBudget {
+			continue
+		}
+		height := version.NewHeight(blk.num, uint64(tx.indexInBlock))
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			for _, payload := range nsRWSet.KvRwSet.CrdtPayload {
+				compositeKey := statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: payload.Key}
+				if _, ok := written[compositeKey]; ok {
+					continue
+				}
+				keyPlan, ok := p.keys[compositeKey]
+				if !ok {
+					keyPlan = &crdtKeyPlan{
+						ns:        nsRWSet.NameSpace,
+						key:       payload.Key,
+						txOps:     map[int][2]int{},
+						failedTxs: map[int]bool{},
+						txValues:  map[int]*crdtTxValue{},
+					}
+					p.keys[compositeKey] = keyPlan
+					keyPlans = append(keyPlans, keyPlan)
+				}
+				opRange, ok := keyPlan.txOps[tx.indexInBlock]
+				if !ok {
+					opRange = [2]int{len(keyPlan.ops), len(keyPlan.ops)}
+					p.txKeys[tx.indexInBlock] = append(p.txKeys[tx.indexInBlock], keyPlan)
+				}
+				opRange[1]++
+				keyPlan.txOps[tx.indexInBlock] = opRange
+				keyPlan.ops = append(keyPlan.ops, &crdtOp{txIndex: tx.indexInBlock, height: height, payload: payload})
+			}
+		}
+	}
+
+	if workers > len(keyPlans) {
+		workers = len(keyPlans)
+	}
+	keyPlansChan := make(chan *crdtKeyPlan, len(keyPlans))
+	for _, keyPlan := range keyPlans {
+		keyPlansChan <- keyPlan
+	}
+	close(keyPlansChan)
+
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for keyPlan := range keyPlansChan {
+				committed, err := db.GetCommittedState(keyPlan.ns, keyPlan.key)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				keyPlan.committed = committed
+				if err := keyPlan.initTypes(); err != nil {
+					// the key is left to the merges one by one, which fail the same way
+					keyPlan.diverged = true
+					continue
+				}
+				keyPlan.merge(resolvers, len(keyPlan.ops))
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debugf("Planned the merges of %d CRDT keys of block [%d]", len(keyPlans), blk.num)
+	return p, nil
+}
+
+// initTypes reads the CRDT type of the key from its committed metadata and, if there is none, prepares
+// the metadata of the key for every resolution type of its payloads
+func (p *crdtKeyPlan) initTypes() error {
+	var metadata []byte
+	if p.committed != nil {
+		metadata = p.committed.Metadata
+	}
+	var err error
+	if p.committedType, err = statemetadata.GetCRDTType(metadata); err != nil || p.committedType != "" {
+		return err
+	}
+	p.typedMetadata = map[string][]byte{}
+	for _, op := range p.ops {
+		resType := op.payload.ResolutionType
+		if _, ok := p.typedMetadata[resType]; ok {
+			continue
+		}
+		if p.typedMetadata[resType], err = statemetadata.SetCRDTType(metadata, resType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metadata returns the metadata of the key once it holds values of the given CRDT type
+func (p *crdtKeyPlan) metadata(keyType string) []byte {
+	if keyType != p.committedType {
+		return p.typedMetadata[keyType]
+	}
+	if p.committed == nil {
+		return nil
+	}
+	return p.committed.Metadata
+}
+
+// merge merges the payloads of the first n ops, n being the first op of a transaction, into the committed
+// value of the key, records the outcome of the merges and returns the resulting value and CRDT type along
+// with the height of the last transaction that merged into the key, which is nil if none did. The payloads
+// of a transaction are merged all or none, as the transaction is invalid if one of them fails to merge
+func (p *crdtKeyPlan) merge(resolvers *crdt_resolver.Registry, n int) ([]byte, string, *version.Height) {
+	cur := []byte{}
+	if p.committed != nil {
+		cur = p.committed.Value
+	}
+	keyType := p.committedType
+	var lastHeight *version.Height
+	var acc crdt_resolver.Accumulator
+	var accType string
+
+	value := func() []byte {
+		if acc != nil {
+			cur = acc.Value()
+			acc = nil
+		}
+		return cur
+	}
+	// txValue returns the value merged so far without ending the accumulation
+	txValue := func() []byte {
+		if acc != nil {
+			return acc.Value()
+		}
+		return cur
+	}
+
+	// the diffs an accumulator does not accumulate, see crdt_resolver.ErrNotAccumulated, are merged by the resolver
+	mergeOp := func(payload *kvrwset.CRDTPayload, height *version.Height) error {
+		if err := crdt_resolver.CheckType(p.key, keyType, payload.ResolutionType); err != nil {
+			return err
+		}
+		if len(payload.Predicates) == 0 {
+			if acc != nil && accType == payload.ResolutionType {
+				if err := acc.Add(payload.Data); err != crdt_resolver.ErrNotAccumulated {
+					return err
+				}
+			} else {
+				newAcc, err := resolvers.NewAccumulator(value(), payload.ResolutionType)
+				if err != nil {
+					return err
+				}
+				if newAcc != nil {
+					if err := newAcc.Add(payload.Data); err != crdt_resolver.ErrNotAccumulated {
+						if err != nil {
+							return err
+						}
+						acc, accType = newAcc, payload.ResolutionType
+						return nil
+					}
+				}
+			}
+		}
+		curValue := value()
+		if err := crdt_resolver.CheckPredicates(curValue, payload.ResolutionType, payload.Predicates); err != nil {
+			return err
+		}
+		merged, err := resolvers.ResolveAt(curValue, payload.Data, payload.ResolutionType, height)
+		if err != nil {
+			return err
+		}
+		cur = merged
+		return nil
+	}
+
+	for start := 0; start < n; {
+		txIndex := p.ops[start].txIndex
+		end := p.txOps[txIndex][1]
+		// a single payload leaves the value unchanged if it fails to merge, otherwise
+		// the value is kept so that the transaction can be rolled back
+		var before []byte
+		beforeType := keyType
+		if end-start > 1 {
+			before = value()
+		}
+		failed := false
+		for i := start; i < end; i++ {
+			op := p.ops[i]
+			start := time.Now()
+			op.err = mergeOp(op.payload, op.height)
+			op.mergeTime = time.Since(start)
+			if op.err != nil {
+				failed = true
+				break
+			}
+			// a key with no CRDT type takes the resolution type of its first payload
+			if keyType == "" {
+				keyType = op.payload.ResolutionType
+			}
+		}
+		if failed {
+			p.failedTxs[txIndex] = true
+			if end-start > 1 {
+				cur, acc = before, nil
+			}
+			keyType = beforeType
+		} else {
+			lastHeight = p.ops[start].height
+			p.txValues[txIndex] = &crdtTxValue{value: txValue(), keyType: keyType}
+		}
+		start = end
+	}
+	return value(), keyType, lastHeight
+}
+
+// nextOp returns the planned merge of the payload merged by the transaction if the key has not diverged
+func (p *crdtPlan) nextOp(ns string, payload *kvrwset.CRDTPayload, txIndex int) (*crdtKeyPlan, *crdtOp) {
+	if p == nil {
+		return nil, nil
+	}
+	keyPlan, ok := p.keys[statedb.CompositeKey{Namespace: ns, Key: payload.Key}]
+	if !ok || keyPlan.diverged || keyPlan.cursor >= len(keyPlan.ops) {
+		return nil, nil
+	}
+	op := keyPlan.ops[keyPlan.cursor]
+	if op.txIndex != txIndex || op.payload != payload {
+		return nil, nil
+	}
+	keyPlan.cursor++
+	return keyPlan, op
+}
+
+// txValidated moves the plan past the payloads of a validated transaction. The keys whose planned merges assumed
+// another outcome of the transaction diverge from the plan. As their values in the batch are the planned ones, the
+// values are replaced by the values of the keys before the transaction
+func (p *crdtPlan) txValidated(txIndex int, valid bool, resolvers *crdt_resolver.Registry, batch *statedb.UpdateBatch) {
+	if p == nil {
+		return
+	}
+	for _, keyPlan := range p.txKeys[txIndex] {
+		opRange := keyPlan.txOps[txIndex]
+		if !keyPlan.diverged && valid == keyPlan.failedTxs[txIndex] {
+			keyPlan.diverged = true
+			value, keyType, height := keyPlan.merge(resolvers, opRange[0])
+			if height != nil {
+				batch.Update(keyPlan.ns, keyPlan.key, &statedb.VersionedValue{Value: value, Metadata: keyPlan.metadata(keyType), Version: height})
+			}
+			logger.Debugf("CRDT key [%s:%s] diverged from the plan at transaction %d of block [%d]", keyPlan.ns, keyPlan.key, txIndex, p.blockNum)
+		}
+		keyPlan.cursor = opRange[1]
+	}
+}
@@ -0,0 +1,421 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+	"github.com/stretchr/testify/require"
+)
+
+// testCRDTMetadata returns the serialized metadata of a key holding a validation parameter
+// and, unless resType is empty, the given CRDT type
+func testCRDTMetadata(t *testing.T, resType string) []byte {
+	metadata, err := statemetadata.Serialize([]*kvrwset.KVMetadataEntry{{Name: "VALIDATION_PARAMETER", Value: []byte("policy")}})
+	require.NoError(t, err)
+	if resType == "" {
+		return metadata
+	}
+	metadata, err = statemetadata.SetCRDTType(metadata, resType)
+	require.NoError(t, err)
+	return metadata
+}
+
+// crdtTypeMetadata returns the serialized metadata of a key holding the given CRDT type only
+func crdtTypeMetadata(t *testing.T, resType string) []byte {
+	metadata, err := statemetadata.SetCRDTType(nil, resType)
+	require.NoError(t, err)
+	return metadata
+}
+
+func TestCRDTPlan(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	hotKey := "hot"
+	boundedKey := "bounded"
+	otherKey := "other"
+	writtenKey := "written"
+	resetKey := "reset"
+	deletedKey := "deleted"
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.PubUpdates.PutValAndMetadata("ns1", hotKey, []byte("10"), testCRDTMetadata(t, ""), version.NewHeight(1, 1))
+	batch.PubUpdates.Put("ns1", boundedKey, []byte("3"), version.NewHeight(1, 2))
+	batch.PubUpdates.PutValAndMetadata("ns1", deletedKey, []byte("4"), testCRDTMetadata(t, "IntAdd"), version.NewHeight(1, 2))
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 2)))
+
+	predicate := func(op kvrwset.CRDTPredicate_Operator, operand string) []*kvrwset.CRDTPredicate {
+		return []*kvrwset.CRDTPredicate{{Operator: op, Operand: []byte(operand)}}
+	}
+
+	buildTxs := func() []*transaction {
+		var builders []*rwsetutil.RWSetBuilder
+		add := func() *rwsetutil.RWSetBuilder {
+			b := rwsetutil.NewRWSetBuilder()
+			builders = append(builders, b)
+			return b
+		}
+		// valid, merged into the hot key
+		add().AddToCRDT("ns1", "BigIntAdd", hotKey, []byte("5"), nil)
+		// invalid diff, the transaction fails to merge
+		add().AddToCRDT("ns1", "BigIntAdd", hotKey, []byte("abc"), nil)
+		// the hot key is planned with this transaction but it fails MVCC validation
+		b := add()
+		b.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+		b.AddToCRDT("ns1", "BigIntAdd", hotKey, []byte("100"), nil)
+		// holds once the transaction above turns out invalid
+		add().AddToCRDT("ns1", "BigIntAdd", hotKey, []byte("1"), predicate(kvrwset.CRDTPredicate_LESS_OR_EQUAL, "15"))
+		// the bounded key can't go below zero
+		add().AddToCRDT("ns1", "UintSub", boundedKey, []byte("2"), nil)
+		add().AddToCRDT("ns1", "UintSub", boundedKey, []byte("2"), nil)
+		// the merge into the other key succeeds but the transaction fails on the bounded key
+		b = add()
+		b.AddToCRDT("ns1", "IntAdd", otherKey, []byte("7"), nil)
+		b.AddToCRDT("ns1", "UintSub", boundedKey, []byte("5"), nil)
+		// two payloads of the same transaction, the second one failing
+		b = add()
+		b.AddToCRDT("ns1", "IntAdd", otherKey, []byte("1"), nil)
+		b.AddToCRDT("ns1", "IntAdd", otherKey, []byte("x"), nil)
+		add().AddToCRDT("ns1", "IntAdd", otherKey, []byte("2"), nil)
+		// a key written in the block is left out of the plan
+		add().AddToCRDT("ns1", "IntAdd", writtenKey, []byte("2"), nil)
+		add().AddToWriteSet("ns1", writtenKey, []byte("40"))
+		add().AddToCRDT("ns1", "IntAdd", writtenKey, []byte("2"), nil)
+		// the transaction fails in its second namespace, hence nothing is merged into the first one
+		b = add()
+		b.AddToCRDT("ns2", "IntAdd", otherKey, []byte("3"), nil)
+		b.AddToCRDT("ns3", "IntAdd", otherKey, []byte("y"), nil)
+		// the keys reset or deleted in the block are left out of the plan as well
+		add().AddToCRDT("ns1", "IntAdd", resetKey, []byte("2"), nil)
+		add().AddResetToCRDT("ns1", "IntAdd", resetKey, []byte("5"))
+		add().AddToCRDT("ns1", "IntAdd", resetKey, []byte("1"), nil)
+		add().AddToCRDT("ns1", "IntAdd", deletedKey, []byte("1"), nil)
+		add().AddDeleteToCRDT("ns1", deletedKey)
+		// the hot key took the type of its first payload, a payload of another type can't be merged
+		add().AddToCRDT("ns1", "StringConcat", hotKey, []byte("1"), nil)
+
+		var txs []*transaction
+		for i, rwset := range getTestPubSimulationRWSet(t, builders...) {
+			txs = append(txs, &transaction{
+				id:             fmt.Sprintf("txid-%d", i),
+				indexInBlock:   i,
+				validationCode: peer.TxValidationCode_VALID,
+				rwset:          rwset,
+			})
+		}
+		return txs
+	}
+
+	expectedCodes := []peer.TxValidationCode{
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_UNDERFLOW,
+		peer.TxValidationCode_CRDT_UNDERFLOW,
+		peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_TYPE_MISMATCH,
+	}
+	expectedValues := map[string]*statedb.VersionedValue{
+		hotKey:     {Value: []byte("16"), Metadata: testCRDTMetadata(t, "BigIntAdd"), Version: version.NewHeight(2, 3)},
+		boundedKey: {Value: []byte("1"), Metadata: crdtTypeMetadata(t, "UintSub"), Version: version.NewHeight(2, 4)},
+		otherKey:   {Value: []byte("2"), Metadata: crdtTypeMetadata(t, "IntAdd"), Version: version.NewHeight(2, 8)},
+		writtenKey: {Value: []byte("42"), Metadata: crdtTypeMetadata(t, "IntAdd"), Version: version.NewHeight(2, 11)},
+		resetKey:   {Value: []byte("6"), Metadata: crdtTypeMetadata(t, "IntAdd"), Version: version.NewHeight(2, 15)},
+		deletedKey: {Version: version.NewHeight(2, 17)},
+	}
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			testValidator := &validator{
+				db:               db,
+				hashFunc:         testHashFunc,
+				crdtResolvers:    crdt_resolver.NewRegistry(),
+				crdtMergeWorkers: workers,
+			}
+			txs := buildTxs()
+			updates, _, err := testValidator.validateAndPrepareBatch(&block{num: 2, txs: txs}, true)
+			require.NoError(t, err)
+			for i, tx := range txs {
+				require.Equal(t, expectedCodes[i], tx.validationCode, "transaction %d", i)
+			}
+			for key, expected := range expectedValues {
+				require.Equal(t, expected, updates.publicUpdates.Get("ns1", key), "key %s", key)
+			}
+			require.False(t, updates.publicUpdates.Exists("ns2", otherKey))
+		})
+	}
+}
+
+func TestCRDTPlanTxValues(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.PubUpdates.PutValAndMetadata("ns1", "counter", []byte("5"), crdtTypeMetadata(t, "IntAdd"), version.NewHeight(1, 0))
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 0)))
+
+	buildTxs := func() []*transaction {
+		var builders []*rwsetutil.RWSetBuilder
+		for _, diff := range []string{"1", "10", "2", "3"} {
+			b := rwsetutil.NewRWSetBuilder()
+			b.AddToCRDT("ns1", "IntAdd", "counter", []byte(diff), nil)
+			builders = append(builders, b)
+		}
+		// the second merge is planned but its transaction fails MVCC validation
+		builders[1].AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+		var txs []*transaction
+		for i, rwset := range getTestPubSimulationRWSet(t, builders...) {
+			txs = append(txs, &transaction{indexInBlock: i, validationCode: peer.TxValidationCode_VALID, rwset: rwset})
+		}
+		return txs
+	}
+	txValue := func(value string) []*peer.CRDTValue {
+		return []*peer.CRDTValue{{Namespace: "ns1", Key: "counter", Value: []byte(value)}}
+	}
+
+	// every transaction reports the value it produced, whether the merges are planned or not
+	for _, workers := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			testValidator := &validator{
+				db:               db,
+				hashFunc:         testHashFunc,
+				crdtResolvers:    crdt_resolver.NewRegistry(),
+				crdtMergeWorkers: workers,
+			}
+			txs := buildTxs()
+			updates, _, err := testValidator.validateAndPrepareBatch(&block{num: 2, txs: txs}, true)
+			require.NoError(t, err)
+			require.Equal(t, peer.TxValidationCode_MVCC_READ_CONFLICT, txs[1].validationCode)
+			require.Equal(t, &statedb.VersionedValue{Value: []byte("11"), Metadata: crdtTypeMetadata(t, "IntAdd"), Version: version.NewHeight(2, 3)},
+				updates.publicUpdates.Get("ns1", "counter"))
+			require.Equal(t, txValue("6"), txs[0].crdtValues)
+			require.Nil(t, txs[1].crdtValues)
+			require.Equal(t, txValue("8"), txs[2].crdtValues)
+			require.Equal(t, txValue("11"), txs[3].crdtValues)
+		})
+	}
+}
+
+func TestCRDTPlanMerge(t *testing.T) {
+	key := "key"
+	blk := &block{num: 2}
+	for i, diff := range []string{"1", "2", "abc", "3"} {
+		b := rwsetutil.NewRWSetBuilder()
+		b.AddToCRDT("ns1", "BigIntAdd", key, []byte(diff), nil)
+		blk.txs = append(blk.txs, &transaction{indexInBlock: i, rwset: getTestPubSimulationRWSet(t, b)[0]})
+	}
+
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+	resolvers := crdt_resolver.NewRegistry()
+
+	plan, err := newCRDTPlan(blk, db, resolvers, 2)
+	require.NoError(t, err)
+	keyPlan := plan.keys[statedb.CompositeKey{Namespace: "ns1", Key: key}]
+	require.NotNil(t, keyPlan)
+	require.Equal(t, map[int]*crdtTxValue{
+		0: {value: []byte("1"), keyType: "BigIntAdd"},
+		1: {value: []byte("3"), keyType: "BigIntAdd"},
+		3: {value: []byte("6"), keyType: "BigIntAdd"},
+	}, keyPlan.txValues)
+	require.Equal(t, map[int]bool{2: true}, keyPlan.failedTxs)
+
+	value, keyType, height := keyPlan.merge(resolvers, 2)
+	require.Equal(t, []byte("3"), value)
+	require.Equal(t, "BigIntAdd", keyType)
+	require.Equal(t, version.NewHeight(2, 1), height)
+
+	value, keyType, height = keyPlan.merge(resolvers, 0)
+	require.Equal(t, []byte{}, value)
+	require.Empty(t, keyType)
+	require.Nil(t, height)
+
+	// the payloads are only used in the order of the plan
+	payload := blk.txs[1].rwset.NsRwSets[0].KvRwSet.CrdtPayload[0]
+	p, op := plan.nextOp("ns1", payload, 1)
+	require.Nil(t, p)
+	require.Nil(t, op)
+	payload = blk.txs[0].rwset.NsRwSets[0].KvRwSet.CrdtPayload[0]
+	p, op = plan.nextOp("ns1", payload, 0)
+	require.Equal(t, keyPlan, p)
+	require.NoError(t, op.err)
+
+	// once the key diverges, the planned merges are no longer used
+	batch := statedb.NewUpdateBatch()
+	plan.txValidated(0, false, resolvers, batch)
+	require.True(t, keyPlan.diverged)
+	require.Nil(t, batch.Get("ns1", key))
+	payload = blk.txs[1].rwset.NsRwSets[0].KvRwSet.CrdtPayload[0]
+	p, _ = plan.nextOp("ns1", payload, 1)
+	require.Nil(t, p)
+
+	var nilPlan *crdtPlan
+	p, op = nilPlan.nextOp("ns1", payload, 1)
+	require.Nil(t, p)
+	require.Nil(t, op)
+	nilPlan.txValidated(1, true, resolvers, batch)
+}
+
+func TestCRDTPlanBudget(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	counterKey := statedb.CRDTPrefix + "counter"
+	textKey := statedb.CRDTPrefix + "text"
+
+	buildTxs := func() []*transaction {
+		var builders []*rwsetutil.RWSetBuilder
+		add := func() *rwsetutil.RWSetBuilder {
+			b := rwsetutil.NewRWSetBuilder()
+			builders = append(builders, b)
+			return b
+		}
+		// the counter reaches the maximum value size while its diffs are accumulated
+		add().AddToCRDT("ns1", "BigIntAdd", counterKey, []byte("9990"), nil)
+		add().AddToCRDT("ns1", "BigIntAdd", counterKey, []byte("9"), nil)
+		add().AddToCRDT("ns1", "BigIntAdd", counterKey, []byte("1"), nil)
+		add().AddToCRDT("ns1", "BigIntAdd", counterKey, []byte("-5"), nil)
+		add().AddToCRDT("ns1", "StringConcat", textKey, []byte("abc"), nil)
+		add().AddToCRDT("ns1", "StringConcat", textKey, []byte("defgh"), nil)
+		// over the diff size budget of the block, whatever the validity of the previous transactions
+		add().AddToCRDT("ns1", "StringConcat", textKey, []byte("d"), nil)
+		// the transactions without payloads are not charged
+		add().AddToWriteSet("ns1", "key1", []byte("value1"))
+
+		var txs []*transaction
+		for i, rwset := range getTestPubSimulationRWSet(t, builders...) {
+			txs = append(txs, &transaction{
+				id:             fmt.Sprintf("txid-%d", i),
+				indexInBlock:   i,
+				validationCode: peer.TxValidationCode_VALID,
+				rwset:          rwset,
+			})
+		}
+		return txs
+	}
+
+	expectedCodes := []peer.TxValidationCode{
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_BUDGET_EXCEEDED,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_BUDGET_EXCEEDED,
+		peer.TxValidationCode_CRDT_BUDGET_EXCEEDED,
+		peer.TxValidationCode_VALID,
+	}
+
+	resolvers := crdt_resolver.NewRegistry()
+	resolvers.SetLimits(crdt_resolver.Limits{MaxValueSize: 4, MaxBlockDiffSize: 16})
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			testValidator := &validator{
+				db:               db,
+				hashFunc:         testHashFunc,
+				crdtResolvers:    resolvers,
+				crdtMergeWorkers: workers,
+			}
+			txs := buildTxs()
+			updates, _, err := testValidator.validateAndPrepareBatch(&block{num: 1, txs: txs}, true)
+			require.NoError(t, err)
+			for i, tx := range txs {
+				require.Equal(t, expectedCodes[i], tx.validationCode, "transaction %d", i)
+			}
+			require.Equal(t, []byte("9994"), updates.publicUpdates.Get("ns1", counterKey).Value)
+			require.Equal(t, []byte("abc"), updates.publicUpdates.Get("ns1", textKey).Value)
+			require.True(t, txs[6].crdtOverBudget)
+			require.False(t, txs[7].crdtOverBudget)
+
+			// the merges of the valid transactions and the failed merges are recorded
+			require.Len(t, txs[0].crdtMerges, 1)
+			require.Equal(t, counterKey, txs[0].crdtMerges[0].Key)
+			require.Equal(t, "BigIntAdd", txs[0].crdtMerges[0].ResolutionType)
+			require.Empty(t, txs[0].crdtMerges[0].Failure)
+			for _, i := range []int{2, 5, 6} {
+				require.Len(t, txs[i].crdtMerges, 1)
+				require.Equal(t, "CRDT_BUDGET_EXCEEDED", txs[i].crdtMerges[0].Failure, "transaction %d", i)
+			}
+			require.Empty(t, txs[7].crdtMerges)
+		})
+	}
+}
+
+func TestCRDTPlanMergeSteps(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	setKey := statedb.CRDTPrefix + "set"
+	buildTxs := func() []*transaction {
+		var txs []*transaction
+		// a merge takes a step plus a step for every element of the set and of the diff
+		for i, diff := range []string{`{"add":["a","b","c"]}`, `{"add":["d","e","f","g"]}`, `{"add":["h"]}`} {
+			b := rwsetutil.NewRWSetBuilder()
+			b.AddToCRDT("ns1", crdt_resolver.GSet, setKey, []byte(diff), nil)
+			txs = append(txs, &transaction{
+				id:             fmt.Sprintf("txid-%d", i),
+				indexInBlock:   i,
+				validationCode: peer.TxValidationCode_VALID,
+				rwset:          getTestPubSimulationRWSet(t, b)[0],
+			})
+		}
+		return txs
+	}
+
+	resolvers := crdt_resolver.NewRegistry()
+	resolvers.SetLimits(crdt_resolver.Limits{MaxMergeSteps: 8})
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			testValidator := &validator{
+				db:               db,
+				hashFunc:         testHashFunc,
+				crdtResolvers:    resolvers,
+				crdtMergeWorkers: workers,
+			}
+			txs := buildTxs()
+			updates, _, err := testValidator.validateAndPrepareBatch(&block{num: 1, txs: txs}, true)
+			require.NoError(t, err)
+			require.Equal(t, peer.TxValidationCode_VALID, txs[0].validationCode)
+			require.Equal(t, peer.TxValidationCode_VALID, txs[1].validationCode)
+			require.Equal(t, peer.TxValidationCode_CRDT_BUDGET_EXCEEDED, txs[2].validationCode)
+			require.Equal(t, `{"elements":["a","b","c","d","e","f","g"]}`, string(updates.publicUpdates.Get("ns1", setKey).Value))
+		})
+	}
+}
@@ -22,7 +22,23 @@ func prepareTxOps(rwset *rwsetutil.TxRwSet, precedingUpdates *publicAndHashUpdat
 	for ck, keyop := range txops {
 		// check if the final state of the key, value and metadata, is already present in the transaction, then skip
 		// otherwise we need to retrieve latest state and merge in the current value or metadata update
-		if keyop.isDelete() || keyop.isUpsertAndMetadataUpdate() {
+		if keyop.isDelete() {
+			continue
+		}
+
+		// the CRDT type of a key is only recorded by the CRDT payloads, hence it is carried forward
+		// when the metadata of the key is updated or deleted
+		if keyop.isMetadataUpdateOrDelete() {
+			latestMetadata, err := retrieveLatestMetadata(ck.ns, ck.coll, ck.key, precedingUpdates, db)
+			if err != nil {
+				return nil, err
+			}
+			if err := keyop.keepCRDTType(latestMetadata); err != nil {
+				return nil, err
+			}
+		}
+
+		if keyop.isUpsertAndMetadataUpdate() {
 			continue
 		}
 
@@ -228,3 +244,18 @@ func (keyops keyOps) isUpsertAndMetadataUpdate() bool {
 func (keyops keyOps) isOnlyUpsert() bool {
 	return keyops.flag|upsertVal == upsertVal
 }
+
+func (keyops keyOps) isMetadataUpdateOrDelete() bool {
+	return keyops.flag&metadataUpdate == metadataUpdate ||
+		keyops.flag&metadataDelete == metadataDelete
+}
+
+// keepCRDTType records the CRDT type found in the latest metadata of the key, if any, in the metadata written
+func (keyops *keyOps) keepCRDTType(latestMetadata []byte) error {
+	crdtType, err := statemetadata.GetCRDTType(latestMetadata)
+	if err != nil || crdtType == "" {
+		return err
+	}
+	keyops.metadata, err = statemetadata.SetCRDTType(keyops.metadata, crdtType)
+	return err
+}
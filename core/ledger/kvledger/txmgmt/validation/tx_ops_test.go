@@ -205,6 +205,51 @@ func TestTxOpsPreparationMetadataDelete(t *testing.T) {
 	require.Equal(t, ck2ExpectedKeyOps, txOps[ck2])
 }
 
+func TestTxOpsPreparationKeepsCRDTType(t *testing.T) {
+	testDBEnv := privacyenabledstate.LevelDBTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	ck1, ck2 :=
+		compositeKey{ns: "ns1", key: "key1"},
+		compositeKey{ns: "ns1", key: "key2"}
+
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	for _, ck := range []compositeKey{ck1, ck2} {
+		updateBatch.PubUpdates.PutValAndMetadata(
+			ck.ns, ck.key,
+			[]byte("10"),
+			testutilSerializedMetadata(t, map[string][]byte{statemetadata.CRDTType: []byte("IntAdd"), "metadata": []byte("metadata")}),
+			version.NewHeight(1, 1))
+	}
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 1)))
+	precedingUpdates := newPubAndHashUpdates()
+
+	rwset := testutilBuildRwset( // A sample rwset {update metadata for key1, delete metadata for key2}
+		t,
+		nil,
+		map[compositeKey]map[string][]byte{
+			ck1: {"metadata": []byte("metadata_new")},
+			ck2: {},
+		},
+	)
+
+	txOps, err := prepareTxOps(rwset, precedingUpdates, db)
+	require.NoError(t, err)
+
+	// the CRDT type of the keys is kept along with the new metadata
+	checkKeyOps := func(ck compositeKey, flag keyOpsFlag, expectedMetadata map[string][]byte) {
+		require.Equal(t, flag, txOps[ck].flag)
+		require.Equal(t, []byte("10"), txOps[ck].value)
+		metadata, err := statemetadata.Deserialize(txOps[ck].metadata)
+		require.NoError(t, err)
+		require.Equal(t, expectedMetadata, metadata)
+	}
+	checkKeyOps(ck1, metadataUpdate, map[string][]byte{statemetadata.CRDTType: []byte("IntAdd"), "metadata": []byte("metadata_new")})
+	checkKeyOps(ck2, metadataDelete, map[string][]byte{statemetadata.CRDTType: []byte("IntAdd")})
+}
+
 func TestTxOpsPreparationMixedUpdates(t *testing.T) {
 	testDBEnv := privacyenabledstate.LevelDBTestEnv{}
 	testDBEnv.Init(t)
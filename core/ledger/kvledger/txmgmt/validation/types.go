@@ -7,11 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package validation
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+	"github.com/hyperledger/fabric/core/ledger/util"
 )
 
 // block is used to used to hold the information from its proto format to a structure
@@ -29,6 +38,39 @@ type transaction struct {
 	rwset                   *rwsetutil.TxRwSet
 	validationCode          peer.TxValidationCode
 	containsPostOrderWrites bool
+	// creatorMSPID is the MSP ID of the creator of the transaction, to which the diffs of its counters are attributed
+	creatorMSPID string
+	// crdtOverBudget is set if the CRDT payloads of the transaction exceed the diff size budget of the block
+	crdtOverBudget bool
+	crdtMerges     crdtMerges
+	// crdtValues holds the values of the public CRDT keys merged by a valid transaction once it is validated
+	crdtValues []*peer.CRDTValue
+}
+
+// crdtMerges records the merges of the public CRDT payloads of a transaction, see TxStatInfo.CRDTMerges
+type crdtMerges []*CRDTMergeStat
+
+func (m *crdtMerges) record(ns string, payload *kvrwset.CRDTPayload, mergeTime time.Duration) {
+	if m == nil {
+		return
+	}
+	*m = append(*m, &CRDTMergeStat{
+		Namespace:      ns,
+		Key:            payload.Key,
+		ResolutionType: payload.ResolutionType,
+		MergeTime:      mergeTime,
+	})
+}
+
+// failed only keeps the last merge, which failed with the given validation code,
+// as the other merges of the transaction are undone
+func (m *crdtMerges) failed(validationCode peer.TxValidationCode) {
+	if m == nil || len(*m) == 0 {
+		return
+	}
+	last := (*m)[len(*m)-1]
+	last.Failure = validationCode.String()
+	*m = crdtMerges{last}
 }
 
 // publicAndHashUpdates encapsulates public and hash updates. The intended use of this to hold the updates
@@ -116,24 +158,54 @@ func (u *publicAndHashUpdates) applyCRDT(
 	txRWSet *rwsetutil.TxRwSet,
 	txHeight *version.Height,
 	db *privacyenabledstate.DB,
+	resolvers *crdt_resolver.Registry,
+	schemas *crdtSchemaCache,
+	plan *crdtPlan,
 	containsPostOrderWrites bool,
+	merges *crdtMerges,
 ) error {
-	prevValues := make(map[string]*statedb.VersionedValue)
+	if err := u.checkPvtCRDTTypes(txRWSet, db); err != nil {
+		return err
+	}
+
+	undoLog := newCRDTUndoLog(u.publicUpdates.UpdateBatch)
 
 	for _, nsRwSet := range txRWSet.NsRwSets {
 		ns := nsRwSet.NameSpace
 
 		for _, crdt := range nsRwSet.KvRwSet.CrdtPayload {
-			metadata := []byte("") // #TODO Shold be get some metadata?
-
-			curVV, err := u.publicUpdates.CRDTMerge(db.GetState, ns, crdt.Key, crdt.Data, crdt.ResolutionType, metadata, txHeight)
+			// a delete carries no resolution type to check against the schema
+			if crdt.Operation != kvrwset.CRDTPayload_DELETE {
+				if err := schemas.check(ns, crdt.Key, crdt.ResolutionType); err != nil {
+					merges.record(ns, crdt, 0)
+					undoLog.rollback()
+					return err
+				}
+			}
 
-			if _, exist := prevValues[crdt.Key]; !exist {
-				prevValues[crdt.Key] = curVV
+			undoLog.record(ns, crdt.Key)
+			var err error
+			if keyPlan, op := plan.nextOp(ns, crdt, int(txHeight.TxNum)); op != nil {
+				err = u.applyPlannedCRDT(keyPlan, op, txHeight)
+				merges.record(ns, crdt, op.mergeTime)
+			} else {
+				// the payloads are applied in the order of the block, so that a reset or a delete
+				// overrides the merges of the preceding transactions and precedes the following ones
+				start := time.Now()
+				switch crdt.Operation {
+				case kvrwset.CRDTPayload_RESET:
+					_, err = u.publicUpdates.CRDTReset(db.GetCommittedState, resolvers, ns, crdt.Key, crdt.Data, crdt.ResolutionType, txHeight)
+				case kvrwset.CRDTPayload_DELETE:
+					_, err = u.publicUpdates.CRDTDelete(db.GetCommittedState, ns, crdt.Key, txHeight)
+				default:
+					_, err = u.publicUpdates.CRDTMerge(db.GetCommittedState, resolvers, ns, crdt.Key, crdt.Data, crdt.ResolutionType, crdt.Predicates, txHeight)
+				}
+				merges.record(ns, crdt, time.Since(start))
 			}
 
 			if err != nil {
-				restoreUpdates(ns, prevValues, u.publicUpdates.UpdateBatch)
+				// the merges of the transaction into the keys of every namespace are undone
+				undoLog.rollback()
 				return err
 			}
 		}
@@ -142,8 +214,177 @@ func (u *publicAndHashUpdates) applyCRDT(
 	return nil
 }
 
-func restoreUpdates(ns string, prevValues map[string]*statedb.VersionedValue, batch *statedb.UpdateBatch) {
-	for key, value := range prevValues {
-		batch.Update(ns, key, value)
+// applyPlannedCRDT applies the planned merge of a CRDT payload to the batch. As the key is not written by the block,
+// its metadata is the committed one, with the CRDT type of the key recorded. The merged value is the value of the key
+// once the planned payloads of the transaction are merged, as if they were merged one by one
+func (u *publicAndHashUpdates) applyPlannedCRDT(keyPlan *crdtKeyPlan, op *crdtOp, txHeight *version.Height) error {
+	if op.err != nil {
+		return op.err
+	}
+	txValue := keyPlan.txValues[op.txIndex]
+	u.publicUpdates.Update(keyPlan.ns, keyPlan.key, &statedb.VersionedValue{Value: txValue.value, Metadata: keyPlan.metadata(txValue.keyType), Version: txHeight})
+	return nil
+}
+
+// checkPvtCRDTTypes checks the resolution types of the CRDT payloads the transaction merges into private keys
+// against the CRDT types recorded in the metadata of the hashed keys. A key with no CRDT type takes the
+// resolution type of its first payload
+func (u *publicAndHashUpdates) checkPvtCRDTTypes(txRWSet *rwsetutil.TxRwSet, db *privacyenabledstate.DB) error {
+	txTypes := map[privacyenabledstate.HashedCompositeKey]string{}
+	for _, nsRwSet := range txRWSet.NsRwSets {
+		ns := nsRwSet.NameSpace
+		for _, collRwSet := range nsRwSet.CollHashedRwSets {
+			coll := collRwSet.CollectionName
+			for _, crdtHash := range collRwSet.HashedRwSet.CrdtPayloadHashes {
+				hashedKey := privacyenabledstate.HashedCompositeKey{Namespace: ns, CollectionName: coll, KeyHash: string(crdtHash.KeyHash)}
+				keyType, ok := txTypes[hashedKey]
+				if !ok {
+					var err error
+					if keyType, err = u.pvtCRDTType(ns, coll, crdtHash.KeyHash, db); err != nil {
+						return err
+					}
+				}
+				if err := crdt_resolver.CheckType(fmt.Sprintf("with hash %x in collection [%s:%s]", crdtHash.KeyHash, ns, coll), keyType, crdtHash.ResolutionType); err != nil {
+					return err
+				}
+				if keyType == "" {
+					keyType = crdtHash.ResolutionType
+				}
+				txTypes[hashedKey] = keyType
+			}
+		}
+	}
+	return nil
+}
+
+// pvtCRDTType returns the CRDT type recorded in the metadata of a hashed key
+func (u *publicAndHashUpdates) pvtCRDTType(ns, coll string, keyHash []byte, db *privacyenabledstate.DB) (string, error) {
+	vv := u.hashUpdates.Get(ns, coll, string(keyHash))
+	if vv != nil {
+		return statemetadata.GetCRDTType(vv.Metadata)
+	}
+	metadata, err := db.GetPrivateDataMetadataByHash(ns, coll, keyHash)
+	if err != nil {
+		return "", err
+	}
+	return statemetadata.GetCRDTType(metadata)
+}
+
+// applyPvtCRDTHashes advances the hashed state of the private keys the transaction merges CRDT payloads
+// into. As the peers that do not hold the private data can't merge the payloads, the hash of a merged key
+// is the hash of its previous hash followed by the hash of the payload
+func (u *publicAndHashUpdates) applyPvtCRDTHashes(
+	txRWSet *rwsetutil.TxRwSet,
+	txHeight *version.Height,
+	db *privacyenabledstate.DB,
+) error {
+	for _, nsRwSet := range txRWSet.NsRwSets {
+		ns := nsRwSet.NameSpace
+		for _, collRwSet := range nsRwSet.CollHashedRwSets {
+			coll := collRwSet.CollectionName
+			for _, crdtHash := range collRwSet.HashedRwSet.CrdtPayloadHashes {
+				prevVV := u.hashUpdates.Get(ns, coll, string(crdtHash.KeyHash))
+				if prevVV == nil {
+					var err error
+					if prevVV, err = db.GetCommittedValueHash(ns, coll, crdtHash.KeyHash); err != nil {
+						return err
+					}
+				}
+				var prevHash, metadata []byte
+				if prevVV != nil {
+					prevHash, metadata = prevVV.Value, prevVV.Metadata
+				}
+				// the CRDT type was checked by checkPvtCRDTTypes
+				keyType, err := statemetadata.GetCRDTType(metadata)
+				if err != nil {
+					return err
+				}
+				if keyType == "" {
+					if metadata, err = statemetadata.SetCRDTType(metadata, crdtHash.ResolutionType); err != nil {
+						return err
+					}
+				}
+				valueHash := util.ComputeHash(append(append([]byte{}, prevHash...), crdtHash.DataHash...))
+				u.hashUpdates.PutValHashAndMetadata(ns, coll, crdtHash.KeyHash, valueHash, metadata, txHeight)
+			}
+		}
+	}
+	return nil
+}
+
+// crdtSchemaCache holds the CRDT schemas of the namespaces touched by the transactions
+// of a block, so that the chaincode definition is looked up once per namespace
+type crdtSchemaCache struct {
+	provider CRDTSchemaProvider
+	schemas  map[string]*lifecycle.CRDTSchema
+}
+
+func newCRDTSchemaCache(provider CRDTSchemaProvider) *crdtSchemaCache {
+	return &crdtSchemaCache{
+		provider: provider,
+		schemas:  make(map[string]*lifecycle.CRDTSchema),
+	}
+}
+
+// check returns a SchemaViolation if the committed CRDT schema of the namespace does not allow
+// the resolution type to be used on the key. The errors of the provider are returned as is
+func (c *crdtSchemaCache) check(ns, key, resType string) error {
+	if c.provider == nil {
+		return nil
+	}
+	schema, ok := c.schemas[ns]
+	if !ok {
+		var err error
+		if schema, err = c.provider.CRDTSchema(ns); err != nil {
+			return err
+		}
+		c.schemas[ns] = schema
+	}
+	return crdt_resolver.CheckSchema(schema, strings.TrimPrefix(key, statedb.CRDTPrefix), resType)
+}
+
+// crdtUndoLog records the entries of the update batch for the keys a transaction merges CRDT payloads into,
+// before the first merge into every key, so that the merges of a failed transaction can be undone across
+// all the namespaces of the transaction
+type crdtUndoLog struct {
+	batch   *statedb.UpdateBatch
+	entries map[statedb.CompositeKey]*crdtUndoEntry
+}
+
+// crdtUndoEntry is the entry of a key in the update batch, if any
+type crdtUndoEntry struct {
+	vv     *statedb.VersionedValue
+	exists bool
+}
+
+func newCRDTUndoLog(batch *statedb.UpdateBatch) *crdtUndoLog {
+	return &crdtUndoLog{
+		batch:   batch,
+		entries: make(map[statedb.CompositeKey]*crdtUndoEntry),
+	}
+}
+
+// record records the entry of a key unless it is already recorded
+func (l *crdtUndoLog) record(ns, key string) {
+	compositeKey := statedb.CompositeKey{Namespace: ns, Key: key}
+	if _, ok := l.entries[compositeKey]; ok {
+		return
+	}
+	l.entries[compositeKey] = &crdtUndoEntry{
+		vv:     l.batch.Get(ns, key),
+		exists: l.batch.Exists(ns, key),
+	}
+}
+
+// rollback restores the recorded entries. The keys that had no entry are removed from the batch,
+// as opposed to being updated with their committed value
+func (l *crdtUndoLog) rollback() {
+	for compositeKey, entry := range l.entries {
+		if entry.exists {
+			l.batch.Update(compositeKey.Namespace, compositeKey.Key, entry.vv)
+		} else {
+			l.batch.Remove(compositeKey.Namespace, compositeKey.Key)
+		}
 	}
+	l.entries = make(map[statedb.CompositeKey]*crdtUndoEntry)
 }
@@ -7,12 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package validation
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -149,3 +156,281 @@ func TestApplyWriteSet(t *testing.T) {
 	// Check result
 	require.Equal(t, expected, pahu)
 }
+
+type testCRDTSchemaProvider map[string]*lifecycle.CRDTSchema
+
+func (p testCRDTSchemaProvider) CRDTSchema(ns string) (*lifecycle.CRDTSchema, error) {
+	return p[ns], nil
+}
+
+func TestApplyCRDTWithSchema(t *testing.T) {
+	testdbEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testdbEnv.Init(t)
+	defer testdbEnv.Cleanup()
+	testdb := testdbEnv.GetDBHandle("testdb")
+
+	schemas := newCRDTSchemaCache(testCRDTSchemaProvider{
+		"ns1": {
+			Keys: []*lifecycle.CRDTKeySchema{
+				{KeyPattern: "counter_*", ResolutionTypes: []string{"IntAdd"}},
+				{KeyPattern: "log", ResolutionTypes: []string{"StringConcat", "Set"}},
+			},
+		},
+	})
+	resolvers := crdt_resolver.NewRegistry()
+	ver := &version.Height{BlockNum: 1, TxNum: 1}
+
+	txRWSet := func(ns string, payloads ...*kvrwset.CRDTPayload) *rwsetutil.TxRwSet {
+		return &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{
+			{NameSpace: ns, KvRwSet: &kvrwset.KVRWSet{CrdtPayload: payloads}},
+		}}
+	}
+
+	updates := newPubAndHashUpdates()
+	require.NoError(t, updates.applyCRDT(
+		txRWSet("ns1",
+			&kvrwset.CRDTPayload{Key: "CRDTFIELD_counter_a", ResolutionType: "IntAdd", Data: []byte("5")},
+			&kvrwset.CRDTPayload{Key: "CRDTFIELD_log", ResolutionType: "StringConcat", Data: []byte("a")},
+		),
+		ver, testdb, resolvers, schemas, nil, false, nil,
+	))
+	require.Equal(t, []byte("5"), updates.publicUpdates.Get("ns1", "CRDTFIELD_counter_a").Value)
+
+	err := updates.applyCRDT(
+		txRWSet("ns1", &kvrwset.CRDTPayload{Key: "CRDTFIELD_counter_a", ResolutionType: "StringConcat", Data: []byte("1")}),
+		ver, testdb, resolvers, schemas, nil, false, nil,
+	)
+	require.EqualError(t, err, "Resolve type StringConcat is not allowed for key counter_a by pattern counter_*")
+
+	// the merges of a rejected transaction are rolled back
+	err = updates.applyCRDT(
+		txRWSet("ns1",
+			&kvrwset.CRDTPayload{Key: "CRDTFIELD_counter_a", ResolutionType: "IntAdd", Data: []byte("1")},
+			&kvrwset.CRDTPayload{Key: "CRDTFIELD_balance", ResolutionType: "IntAdd", Data: []byte("1")},
+		),
+		ver, testdb, resolvers, schemas, nil, false, nil,
+	)
+	require.EqualError(t, err, "Key balance does not match any pattern of the CRDT schema")
+	require.Equal(t, []byte("5"), updates.publicUpdates.Get("ns1", "CRDTFIELD_counter_a").Value)
+
+	// namespaces without a schema accept every resolution type
+	require.NoError(t, updates.applyCRDT(
+		txRWSet("ns2", &kvrwset.CRDTPayload{Key: "CRDTFIELD_counter_a", ResolutionType: "StringConcat", Data: []byte("a")}),
+		ver, testdb, resolvers, schemas, nil, false, nil,
+	))
+	require.Equal(t, []byte("a"), updates.publicUpdates.Get("ns2", "CRDTFIELD_counter_a").Value)
+}
+
+func TestApplyCRDTWithPredicates(t *testing.T) {
+	testdbEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testdbEnv.Init(t)
+	defer testdbEnv.Cleanup()
+	testdb := testdbEnv.GetDBHandle("testdb")
+
+	resolvers := crdt_resolver.NewRegistry()
+	schemas := newCRDTSchemaCache(nil)
+	ver := &version.Height{BlockNum: 1, TxNum: 1}
+	withdraw := func(amount string) *rwsetutil.TxRwSet {
+		return &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{
+			{NameSpace: "ns1", KvRwSet: &kvrwset.KVRWSet{CrdtPayload: []*kvrwset.CRDTPayload{
+				{
+					Key:            "CRDTFIELD_balance",
+					ResolutionType: "IntAdd",
+					Data:           []byte("-" + amount),
+					Predicates: []*kvrwset.CRDTPredicate{
+						{Operator: kvrwset.CRDTPredicate_GREATER_OR_EQUAL, Operand: []byte(amount)},
+					},
+				},
+			}}},
+		}}
+	}
+
+	updates := newPubAndHashUpdates()
+	updates.publicUpdates.Put("ns1", "CRDTFIELD_balance", []byte("10"), ver)
+
+	require.NoError(t, updates.applyCRDT(withdraw("6"), ver, testdb, resolvers, schemas, nil, false, nil))
+	require.Equal(t, []byte("4"), updates.publicUpdates.Get("ns1", "CRDTFIELD_balance").Value)
+
+	// the predicate is evaluated against the value merged by the previous transactions
+	err := updates.applyCRDT(withdraw("6"), ver, testdb, resolvers, schemas, nil, false, nil)
+	require.EqualError(t, err, "Predicate GREATER_OR_EQUAL failed: current value 4 is less than 6")
+	require.True(t, errors.As(err, new(*crdt_resolver.PredicateError)))
+	require.Equal(t, []byte("4"), updates.publicUpdates.Get("ns1", "CRDTFIELD_balance").Value)
+}
+
+func TestApplyCRDTRollback(t *testing.T) {
+	testdbEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testdbEnv.Init(t)
+	defer testdbEnv.Cleanup()
+	testdb := testdbEnv.GetDBHandle("testdb")
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "CRDTFIELD_committed", []byte("10"), version.NewHeight(1, 0))
+	require.NoError(t, testdb.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 0)))
+
+	resolvers := crdt_resolver.NewRegistry()
+	schemas := newCRDTSchemaCache(nil)
+	ver := version.NewHeight(2, 1)
+	payload := func(key, data string) *kvrwset.CRDTPayload {
+		return &kvrwset.CRDTPayload{Key: key, ResolutionType: "IntAdd", Data: []byte(data)}
+	}
+	nsRWSet := func(ns string, payloads ...*kvrwset.CRDTPayload) *rwsetutil.NsRwSet {
+		return &rwsetutil.NsRwSet{NameSpace: ns, KvRwSet: &kvrwset.KVRWSet{CrdtPayload: payloads}}
+	}
+
+	updates := newPubAndHashUpdates()
+	updates.publicUpdates.Put("ns2", "CRDTFIELD_merged", []byte("1"), version.NewHeight(2, 0))
+	updates.publicUpdates.Delete("ns2", "CRDTFIELD_deleted", version.NewHeight(2, 0))
+
+	// the transaction fails in its last namespace, after merging into
+	// a committed key, a key of the batch and new keys of other namespaces
+	var merges crdtMerges
+	err := updates.applyCRDT(&rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{
+		nsRWSet("ns1", payload("CRDTFIELD_committed", "1"), payload("CRDTFIELD_new", "1")),
+		nsRWSet("ns2", payload("CRDTFIELD_merged", "1"), payload("CRDTFIELD_deleted", "1"), payload("CRDTFIELD_merged", "1")),
+		nsRWSet("ns3", payload("CRDTFIELD_new", "1"), payload("CRDTFIELD_new", "abc")),
+	}}, ver, testdb, resolvers, schemas, nil, false, &merges)
+	require.Error(t, err)
+	require.Len(t, merges, 7)
+	// only the failed merge is kept
+	merges.failed(peer.TxValidationCode_CRDT_MALFORMED_DIFF)
+	require.Len(t, merges, 1)
+	require.Equal(t, "ns3", merges[0].Namespace)
+	require.Equal(t, "CRDTFIELD_new", merges[0].Key)
+	require.Equal(t, "IntAdd", merges[0].ResolutionType)
+	require.Equal(t, "CRDT_MALFORMED_DIFF", merges[0].Failure)
+
+	expectedUpdates := newPubAndHashUpdates()
+	expectedUpdates.publicUpdates.Put("ns2", "CRDTFIELD_merged", []byte("1"), version.NewHeight(2, 0))
+	expectedUpdates.publicUpdates.Delete("ns2", "CRDTFIELD_deleted", version.NewHeight(2, 0))
+	require.Equal(t, expectedUpdates, updates)
+
+	// the same transaction without the failing payload is merged in every namespace
+	require.NoError(t, updates.applyCRDT(&rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{
+		nsRWSet("ns1", payload("CRDTFIELD_committed", "1"), payload("CRDTFIELD_new", "1")),
+		nsRWSet("ns2", payload("CRDTFIELD_merged", "1"), payload("CRDTFIELD_deleted", "1"), payload("CRDTFIELD_merged", "1")),
+		nsRWSet("ns3", payload("CRDTFIELD_new", "1")),
+	}}, ver, testdb, resolvers, schemas, nil, false, nil))
+	require.Equal(t, []byte("11"), updates.publicUpdates.Get("ns1", "CRDTFIELD_committed").Value)
+	require.Equal(t, []byte("1"), updates.publicUpdates.Get("ns1", "CRDTFIELD_new").Value)
+	require.Equal(t, []byte("3"), updates.publicUpdates.Get("ns2", "CRDTFIELD_merged").Value)
+	require.Equal(t, []byte("1"), updates.publicUpdates.Get("ns2", "CRDTFIELD_deleted").Value)
+	require.Equal(t, []byte("1"), updates.publicUpdates.Get("ns3", "CRDTFIELD_new").Value)
+}
+
+func TestApplyCRDTResetAndDelete(t *testing.T) {
+	testdbEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testdbEnv.Init(t)
+	defer testdbEnv.Cleanup()
+	testdb := testdbEnv.GetDBHandle("testdb")
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.PutValAndMetadata("ns1", "balance", []byte("10"), testCRDTMetadata(t, ""), version.NewHeight(1, 0))
+	require.NoError(t, testdb.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 0)))
+
+	resolvers := crdt_resolver.NewRegistry()
+	schemas := newCRDTSchemaCache(nil)
+	apply := func(updates *publicAndHashUpdates, txNum uint64, payloads ...*kvrwset.CRDTPayload) error {
+		return updates.applyCRDT(&rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{
+			{NameSpace: "ns1", KvRwSet: &kvrwset.KVRWSet{CrdtPayload: payloads}},
+		}}, version.NewHeight(2, txNum), testdb, resolvers, schemas, nil, false, nil)
+	}
+	merge := func(data string) *kvrwset.CRDTPayload {
+		return &kvrwset.CRDTPayload{Key: "balance", ResolutionType: "IntAdd", Data: []byte(data)}
+	}
+	reset := func(data string) *kvrwset.CRDTPayload {
+		return &kvrwset.CRDTPayload{Key: "balance", ResolutionType: "IntAdd", Data: []byte(data), Operation: kvrwset.CRDTPayload_RESET}
+	}
+	del := &kvrwset.CRDTPayload{Key: "balance", Operation: kvrwset.CRDTPayload_DELETE}
+
+	// the ordinary key takes the type of the first payload merged into it
+	updates := newPubAndHashUpdates()
+	require.NoError(t, apply(updates, 0, merge("5")))
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("15"), Metadata: testCRDTMetadata(t, "IntAdd"), Version: version.NewHeight(2, 0)},
+		updates.publicUpdates.Get("ns1", "balance"))
+	err := apply(updates, 1, &kvrwset.CRDTPayload{Key: "balance", ResolutionType: "StringConcat", Data: []byte("1")})
+	require.EqualError(t, err, "CRDT key balance holds a IntAdd value, can't merge a StringConcat payload")
+	require.Equal(t, crdt_resolver.TypeMismatch, crdt_resolver.MergeFailureOf(err))
+
+	// a reset overrides the merges of the preceding transactions and keeps the metadata
+	require.NoError(t, apply(updates, 1, reset("3")))
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("3"), Metadata: testCRDTMetadata(t, "IntAdd"), Version: version.NewHeight(2, 1)},
+		updates.publicUpdates.Get("ns1", "balance"))
+
+	// the following transactions merge into the reset value
+	require.NoError(t, apply(updates, 2, merge("1")))
+	require.Equal(t, []byte("4"), updates.publicUpdates.Get("ns1", "balance").Value)
+
+	// a delete removes the value along with the metadata
+	require.NoError(t, apply(updates, 3, del))
+	require.Equal(t, &statedb.VersionedValue{Version: version.NewHeight(2, 3)}, updates.publicUpdates.Get("ns1", "balance"))
+
+	// a reset of a malformed value fails and the delete is kept
+	require.Error(t, apply(updates, 4, reset("abc")))
+	require.Equal(t, &statedb.VersionedValue{Version: version.NewHeight(2, 3)}, updates.publicUpdates.Get("ns1", "balance"))
+
+	// the following transactions recreate the key from an empty value, with its CRDT type only
+	require.NoError(t, apply(updates, 5, merge("2"), merge("1")))
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("3"), Metadata: crdtTypeMetadata(t, "IntAdd"), Version: version.NewHeight(2, 5)},
+		updates.publicUpdates.Get("ns1", "balance"))
+
+	// the payloads of a transaction apply in order as well
+	require.NoError(t, apply(updates, 6, merge("2"), del, merge("7"), reset("1"), merge("1")))
+	require.Equal(t, []byte("2"), updates.publicUpdates.Get("ns1", "balance").Value)
+	require.NoError(t, apply(updates, 7, merge("2"), reset("1"), del))
+	require.Nil(t, updates.publicUpdates.Get("ns1", "balance").Value)
+}
+
+func TestApplyPvtCRDTHashes(t *testing.T) {
+	testdbEnv := &privacyenabledstate.LevelDBTestEnv{}
+	testdbEnv.Init(t)
+	defer testdbEnv.Cleanup()
+	testdb := testdbEnv.GetDBHandle("testdb")
+
+	committedMetadata, err := statemetadata.SetCRDTType(nil, "IntAdd")
+	require.NoError(t, err)
+	committedHash := util.ComputeHash([]byte("5"))
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.HashUpdates.PutValHashAndMetadata("ns1", "coll1", util.ComputeStringHash("counter"), committedHash, committedMetadata, version.NewHeight(1, 0))
+	require.NoError(t, testdb.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 0)))
+
+	txRWSet := func(key string, data ...string) *rwsetutil.TxRwSet {
+		var crdtHashes []*kvrwset.CRDTPayloadHash
+		for _, d := range data {
+			crdtHashes = append(crdtHashes, &kvrwset.CRDTPayloadHash{
+				ResolutionType: "IntAdd",
+				KeyHash:        util.ComputeStringHash(key),
+				DataHash:       util.ComputeHash([]byte(d)),
+			})
+		}
+		return &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{
+			NameSpace: "ns1",
+			KvRwSet:   &kvrwset.KVRWSet{},
+			CollHashedRwSets: []*rwsetutil.CollHashedRwSet{{
+				CollectionName: "coll1",
+				HashedRwSet:    &kvrwset.HashedRWSet{CrdtPayloadHashes: crdtHashes},
+			}},
+		}}}
+	}
+	chain := func(prevHash []byte, data string) []byte {
+		return util.ComputeHash(append(append([]byte{}, prevHash...), util.ComputeHash([]byte(data))...))
+	}
+
+	// the hash of the key chains the hashes of the payloads to the committed hash, in the order of the block
+	updates := newPubAndHashUpdates()
+	require.NoError(t, updates.applyPvtCRDTHashes(txRWSet("counter", "2", "3"), version.NewHeight(2, 0), testdb))
+	require.NoError(t, updates.applyPvtCRDTHashes(txRWSet("counter", "4"), version.NewHeight(2, 1), testdb))
+	require.Equal(t, &statedb.VersionedValue{
+		Value:    chain(chain(chain(committedHash, "2"), "3"), "4"),
+		Metadata: committedMetadata,
+		Version:  version.NewHeight(2, 1),
+	}, updates.hashUpdates.Get("ns1", "coll1", string(util.ComputeStringHash("counter"))))
+
+	// a new key starts the chain from an empty hash and records the resolution type of its first payload
+	require.NoError(t, updates.applyPvtCRDTHashes(txRWSet("fresh", "1"), version.NewHeight(2, 2), testdb))
+	require.Equal(t, &statedb.VersionedValue{
+		Value:    chain(nil, "1"),
+		Metadata: committedMetadata,
+		Version:  version.NewHeight(2, 2),
+	}, updates.hashUpdates.Get("ns1", "coll1", string(util.ComputeStringHash("fresh"))))
+}
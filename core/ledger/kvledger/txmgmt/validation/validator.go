@@ -7,19 +7,28 @@ SPDX-License-Identifier: Apache-2.0
 package validation
 
 import (
+	"errors"
+
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 )
 
 // validator validates a tx against the latest committed state
 // and preceding valid transactions with in the same block
 type validator struct {
-	db       *privacyenabledstate.DB
-	hashFunc rwsetutil.HashFunc
+	db                 *privacyenabledstate.DB
+	hashFunc           rwsetutil.HashFunc
+	crdtResolvers      *crdt_resolver.Registry
+	crdtSchemaProvider CRDTSchemaProvider
+	// crdtMergeWorkers is the number of goroutines merging the CRDT payloads of a block
+	// ahead of the validation of the transactions, see crdtPlan. The payloads are merged
+	// one by one while validating the transactions if it is not greater than one
+	crdtMergeWorkers int
 }
 
 // preLoadCommittedVersionOfRSet loads committed version of all keys in each
@@ -77,6 +86,50 @@ func (v *validator) preLoadCommittedVersionOfRSet(blk *block) error {
 	return nil
 }
 
+// preLoadCommittedValueOfCRDTKeys loads committed value of all public keys and key
+// hashes merged by the CRDT payloads of the transactions into a cache, so that
+// the payloads are merged without reading the keys one by one
+func (v *validator) preLoadCommittedValueOfCRDTKeys(blk *block) error {
+	var pubKeys []*statedb.CompositeKey
+	var hashedKeys []*privacyenabledstate.HashedCompositeKey
+
+	pubKeysMap := make(map[statedb.CompositeKey]interface{})
+	hashedKeysMap := make(map[privacyenabledstate.HashedCompositeKey]interface{})
+
+	for _, tx := range blk.txs {
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			for _, crdtPayload := range nsRWSet.KvRwSet.CrdtPayload {
+				compositeKey := statedb.CompositeKey{
+					Namespace: nsRWSet.NameSpace,
+					Key:       crdtPayload.Key,
+				}
+				if _, ok := pubKeysMap[compositeKey]; !ok {
+					pubKeysMap[compositeKey] = nil
+					pubKeys = append(pubKeys, &compositeKey)
+				}
+			}
+			for _, colHashedRwSet := range nsRWSet.CollHashedRwSets {
+				for _, crdtPayloadHash := range colHashedRwSet.HashedRwSet.CrdtPayloadHashes {
+					hashedCompositeKey := privacyenabledstate.HashedCompositeKey{
+						Namespace:      nsRWSet.NameSpace,
+						CollectionName: colHashedRwSet.CollectionName,
+						KeyHash:        string(crdtPayloadHash.KeyHash),
+					}
+					if _, ok := hashedKeysMap[hashedCompositeKey]; !ok {
+						hashedKeysMap[hashedCompositeKey] = nil
+						hashedKeys = append(hashedKeys, &hashedCompositeKey)
+					}
+				}
+			}
+		}
+	}
+
+	if len(pubKeys) > 0 || len(hashedKeys) > 0 {
+		return v.db.LoadCommittedValuesOfPubAndHashedKeys(pubKeys, hashedKeys)
+	}
+	return nil
+}
+
 // validateAndPrepareBatch performs validation and prepares the batch for final writes
 func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (*publicAndHashUpdates, []*AppInitiatedPurgeUpdate, error) {
 	// Check whether statedb implements BulkOptimizable interface. For now,
@@ -87,10 +140,27 @@ func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (
 		if err != nil {
 			return nil, nil, err
 		}
+		// the values are loaded after the versions as loading the versions resets the cache
+		if err := v.preLoadCommittedValueOfCRDTKeys(blk); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	updates := newPubAndHashUpdates()
 	purgeTracker := newPvtdataPurgeTracker()
+	crdtSchemas := newCRDTSchemaCache(v.crdtSchemaProvider)
+	crdtResolvers := v.crdtResolvers
+	if crdtResolvers != nil {
+		markCRDTTxsOverBudget(blk, crdtResolvers.Limits().MaxBlockDiffSize)
+	}
+
+	var plan *crdtPlan
+	if v.crdtMergeWorkers > 1 {
+		var err error
+		if plan, err = newCRDTPlan(blk, v.db, crdtResolvers, v.crdtMergeWorkers); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	for _, tx := range blk.txs {
 		var validationCode peer.TxValidationCode
@@ -102,19 +172,43 @@ func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (
 		committingTxHeight := version.NewHeight(blk.num, uint64(tx.indexInBlock))
 
 		if validationCode == peer.TxValidationCode_VALID {
-			if err := updates.applyCRDT(tx.rwset, committingTxHeight, v.db, tx.containsPostOrderWrites); err != nil {
-				logger.Warningf("CRDT error <%s> while processing transaction %d from block %d", err, tx.id, blk.num)
-				validationCode = peer.TxValidationCode_CRDT_CONFLICT
+			if tx.crdtOverBudget {
+				validationCode = peer.TxValidationCode_CRDT_BUDGET_EXCEEDED
+				logger.Warningf("CRDT payloads of transaction %s from block %d exceed the diff size budget of %d bytes of the block",
+					tx.id, blk.num, crdtResolvers.Limits().MaxBlockDiffSize)
+				// none of the payloads is merged, the first one is reported as failed
+				if payload, ns := firstCRDTPayload(tx.rwset); payload != nil {
+					tx.crdtMerges.record(ns, payload, 0)
+					tx.crdtMerges.failed(validationCode)
+				}
+			} else {
+				err := checkCRDTAttribution(tx.rwset, tx.creatorMSPID, &tx.crdtMerges)
+				if err == nil {
+					err = updates.applyCRDT(tx.rwset, committingTxHeight, v.db, crdtResolvers, crdtSchemas, plan, tx.containsPostOrderWrites, &tx.crdtMerges)
+				}
+				if err != nil {
+					var ok bool
+					if validationCode, ok = crdtValidationCode(err); !ok {
+						return nil, nil, err
+					}
+					tx.crdtMerges.failed(validationCode)
+					logger.Warningf("CRDT error <%s> while processing transaction %s from block %d, reason code [%s]", err, tx.id, blk.num, validationCode)
+				}
 			}
 		}
 
 		tx.validationCode = validationCode
+		plan.txValidated(tx.indexInBlock, validationCode == peer.TxValidationCode_VALID, crdtResolvers, updates.publicUpdates.UpdateBatch)
 		if validationCode == peer.TxValidationCode_VALID {
 			logger.Debugf("Block [%d] Transaction index [%d] TxId [%s] marked as valid by state validator. ContainsPostOrderWrites [%t]", blk.num, tx.indexInBlock, tx.id, tx.containsPostOrderWrites)
 
+			if err := updates.applyPvtCRDTHashes(tx.rwset, committingTxHeight, v.db); err != nil {
+				return nil, nil, err
+			}
 			if err := updates.applyWriteSet(tx.rwset, committingTxHeight, v.db, tx.containsPostOrderWrites); err != nil {
 				return nil, nil, err
 			}
+			tx.crdtValues = crdtValues(tx.rwset, updates.publicUpdates)
 
 			purgeTracker.update(tx.rwset, committingTxHeight)
 		} else {
@@ -126,6 +220,88 @@ func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (
 	return updates, purgeTracker.getUpdates(), nil
 }
 
+// checkCRDTAttribution checks that the public CRDT payloads merged by the transaction only change the components
+// of the counters that belong to the MSP of its creator. The first payload that does not is recorded as failed
+func checkCRDTAttribution(txRWSet *rwsetutil.TxRwSet, creatorMSPID string, merges *crdtMerges) error {
+	for _, nsRwSet := range txRWSet.NsRwSets {
+		for _, payload := range nsRwSet.KvRwSet.CrdtPayload {
+			// a reset replaces the value of the key and a delete carries no diff
+			if payload.Operation != kvrwset.CRDTPayload_MERGE {
+				continue
+			}
+			if err := crdt_resolver.CheckDiffAttribution(payload.ResolutionType, payload.Data, creatorMSPID); err != nil {
+				merges.record(nsRwSet.NameSpace, payload, 0)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// markCRDTTxsOverBudget marks the transactions whose public CRDT payloads would take the total size of the diffs
+// merged by the block over the given maximum, if any. The payloads are charged in block order whatever the validity
+// of the transactions, so that the marked transactions are the same on every peer and are known before merging
+func markCRDTTxsOverBudget(blk *block, maxSize int) {
+	if maxSize <= 0 {
+		return
+	}
+	size := 0
+	for _, tx := range blk.txs {
+		txSize := 0
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			for _, payload := range nsRWSet.KvRwSet.CrdtPayload {
+				txSize += len(payload.Data)
+			}
+		}
+		if size+txSize > maxSize {
+			tx.crdtOverBudget = true
+			continue
+		}
+		size += txSize
+	}
+}
+
+// firstCRDTPayload returns the first public CRDT payload of a transaction and its namespace, if any
+func firstCRDTPayload(txRWSet *rwsetutil.TxRwSet) (*kvrwset.CRDTPayload, string) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if len(nsRWSet.KvRwSet.CrdtPayload) != 0 {
+			return nsRWSet.KvRwSet.CrdtPayload[0], nsRWSet.NameSpace
+		}
+	}
+	return nil, ""
+}
+
+// crdtValidationCode returns the validation code of a transaction whose CRDT payloads failed to merge,
+// so that the clients can tell why the transaction was invalidated. It returns false if the error is not
+// a merge failure, e.g. an error reading the state, which has to abort the commit of the block instead
+func crdtValidationCode(err error) (peer.TxValidationCode, bool) {
+	if errors.As(err, new(*crdt_resolver.PredicateError)) {
+		return peer.TxValidationCode_CRDT_PREDICATE_FAILED, true
+	}
+	var mergeErr *crdt_resolver.MergeError
+	if !errors.As(err, &mergeErr) {
+		return peer.TxValidationCode_INVALID_OTHER_REASON, false
+	}
+	switch mergeErr.Failure {
+	case crdt_resolver.UnknownType:
+		return peer.TxValidationCode_CRDT_UNKNOWN_TYPE, true
+	case crdt_resolver.MalformedDiff:
+		return peer.TxValidationCode_CRDT_MALFORMED_DIFF, true
+	case crdt_resolver.TypeMismatch:
+		return peer.TxValidationCode_CRDT_TYPE_MISMATCH, true
+	case crdt_resolver.Underflow:
+		return peer.TxValidationCode_CRDT_UNDERFLOW, true
+	case crdt_resolver.Overflow:
+		return peer.TxValidationCode_CRDT_OVERFLOW, true
+	case crdt_resolver.SchemaViolation:
+		return peer.TxValidationCode_CRDT_SCHEMA_VIOLATION, true
+	case crdt_resolver.BudgetExceeded:
+		return peer.TxValidationCode_CRDT_BUDGET_EXCEEDED, true
+	default:
+		return peer.TxValidationCode_CRDT_CONFLICT, true
+	}
+}
+
 // validateEndorserTX validates endorser transaction
 func (v *validator) validateEndorserTX(
 	txRWSet *rwsetutil.TxRwSet,
@@ -8,6 +8,7 @@ package validation
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -15,10 +16,12 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/crdt_resolver"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/stretchr/testify/require"
 )
@@ -194,6 +197,73 @@ func TestValidatorBulkLoadingOfCache(t *testing.T) {
 	}
 }
 
+func TestValidatorBulkLoadingOfCRDTValues(t *testing.T) {
+	testDBEnv := testEnvs[couchDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("testdb")
+
+	testValidator := &validator{db: db, hashFunc: testHashFunc, crdtResolvers: crdt_resolver.NewRegistry()}
+
+	// populate db with a public and a hashed CRDT key
+	batch := privacyenabledstate.NewUpdateBatch()
+	pubKV := keyValue{namespace: "ns1", key: statedb.CRDTPrefix + "key1", value: []byte("5"), version: version.NewHeight(1, 0)}
+	hashedKV := keyValue{
+		namespace: "ns2", collection: "col1", key: statedb.CRDTPrefix + "key1",
+		keyHash: util.ComputeStringHash(statedb.CRDTPrefix + "key1"), value: []byte("hash1"),
+		version: version.NewHeight(1, 1),
+	}
+	batch.PubUpdates.Put(pubKV.namespace, pubKV.key, pubKV.value, pubKV.version)
+	batch.HashUpdates.Put(hashedKV.namespace, hashedKV.collection, hashedKV.keyHash, hashedKV.value, hashedKV.version)
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 1)))
+
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToCRDT(pubKV.namespace, "BigIntAdd", pubKV.key, []byte("2"), nil)
+	rwsetBuilder1.AddToPvtAndHashedCRDT(hashedKV.namespace, hashedKV.collection, "BigIntAdd", hashedKV.key, []byte("2"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToCRDT("ns3", "BigIntAdd", statedb.CRDTPrefix+"key1", []byte("2"), nil)
+
+	transRWSets := getTestPubSimulationRWSet(t, rwsetBuilder1, rwsetBuilder2)
+	var trans []*transaction
+	for i, tranRWSet := range transRWSets {
+		tx := &transaction{
+			id:             fmt.Sprintf("txid-%d", i),
+			indexInBlock:   i,
+			validationCode: peer.TxValidationCode_VALID,
+			rwset:          tranRWSet,
+		}
+		trans = append(trans, tx)
+	}
+	blk := &block{num: 2, txs: trans}
+
+	// Clear cache loaded during ApplyPrivacyAwareUpdates()
+	testValidator.db.ClearCachedVersions()
+	bulkOptimizable, _ := db.VersionedDB.(statedb.BulkOptimizable)
+	require.NoError(t, testValidator.preLoadCommittedValueOfCRDTKeys(blk))
+
+	vv, keyFound := bulkOptimizable.GetCachedValue(pubKV.namespace, pubKV.key)
+	require.True(t, keyFound)
+	require.Equal(t, pubKV.value, vv.Value)
+	require.Equal(t, pubKV.version, vv.Version)
+
+	// the key merged by transaction 2 is not in the state db, hence its value is nil
+	vv, keyFound = bulkOptimizable.GetCachedValue("ns3", statedb.CRDTPrefix+"key1")
+	require.True(t, keyFound)
+	require.Nil(t, vv)
+
+	// the committed values are used for merging the payloads
+	vv, err := testValidator.db.GetCommittedValueHash(hashedKV.namespace, hashedKV.collection, hashedKV.keyHash)
+	require.NoError(t, err)
+	require.Equal(t, hashedKV.value, vv.Value)
+
+	updates, _, err := testValidator.validateAndPrepareBatch(blk, true)
+	require.NoError(t, err)
+	require.Equal(t, peer.TxValidationCode_VALID, trans[0].validationCode)
+	require.Equal(t, peer.TxValidationCode_VALID, trans[1].validationCode)
+	require.Equal(t, []byte("7"), updates.publicUpdates.Get(pubKV.namespace, pubKV.key).Value)
+	require.Equal(t, []byte("2"), updates.publicUpdates.Get("ns3", statedb.CRDTPrefix+"key1").Value)
+}
+
 func TestValidator(t *testing.T) {
 	testDBEnv := testEnvs[levelDBtestEnvName]
 	testDBEnv.Init(t)
@@ -237,6 +307,90 @@ func TestValidator(t *testing.T) {
 	checkValidation(t, testValidator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
 }
 
+func TestValidatorCRDTCounterAttribution(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	diff := func(components string) []byte {
+		return []byte(`{"components":` + components + `}`)
+	}
+	var builders []*rwsetutil.RWSetBuilder
+	add := func() *rwsetutil.RWSetBuilder {
+		b := rwsetutil.NewRWSetBuilder()
+		builders = append(builders, b)
+		return b
+	}
+	add().AddToCRDT("ns1", "PNCounter", "counter", diff(`{"Org1MSP":{"inc":"5"}}`), nil)
+	// the component of another organization is changed
+	add().AddToCRDT("ns1", "PNCounter", "counter", diff(`{"Org2MSP":{"dec":"5"}}`), nil)
+	add().AddToCRDT("ns1", "PNCounter", "counter", diff(`{"Org1MSP":{"inc":"1"},"Org2MSP":{"inc":"1"}}`), nil)
+	// the counter fields of a map are attributed as well
+	add().AddToCRDT("ns1", "CRDTMap", "map", []byte(`{"balance":{"type":"GCounter","diff":`+string(diff(`{"Org2MSP":{"inc":"1"}}`))+`}}`), nil)
+	add().AddToCRDT("ns1", "CRDTMap", "map", []byte(`{"balance":{"type":"GCounter","diff":`+string(diff(`{"Org1MSP":{"inc":"1"}}`))+`}}`), nil)
+
+	var txs []*transaction
+	for i, rwset := range getTestPubSimulationRWSet(t, builders...) {
+		txs = append(txs, &transaction{indexInBlock: i, rwset: rwset, creatorMSPID: "Org1MSP"})
+	}
+	testValidator := &validator{db: db, hashFunc: testHashFunc, crdtResolvers: crdt_resolver.NewRegistry()}
+	updates, _, err := testValidator.validateAndPrepareBatch(&block{num: 1, txs: txs}, true)
+	require.NoError(t, err)
+
+	expectedCodes := []peer.TxValidationCode{
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+		peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+		peer.TxValidationCode_CRDT_MALFORMED_DIFF,
+		peer.TxValidationCode_VALID,
+	}
+	for i, tx := range txs {
+		require.Equal(t, expectedCodes[i], tx.validationCode, "transaction %d", i)
+	}
+	require.Equal(t, `{"components":{"Org1MSP":{"inc":"5"}},"total":"5"}`, string(updates.publicUpdates.Get("ns1", "counter").Value))
+	require.Len(t, txs[1].crdtMerges, 1)
+	require.Equal(t, peer.TxValidationCode_CRDT_MALFORMED_DIFF.String(), txs[1].crdtMerges[0].Failure)
+}
+
+func TestValidatorCRDTErrors(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	resolvers := crdt_resolver.NewRegistry()
+	resolvers.Register("Failing", crdt.ResolverFunc(func(curValue []byte, diffValue []byte) ([]byte, error) {
+		return nil, errors.New("resolver failure")
+	}))
+	testValidator := &validator{db: db, hashFunc: testHashFunc, crdtResolvers: resolvers}
+
+	validate := func() ([]*transaction, error) {
+		b1 := rwsetutil.NewRWSetBuilder()
+		b1.AddToCRDT("ns1", "Failing", "key1", []byte("diff"), nil)
+		b2 := rwsetutil.NewRWSetBuilder()
+		b2.AddToCRDT("ns1", "IntAdd", "key2", []byte("1"), nil)
+		var txs []*transaction
+		for i, rwset := range getTestPubSimulationRWSet(t, b1, b2) {
+			txs = append(txs, &transaction{indexInBlock: i, rwset: rwset})
+		}
+		_, _, err := testValidator.validateAndPrepareBatch(&block{num: 1, txs: txs}, true)
+		return txs, err
+	}
+
+	// the error of a resolver invalidates the transaction
+	txs, err := validate()
+	require.NoError(t, err)
+	require.Equal(t, peer.TxValidationCode_CRDT_CONFLICT, txs[0].validationCode)
+	require.Equal(t, peer.TxValidationCode_VALID, txs[1].validationCode)
+
+	// an error reading the state aborts the commit of the block
+	testDBEnv.GetProvider().Close()
+	_, err = validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "leveldb: closed")
+}
+
 func TestPhantomValidation(t *testing.T) {
 	testDBEnv := testEnvs[levelDBtestEnvName]
 	testDBEnv.Init(t)
@@ -17,9 +17,11 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/bccsp"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 )
 
 const (
@@ -38,6 +40,9 @@ type Initializer struct {
 	Config                          *Config
 	CustomTxProcessors              map[common.HeaderType]CustomTxProcessor
 	HashProvider                    HashProvider
+	// CRDTResolvers maps resolution types to resolvers that are made available
+	// in addition to (or instead of) the builtin ones
+	CRDTResolvers map[string]crdt.Resolver
 }
 
 // Config is a structure used to configure a ledger provider.
@@ -52,6 +57,8 @@ type Config struct {
 	HistoryDBConfig *HistoryDBConfig
 	// SnapshotsConfig holds the configuration parameters for the snapshots.
 	SnapshotsConfig *SnapshotsConfig
+	// CRDTConfig holds the configuration parameters for merging the CRDT payloads.
+	CRDTConfig *CRDTConfig
 }
 
 // StateDBConfig is a structure used to configure the state parameters for the ledger.
@@ -131,6 +138,32 @@ type SnapshotsConfig struct {
 	RootDir string
 }
 
+// CRDTConfig is a structure used to configure the merging of the CRDT payloads at commit time
+type CRDTConfig struct {
+	// MergeWorkers is the number of goroutines merging the CRDT payloads of the different keys
+	// of a block concurrently. Zero defaults to the number of CPUs. One merges the payloads one
+	// by one, in transaction order.
+	MergeWorkers int
+	// EnableTestResolvers makes the resolvers that are only meant for testing available, e.g. the
+	// Wait resolver. It must not be set on the peers of a production network.
+	EnableTestResolvers bool
+	// ValuesRetention is the number of the last blocks whose CRDT values, delivered to the clients along with
+	// the blocks, are kept. Zero keeps the values of every block.
+	ValuesRetention uint64
+	// MaxValueSize is the maximum size, in bytes, of a diff and of a value merged by a resolver. The limits
+	// decide the validity of the transactions, hence they must be the same on every peer of a channel.
+	MaxValueSize int
+	// MaxMergeSteps is the maximum number of steps, e.g. elements of a set or operations of a JSON patch,
+	// the builtin resolvers may take to merge a diff.
+	MaxMergeSteps int
+	// MaxBlockDiffSize is the maximum total size, in bytes, of the diffs of the public CRDT payloads of a block.
+	// The transactions exceeding a limit are invalidated. Zero disables a limit.
+	MaxBlockDiffSize int
+	// SlowMergeWarning is the time above which a merge is logged. It does not change the outcome of the merge.
+	// Zero disables the warning.
+	SlowMergeWarning time.Duration
+}
+
 // PeerLedgerProvider provides handle to ledger instances
 type PeerLedgerProvider interface {
 	// CreateFromGenesisBlock creates a new ledger with the given genesis block.
@@ -185,6 +218,10 @@ type PeerLedger interface {
 	// The pvt data is filtered by the list of 'ns/collections' supplied in the filter
 	// A nil filter does not filter any results and causes retrieving all the pvt data for the given blockNum
 	GetPvtDataByNum(blockNum uint64, filter PvtNsCollFilter) ([]*TxPvtData, error)
+	// GetCRDTValuesByNum returns the values the public CRDT keys merged by the valid transactions of the given block
+	// have right after each transaction, mapped by the sequence of the transactions in the block. An error is returned
+	// if the values of the block are no longer retained, see CRDTConfig.ValuesRetention
+	GetCRDTValuesByNum(blockNum uint64) (map[uint64]*peer.TxCRDTValues, error)
 	// CommitLegacy commits the block and the corresponding pvt data in an atomic operation following the v14 validation/commit path
 	// TODO: add a new Commit() path that replaces CommitLegacy() for the validation refactor described in FAB-12221
 	CommitLegacy(blockAndPvtdata *BlockAndPvtData, commitOpts *CommitOptions) error
@@ -272,8 +309,19 @@ type QueryExecutor interface {
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in fabric-protos/ledger/queryresult.
 	ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (QueryResultsIterator, error)
+	// GetCRDTStateRangeScanIterator returns an iterator over the CRDT keys between the given keys, as
+	// GetStateRangeScanIterator does. An empty endKey refers to the last CRDT key. Since the CRDT keys are
+	// merged at commit rather than checked for conflicts, the range is not recorded for phantom read validation
+	GetCRDTStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error)
+	// ExecuteCRDTQuery executes the given rich query on the CRDT keys only. As in GetCRDTStateRangeScanIterator,
+	// the keys returned are not added to the read-set.
+	// Only used for state databases that support query
+	ExecuteCRDTQuery(namespace, query string) (commonledger.ResultsIterator, error)
 	// GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
 	GetPrivateData(namespace, collection, key string) ([]byte, error)
+	// GetPrivateCRDTState gets the value of a CRDT key of a private collection. An error is returned if the
+	// peer is missing some of the private data merged into the key, as in GetPrivateData
+	GetPrivateCRDTState(namespace, collection, key string) ([]byte, error)
 	// GetPrivateDataMetadata gets the metadata of a private data item identified by a tuple <namespace, collection, key>
 	GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error)
 	// GetPrivateDataMetadataByHash gets the metadata of a private data item identified by a tuple <namespace, collection, keyhash>
@@ -308,8 +356,18 @@ type TxSimulator interface {
 	QueryExecutor
 	// SetState sets the given value for the given namespace and key. For a chaincode, the namespace corresponds to the chaincodeId
 	SetState(namespace string, key string, value []byte) error
-	// Temp
-	SetCRDT(ns string, resType string, key string, value []byte) error
+	// SetCRDT records the diff to be merged into the given CRDT key at commit, using the resolver of the given
+	// resolution type, if the predicates hold for the value of the key at that time. An error is returned if the
+	// resolution type is unknown or the diff can't be merged into the value of the key read by the transaction
+	SetCRDT(ns string, resType string, key string, value []byte, predicates []*kvrwset.CRDTPredicate) error
+	// ResetCRDT records the reset of the given CRDT key at commit: the value of the key is replaced with the given
+	// value merged into an empty value, using the resolver of the given resolution type. The CRDT payloads merged
+	// into the key by the preceding transactions of the block are overridden, the following ones are merged into
+	// the reset value
+	ResetCRDT(ns string, resType string, key string, value []byte) error
+	// DeleteCRDT records the delete of the given CRDT key, along with its metadata, at commit. The CRDT payloads
+	// merged into the key by the following transactions of the block recreate the key from an empty value
+	DeleteCRDT(ns string, key string) error
 
 	// DeleteState deletes the given namespace and key
 	DeleteState(namespace string, key string) error
@@ -323,6 +381,9 @@ type TxSimulator interface {
 	ExecuteUpdate(query string) error
 	// SetPrivateData sets the given value to a key in the private data state represented by the tuple <namespace, collection, key>
 	SetPrivateData(namespace, collection, key string, value []byte) error
+	// SetPrivateCRDT records the diff to be merged into the given CRDT key of a private collection at commit,
+	// using the resolver of the given resolution type. The diff is merged by the peers that hold the private data
+	SetPrivateCRDT(namespace, collection, resType, key string, value []byte) error
 	// SetPrivateDataMultipleKeys sets the values for multiple keys in the private data space in a single call
 	SetPrivateDataMultipleKeys(namespace, collection string, kvs map[string][]byte) error
 	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
@@ -705,6 +766,7 @@ type DeployedChaincodeInfo struct {
 	Hash                        []byte
 	Version                     string
 	ExplicitCollectionConfigPkg *peer.CollectionConfigPackage
+	CRDTSchema                  *lifecycle.CRDTSchema
 	IsLegacy                    bool
 }
 
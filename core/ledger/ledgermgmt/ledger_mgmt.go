@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger"
@@ -59,6 +60,7 @@ type Initializer struct {
 	Config                          *ledger.Config
 	HashProvider                    ledger.HashProvider
 	EbMetadataProvider              MetadataProvider
+	CRDTResolvers                   map[string]crdt.Resolver
 }
 
 // NewLedgerMgr creates a new LedgerMgr
@@ -79,6 +81,7 @@ func NewLedgerMgr(initializer *Initializer) *LedgerMgr {
 			Config:                          initializer.Config,
 			CustomTxProcessors:              initializer.CustomTxProcessors,
 			HashProvider:                    initializer.HashProvider,
+			CRDTResolvers:                   initializer.CRDTResolvers,
 		},
 	)
 	if err != nil {
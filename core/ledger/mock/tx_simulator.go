@@ -4,11 +4,24 @@ package mock
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	ledgera "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
 )
 
 type TxSimulator struct {
+	DeleteCRDTStub        func(string, string) error
+	deleteCRDTMutex       sync.RWMutex
+	deleteCRDTArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	deleteCRDTReturns struct {
+		result1 error
+	}
+	deleteCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeletePrivateDataStub        func(string, string, string) error
 	deletePrivateDataMutex       sync.RWMutex
 	deletePrivateDataArgsForCall []struct {
@@ -63,6 +76,20 @@ type TxSimulator struct {
 	doneMutex       sync.RWMutex
 	doneArgsForCall []struct {
 	}
+	ExecuteCRDTQueryStub        func(string, string) (ledgera.ResultsIterator, error)
+	executeCRDTQueryMutex       sync.RWMutex
+	executeCRDTQueryArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	executeCRDTQueryReturns struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}
+	executeCRDTQueryReturnsOnCall map[int]struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}
 	ExecuteQueryStub        func(string, string) (ledgera.ResultsIterator, error)
 	executeQueryMutex       sync.RWMutex
 	executeQueryArgsForCall []struct {
@@ -133,6 +160,36 @@ type TxSimulator struct {
 		result1 []byte
 		result2 error
 	}
+	GetCRDTStateRangeScanIteratorStub        func(string, string, string) (ledgera.ResultsIterator, error)
+	getCRDTStateRangeScanIteratorMutex       sync.RWMutex
+	getCRDTStateRangeScanIteratorArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getCRDTStateRangeScanIteratorReturns struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}
+	getCRDTStateRangeScanIteratorReturnsOnCall map[int]struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}
+	GetPrivateCRDTStateStub        func(string, string, string) ([]byte, error)
+	getPrivateCRDTStateMutex       sync.RWMutex
+	getPrivateCRDTStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getPrivateCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetPrivateDataStub        func(string, string, string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -322,13 +379,28 @@ type TxSimulator struct {
 	purgePrivateDataReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SetCRDTStub        func(string, string, string, []byte) error
+	ResetCRDTStub        func(string, string, string, []byte) error
+	resetCRDTMutex       sync.RWMutex
+	resetCRDTArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []byte
+	}
+	resetCRDTReturns struct {
+		result1 error
+	}
+	resetCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetCRDTStub        func(string, string, string, []byte, []*kvrwset.CRDTPredicate) error
 	setCRDTMutex       sync.RWMutex
 	setCRDTArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 string
 		arg4 []byte
+		arg5 []*kvrwset.CRDTPredicate
 	}
 	setCRDTReturns struct {
 		result1 error
@@ -336,6 +408,21 @@ type TxSimulator struct {
 	setCRDTReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetPrivateCRDTStub        func(string, string, string, string, []byte) error
+	setPrivateCRDTMutex       sync.RWMutex
+	setPrivateCRDTArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 []byte
+	}
+	setPrivateCRDTReturns struct {
+		result1 error
+	}
+	setPrivateCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivateDataStub        func(string, string, string, []byte) error
 	setPrivateDataMutex       sync.RWMutex
 	setPrivateDataArgsForCall []struct {
@@ -419,6 +506,68 @@ type TxSimulator struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *TxSimulator) DeleteCRDT(arg1 string, arg2 string) error {
+	fake.deleteCRDTMutex.Lock()
+	ret, specificReturn := fake.deleteCRDTReturnsOnCall[len(fake.deleteCRDTArgsForCall)]
+	fake.deleteCRDTArgsForCall = append(fake.deleteCRDTArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteCRDTStub
+	fakeReturns := fake.deleteCRDTReturns
+	fake.recordInvocation("DeleteCRDT", []interface{}{arg1, arg2})
+	fake.deleteCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *TxSimulator) DeleteCRDTCallCount() int {
+	fake.deleteCRDTMutex.RLock()
+	defer fake.deleteCRDTMutex.RUnlock()
+	return len(fake.deleteCRDTArgsForCall)
+}
+
+func (fake *TxSimulator) DeleteCRDTCalls(stub func(string, string) error) {
+	fake.deleteCRDTMutex.Lock()
+	defer fake.deleteCRDTMutex.Unlock()
+	fake.DeleteCRDTStub = stub
+}
+
+func (fake *TxSimulator) DeleteCRDTArgsForCall(i int) (string, string) {
+	fake.deleteCRDTMutex.RLock()
+	defer fake.deleteCRDTMutex.RUnlock()
+	argsForCall := fake.deleteCRDTArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *TxSimulator) DeleteCRDTReturns(result1 error) {
+	fake.deleteCRDTMutex.Lock()
+	defer fake.deleteCRDTMutex.Unlock()
+	fake.DeleteCRDTStub = nil
+	fake.deleteCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TxSimulator) DeleteCRDTReturnsOnCall(i int, result1 error) {
+	fake.deleteCRDTMutex.Lock()
+	defer fake.deleteCRDTMutex.Unlock()
+	fake.DeleteCRDTStub = nil
+	if fake.deleteCRDTReturnsOnCall == nil {
+		fake.deleteCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *TxSimulator) DeletePrivateData(arg1 string, arg2 string, arg3 string) error {
 	fake.deletePrivateDataMutex.Lock()
 	ret, specificReturn := fake.deletePrivateDataReturnsOnCall[len(fake.deletePrivateDataArgsForCall)]
@@ -693,6 +842,71 @@ func (fake *TxSimulator) DoneCalls(stub func()) {
 	fake.DoneStub = stub
 }
 
+func (fake *TxSimulator) ExecuteCRDTQuery(arg1 string, arg2 string) (ledgera.ResultsIterator, error) {
+	fake.executeCRDTQueryMutex.Lock()
+	ret, specificReturn := fake.executeCRDTQueryReturnsOnCall[len(fake.executeCRDTQueryArgsForCall)]
+	fake.executeCRDTQueryArgsForCall = append(fake.executeCRDTQueryArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ExecuteCRDTQueryStub
+	fakeReturns := fake.executeCRDTQueryReturns
+	fake.recordInvocation("ExecuteCRDTQuery", []interface{}{arg1, arg2})
+	fake.executeCRDTQueryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *TxSimulator) ExecuteCRDTQueryCallCount() int {
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	return len(fake.executeCRDTQueryArgsForCall)
+}
+
+func (fake *TxSimulator) ExecuteCRDTQueryCalls(stub func(string, string) (ledgera.ResultsIterator, error)) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = stub
+}
+
+func (fake *TxSimulator) ExecuteCRDTQueryArgsForCall(i int) (string, string) {
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
+	argsForCall := fake.executeCRDTQueryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *TxSimulator) ExecuteCRDTQueryReturns(result1 ledgera.ResultsIterator, result2 error) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = nil
+	fake.executeCRDTQueryReturns = struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) ExecuteCRDTQueryReturnsOnCall(i int, result1 ledgera.ResultsIterator, result2 error) {
+	fake.executeCRDTQueryMutex.Lock()
+	defer fake.executeCRDTQueryMutex.Unlock()
+	fake.ExecuteCRDTQueryStub = nil
+	if fake.executeCRDTQueryReturnsOnCall == nil {
+		fake.executeCRDTQueryReturnsOnCall = make(map[int]struct {
+			result1 ledgera.ResultsIterator
+			result2 error
+		})
+	}
+	fake.executeCRDTQueryReturnsOnCall[i] = struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *TxSimulator) ExecuteQuery(arg1 string, arg2 string) (ledgera.ResultsIterator, error) {
 	fake.executeQueryMutex.Lock()
 	ret, specificReturn := fake.executeQueryReturnsOnCall[len(fake.executeQueryArgsForCall)]
@@ -1017,6 +1231,138 @@ func (fake *TxSimulator) GetCRDTStateReturnsOnCall(i int, result1 []byte, result
 	}{result1, result2}
 }
 
+func (fake *TxSimulator) GetCRDTStateRangeScanIterator(arg1 string, arg2 string, arg3 string) (ledgera.ResultsIterator, error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateRangeScanIteratorReturnsOnCall[len(fake.getCRDTStateRangeScanIteratorArgsForCall)]
+	fake.getCRDTStateRangeScanIteratorArgsForCall = append(fake.getCRDTStateRangeScanIteratorArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetCRDTStateRangeScanIteratorStub
+	fakeReturns := fake.getCRDTStateRangeScanIteratorReturns
+	fake.recordInvocation("GetCRDTStateRangeScanIterator", []interface{}{arg1, arg2, arg3})
+	fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *TxSimulator) GetCRDTStateRangeScanIteratorCallCount() int {
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	return len(fake.getCRDTStateRangeScanIteratorArgsForCall)
+}
+
+func (fake *TxSimulator) GetCRDTStateRangeScanIteratorCalls(stub func(string, string, string) (ledgera.ResultsIterator, error)) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = stub
+}
+
+func (fake *TxSimulator) GetCRDTStateRangeScanIteratorArgsForCall(i int) (string, string, string) {
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	argsForCall := fake.getCRDTStateRangeScanIteratorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *TxSimulator) GetCRDTStateRangeScanIteratorReturns(result1 ledgera.ResultsIterator, result2 error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = nil
+	fake.getCRDTStateRangeScanIteratorReturns = struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) GetCRDTStateRangeScanIteratorReturnsOnCall(i int, result1 ledgera.ResultsIterator, result2 error) {
+	fake.getCRDTStateRangeScanIteratorMutex.Lock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.Unlock()
+	fake.GetCRDTStateRangeScanIteratorStub = nil
+	if fake.getCRDTStateRangeScanIteratorReturnsOnCall == nil {
+		fake.getCRDTStateRangeScanIteratorReturnsOnCall = make(map[int]struct {
+			result1 ledgera.ResultsIterator
+			result2 error
+		})
+	}
+	fake.getCRDTStateRangeScanIteratorReturnsOnCall[i] = struct {
+		result1 ledgera.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) GetPrivateCRDTState(arg1 string, arg2 string, arg3 string) ([]byte, error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getPrivateCRDTStateReturnsOnCall[len(fake.getPrivateCRDTStateArgsForCall)]
+	fake.getPrivateCRDTStateArgsForCall = append(fake.getPrivateCRDTStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetPrivateCRDTStateStub
+	fakeReturns := fake.getPrivateCRDTStateReturns
+	fake.recordInvocation("GetPrivateCRDTState", []interface{}{arg1, arg2, arg3})
+	fake.getPrivateCRDTStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *TxSimulator) GetPrivateCRDTStateCallCount() int {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	return len(fake.getPrivateCRDTStateArgsForCall)
+}
+
+func (fake *TxSimulator) GetPrivateCRDTStateCalls(stub func(string, string, string) ([]byte, error)) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = stub
+}
+
+func (fake *TxSimulator) GetPrivateCRDTStateArgsForCall(i int) (string, string, string) {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	argsForCall := fake.getPrivateCRDTStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *TxSimulator) GetPrivateCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	fake.getPrivateCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) GetPrivateCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	if fake.getPrivateCRDTStateReturnsOnCall == nil {
+		fake.getPrivateCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *TxSimulator) GetPrivateData(arg1 string, arg2 string, arg3 string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -1876,12 +2222,86 @@ func (fake *TxSimulator) PurgePrivateDataReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *TxSimulator) SetCRDT(arg1 string, arg2 string, arg3 string, arg4 []byte) error {
+func (fake *TxSimulator) ResetCRDT(arg1 string, arg2 string, arg3 string, arg4 []byte) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.resetCRDTMutex.Lock()
+	ret, specificReturn := fake.resetCRDTReturnsOnCall[len(fake.resetCRDTArgsForCall)]
+	fake.resetCRDTArgsForCall = append(fake.resetCRDTArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []byte
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.ResetCRDTStub
+	fakeReturns := fake.resetCRDTReturns
+	fake.recordInvocation("ResetCRDT", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.resetCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *TxSimulator) ResetCRDTCallCount() int {
+	fake.resetCRDTMutex.RLock()
+	defer fake.resetCRDTMutex.RUnlock()
+	return len(fake.resetCRDTArgsForCall)
+}
+
+func (fake *TxSimulator) ResetCRDTCalls(stub func(string, string, string, []byte) error) {
+	fake.resetCRDTMutex.Lock()
+	defer fake.resetCRDTMutex.Unlock()
+	fake.ResetCRDTStub = stub
+}
+
+func (fake *TxSimulator) ResetCRDTArgsForCall(i int) (string, string, string, []byte) {
+	fake.resetCRDTMutex.RLock()
+	defer fake.resetCRDTMutex.RUnlock()
+	argsForCall := fake.resetCRDTArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *TxSimulator) ResetCRDTReturns(result1 error) {
+	fake.resetCRDTMutex.Lock()
+	defer fake.resetCRDTMutex.Unlock()
+	fake.ResetCRDTStub = nil
+	fake.resetCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TxSimulator) ResetCRDTReturnsOnCall(i int, result1 error) {
+	fake.resetCRDTMutex.Lock()
+	defer fake.resetCRDTMutex.Unlock()
+	fake.ResetCRDTStub = nil
+	if fake.resetCRDTReturnsOnCall == nil {
+		fake.resetCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resetCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TxSimulator) SetCRDT(arg1 string, arg2 string, arg3 string, arg4 []byte, arg5 []*kvrwset.CRDTPredicate) error {
 	var arg4Copy []byte
 	if arg4 != nil {
 		arg4Copy = make([]byte, len(arg4))
 		copy(arg4Copy, arg4)
 	}
+	var arg5Copy []*kvrwset.CRDTPredicate
+	if arg5 != nil {
+		arg5Copy = make([]*kvrwset.CRDTPredicate, len(arg5))
+		copy(arg5Copy, arg5)
+	}
 	fake.setCRDTMutex.Lock()
 	ret, specificReturn := fake.setCRDTReturnsOnCall[len(fake.setCRDTArgsForCall)]
 	fake.setCRDTArgsForCall = append(fake.setCRDTArgsForCall, struct {
@@ -1889,13 +2309,14 @@ func (fake *TxSimulator) SetCRDT(arg1 string, arg2 string, arg3 string, arg4 []b
 		arg2 string
 		arg3 string
 		arg4 []byte
-	}{arg1, arg2, arg3, arg4Copy})
+		arg5 []*kvrwset.CRDTPredicate
+	}{arg1, arg2, arg3, arg4Copy, arg5Copy})
 	stub := fake.SetCRDTStub
 	fakeReturns := fake.setCRDTReturns
-	fake.recordInvocation("SetCRDT", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.recordInvocation("SetCRDT", []interface{}{arg1, arg2, arg3, arg4Copy, arg5Copy})
 	fake.setCRDTMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1909,17 +2330,17 @@ func (fake *TxSimulator) SetCRDTCallCount() int {
 	return len(fake.setCRDTArgsForCall)
 }
 
-func (fake *TxSimulator) SetCRDTCalls(stub func(string, string, string, []byte) error) {
+func (fake *TxSimulator) SetCRDTCalls(stub func(string, string, string, []byte, []*kvrwset.CRDTPredicate) error) {
 	fake.setCRDTMutex.Lock()
 	defer fake.setCRDTMutex.Unlock()
 	fake.SetCRDTStub = stub
 }
 
-func (fake *TxSimulator) SetCRDTArgsForCall(i int) (string, string, string, []byte) {
+func (fake *TxSimulator) SetCRDTArgsForCall(i int) (string, string, string, []byte, []*kvrwset.CRDTPredicate) {
 	fake.setCRDTMutex.RLock()
 	defer fake.setCRDTMutex.RUnlock()
 	argsForCall := fake.setCRDTArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *TxSimulator) SetCRDTReturns(result1 error) {
@@ -1945,6 +2366,76 @@ func (fake *TxSimulator) SetCRDTReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *TxSimulator) SetPrivateCRDT(arg1 string, arg2 string, arg3 string, arg4 string, arg5 []byte) error {
+	var arg5Copy []byte
+	if arg5 != nil {
+		arg5Copy = make([]byte, len(arg5))
+		copy(arg5Copy, arg5)
+	}
+	fake.setPrivateCRDTMutex.Lock()
+	ret, specificReturn := fake.setPrivateCRDTReturnsOnCall[len(fake.setPrivateCRDTArgsForCall)]
+	fake.setPrivateCRDTArgsForCall = append(fake.setPrivateCRDTArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 []byte
+	}{arg1, arg2, arg3, arg4, arg5Copy})
+	stub := fake.SetPrivateCRDTStub
+	fakeReturns := fake.setPrivateCRDTReturns
+	fake.recordInvocation("SetPrivateCRDT", []interface{}{arg1, arg2, arg3, arg4, arg5Copy})
+	fake.setPrivateCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *TxSimulator) SetPrivateCRDTCallCount() int {
+	fake.setPrivateCRDTMutex.RLock()
+	defer fake.setPrivateCRDTMutex.RUnlock()
+	return len(fake.setPrivateCRDTArgsForCall)
+}
+
+func (fake *TxSimulator) SetPrivateCRDTCalls(stub func(string, string, string, string, []byte) error) {
+	fake.setPrivateCRDTMutex.Lock()
+	defer fake.setPrivateCRDTMutex.Unlock()
+	fake.SetPrivateCRDTStub = stub
+}
+
+func (fake *TxSimulator) SetPrivateCRDTArgsForCall(i int) (string, string, string, string, []byte) {
+	fake.setPrivateCRDTMutex.RLock()
+	defer fake.setPrivateCRDTMutex.RUnlock()
+	argsForCall := fake.setPrivateCRDTArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *TxSimulator) SetPrivateCRDTReturns(result1 error) {
+	fake.setPrivateCRDTMutex.Lock()
+	defer fake.setPrivateCRDTMutex.Unlock()
+	fake.SetPrivateCRDTStub = nil
+	fake.setPrivateCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TxSimulator) SetPrivateCRDTReturnsOnCall(i int, result1 error) {
+	fake.setPrivateCRDTMutex.Lock()
+	defer fake.setPrivateCRDTMutex.Unlock()
+	fake.SetPrivateCRDTStub = nil
+	if fake.setPrivateCRDTReturnsOnCall == nil {
+		fake.setPrivateCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setPrivateCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *TxSimulator) SetPrivateData(arg1 string, arg2 string, arg3 string, arg4 []byte) error {
 	var arg4Copy []byte
 	if arg4 != nil {
@@ -2337,6 +2828,8 @@ func (fake *TxSimulator) SetStateMultipleKeysReturnsOnCall(i int, result1 error)
 func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.deleteCRDTMutex.RLock()
+	defer fake.deleteCRDTMutex.RUnlock()
 	fake.deletePrivateDataMutex.RLock()
 	defer fake.deletePrivateDataMutex.RUnlock()
 	fake.deletePrivateDataMetadataMutex.RLock()
@@ -2347,6 +2840,8 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.deleteStateMetadataMutex.RUnlock()
 	fake.doneMutex.RLock()
 	defer fake.doneMutex.RUnlock()
+	fake.executeCRDTQueryMutex.RLock()
+	defer fake.executeCRDTQueryMutex.RUnlock()
 	fake.executeQueryMutex.RLock()
 	defer fake.executeQueryMutex.RUnlock()
 	fake.executeQueryOnPrivateDataMutex.RLock()
@@ -2357,6 +2852,10 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.executeUpdateMutex.RUnlock()
 	fake.getCRDTStateMutex.RLock()
 	defer fake.getCRDTStateMutex.RUnlock()
+	fake.getCRDTStateRangeScanIteratorMutex.RLock()
+	defer fake.getCRDTStateRangeScanIteratorMutex.RUnlock()
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.getPrivateDataHashMutex.RLock()
@@ -2383,8 +2882,12 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.getTxSimulationResultsMutex.RUnlock()
 	fake.purgePrivateDataMutex.RLock()
 	defer fake.purgePrivateDataMutex.RUnlock()
+	fake.resetCRDTMutex.RLock()
+	defer fake.resetCRDTMutex.RUnlock()
 	fake.setCRDTMutex.RLock()
 	defer fake.setCRDTMutex.RUnlock()
+	fake.setPrivateCRDTMutex.RLock()
+	defer fake.setPrivateCRDTMutex.RUnlock()
 	fake.setPrivateDataMutex.RLock()
 	defer fake.setPrivateDataMutex.RUnlock()
 	fake.setPrivateDataMetadataMutex.RLock()
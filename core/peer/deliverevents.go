@@ -228,6 +228,52 @@ func (bprs *blockAndPrivateDataResponseSender) getPrivateData(
 	return seqs2Namespaces.asPrivateDataMap(), nil
 }
 
+// blockAndCRDTValuesResponseSender structure used to send block and CRDT values responses
+type blockAndCRDTValuesResponseSender struct {
+	peer.Deliver_DeliverWithCRDTValuesServer
+}
+
+// SendStatusResponse generates status reply proto message
+func (bcrs *blockAndCRDTValuesResponseSender) SendStatusResponse(status common.Status) error {
+	reply := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_Status{Status: status},
+	}
+	return bcrs.Send(reply)
+}
+
+// SendBlockResponse gets the values of the CRDT keys merged by the transactions of the block
+// and generates deliver response with both block and CRDT values
+func (bcrs *blockAndCRDTValuesResponseSender) SendBlockResponse(
+	block *common.Block,
+	channelID string,
+	chain deliver.Chain,
+	signedData *protoutil.SignedData,
+) error {
+	channel, ok := chain.(Chain)
+	if !ok {
+		return errors.New("wrong chain type")
+	}
+
+	crdtValues, err := channel.Ledger().GetCRDTValuesByNum(block.Header.Number)
+	if err != nil {
+		logger.Errorf("Error getting CRDT values by block number %d on channel %s", block.Header.Number, channelID)
+		return errors.Wrapf(err, "error getting CRDT values by block number %d", block.Header.Number)
+	}
+
+	blockAndCRDTValues := &peer.BlockAndCRDTValues{
+		Block:         block,
+		CrdtValuesMap: crdtValues,
+	}
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_BlockAndCrdtValues{BlockAndCrdtValues: blockAndCRDTValues},
+	}
+	return bcrs.Send(response)
+}
+
+func (bcrs *blockAndCRDTValuesResponseSender) DataType() string {
+	return "block_and_crdt_values"
+}
+
 // transactionActions aliasing for peer.TransactionAction pointers slice
 type transactionActions []*peer.TransactionAction
 
@@ -289,6 +335,22 @@ func (s *DeliverServer) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPriva
 	return err
 }
 
+// DeliverWithCRDTValues sends a stream of blocks to a client after commitment, along with the values
+// the CRDT keys merged by the valid transactions of the blocks have right after each transaction
+func (s *DeliverServer) DeliverWithCRDTValues(srv peer.Deliver_DeliverWithCRDTValuesServer) (err error) {
+	logger.Debug("Starting new DeliverWithCRDTValues handler")
+	defer dumpStacktraceOnPanic()
+	// the CRDT values are part of the public state, hence the policy checker is the one of the blocks
+	deliverServer := &deliver.Server{
+		PolicyChecker: s.PolicyCheckerProvider(resources.Event_Block),
+		Receiver:      srv,
+		ResponseSender: &blockAndCRDTValuesResponseSender{
+			Deliver_DeliverWithCRDTValuesServer: srv,
+		},
+	}
+	return s.DeliverHandler.Handle(srv.Context(), deliverServer)
+}
+
 func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	filteredBlock := &peer.FilteredBlock{
 		Number: block.Header.Number,
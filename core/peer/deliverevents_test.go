@@ -583,6 +583,107 @@ func TestEventsServer_DeliverWithPrivateData(t *testing.T) {
 	}
 }
 
+func TestEventsServer_DeliverWithCRDTValues(t *testing.T) {
+	crdtValues := map[uint64]*peer.TxCRDTValues{
+		0: {
+			TxId: "testID",
+			Values: []*peer.CRDTValue{
+				{Namespace: "mycc", Key: "counter", Value: []byte("42")},
+				{Namespace: "mycc", Key: "gone", IsDelete: true},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		crdtValuesErr error
+		expectedErr   string
+	}{
+		{
+			name: "Testing deliver block with CRDT values",
+		},
+		{
+			name:          "Testing deliver block with failure to get CRDT values",
+			crdtValuesErr: fmt.Errorf("bookkeeping failure"),
+			expectedErr:   "error getting CRDT values by block number 0: bookkeeping failure",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			config := testConfig{
+				channelID:     "testChannelID",
+				eventName:     "testEvent",
+				chaincodeName: "mycc",
+				txID:          "testID",
+				payload: &common.Payload{
+					Header: &common.Header{
+						ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+							ChannelId: "testChannelID",
+							Timestamp: util.CreateUtcTimestamp(),
+						}),
+						SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{}),
+					},
+					Data: protoutil.MarshalOrPanic(&orderer.SeekInfo{
+						Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: 0}}},
+						Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+						Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+					}),
+				},
+				Assertions: require.New(t),
+			}
+
+			chaincodeActionPayload, err := createChaincodeAction(config.chaincodeName, config.eventName, config.txID)
+			require.NoError(t, err)
+			chainManager := createDefaultSupportMamangerMock(config, chaincodeActionPayload, nil)
+			ldgr := chainManager.GetChain(config.channelID).(*mockChainSupport).Ledger().(*fake.PeerLedger)
+			if test.crdtValuesErr != nil {
+				ldgr.GetCRDTValuesByNumReturns(nil, test.crdtValuesErr)
+			} else {
+				ldgr.GetCRDTValuesByNumReturns(crdtValues, nil)
+			}
+
+			var responses []*peer.DeliverResponse
+			p := &peer2.Peer{}
+			deliverServer := &mockDeliverServer{}
+			deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+			deliverServer.On("Recv").Return(&common.Envelope{
+				Payload: protoutil.MarshalOrPanic(config.payload),
+			}, nil).Run(func(_ mock.Arguments) {
+				// mock Recv calls to get io.EOF to stop the looping for next message
+				deliverServer.Mock = mock.Mock{}
+				deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+				deliverServer.On("Recv").Return(&common.Envelope{}, io.EOF)
+				deliverServer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+					responses = append(responses, args.Get(0).(*peer.DeliverResponse))
+				}).Return(nil)
+			})
+
+			metrics := deliver.NewMetrics(&disabled.Provider{})
+			handler := deliver.NewHandler(chainManager, time.Second, false, metrics, false)
+			server := &DeliverServer{
+				DeliverHandler:        handler,
+				PolicyCheckerProvider: defaultPolicyCheckerProvider,
+			}
+
+			err = server.DeliverWithCRDTValues(deliverServer)
+			require.Equal(t, 1, ldgr.GetCRDTValuesByNumCallCount())
+			require.Equal(t, uint64(0), ldgr.GetCRDTValuesByNumArgsForCall(0))
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				require.Empty(t, responses)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, responses, 2)
+			blockAndCRDTValues := responses[0].GetBlockAndCrdtValues()
+			require.NotNil(t, blockAndCRDTValues)
+			require.Equal(t, uint64(0), blockAndCRDTValues.Block.Header.Number)
+			require.True(t, proto.Equal(crdtValues[0], blockAndCRDTValues.CrdtValuesMap[0]))
+			require.Equal(t, common.Status_SUCCESS, responses[1].GetStatus())
+		})
+	}
+}
+
 func createDefaultSupportMamangerMock(config testConfig, chaincodeActionPayload *peer.ChaincodeActionPayload, pvtData []*ledger.TxPvtData) *mockChainManager {
 	chainManager := &mockChainManager{}
 	iter := &mockIterator{}
@@ -142,6 +142,19 @@ type PeerLedger struct {
 		result1 ledgera.ResultsIterator
 		result2 error
 	}
+	GetCRDTValuesByNumStub        func(uint64) (map[uint64]*peera.TxCRDTValues, error)
+	getCRDTValuesByNumMutex       sync.RWMutex
+	getCRDTValuesByNumArgsForCall []struct {
+		arg1 uint64
+	}
+	getCRDTValuesByNumReturns struct {
+		result1 map[uint64]*peera.TxCRDTValues
+		result2 error
+	}
+	getCRDTValuesByNumReturnsOnCall map[int]struct {
+		result1 map[uint64]*peera.TxCRDTValues
+		result2 error
+	}
 	GetConfigHistoryRetrieverStub        func() (ledger.ConfigHistoryRetriever, error)
 	getConfigHistoryRetrieverMutex       sync.RWMutex
 	getConfigHistoryRetrieverArgsForCall []struct {
@@ -950,6 +963,69 @@ func (fake *PeerLedger) GetBlocksIteratorReturnsOnCall(i int, result1 ledgera.Re
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetCRDTValuesByNum(arg1 uint64) (map[uint64]*peera.TxCRDTValues, error) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	ret, specificReturn := fake.getCRDTValuesByNumReturnsOnCall[len(fake.getCRDTValuesByNumArgsForCall)]
+	fake.getCRDTValuesByNumArgsForCall = append(fake.getCRDTValuesByNumArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	fake.recordInvocation("GetCRDTValuesByNum", []interface{}{arg1})
+	fake.getCRDTValuesByNumMutex.Unlock()
+	if fake.GetCRDTValuesByNumStub != nil {
+		return fake.GetCRDTValuesByNumStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCRDTValuesByNumReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetCRDTValuesByNumCallCount() int {
+	fake.getCRDTValuesByNumMutex.RLock()
+	defer fake.getCRDTValuesByNumMutex.RUnlock()
+	return len(fake.getCRDTValuesByNumArgsForCall)
+}
+
+func (fake *PeerLedger) GetCRDTValuesByNumCalls(stub func(uint64) (map[uint64]*peera.TxCRDTValues, error)) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	defer fake.getCRDTValuesByNumMutex.Unlock()
+	fake.GetCRDTValuesByNumStub = stub
+}
+
+func (fake *PeerLedger) GetCRDTValuesByNumArgsForCall(i int) uint64 {
+	fake.getCRDTValuesByNumMutex.RLock()
+	defer fake.getCRDTValuesByNumMutex.RUnlock()
+	argsForCall := fake.getCRDTValuesByNumArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PeerLedger) GetCRDTValuesByNumReturns(result1 map[uint64]*peera.TxCRDTValues, result2 error) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	defer fake.getCRDTValuesByNumMutex.Unlock()
+	fake.GetCRDTValuesByNumStub = nil
+	fake.getCRDTValuesByNumReturns = struct {
+		result1 map[uint64]*peera.TxCRDTValues
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetCRDTValuesByNumReturnsOnCall(i int, result1 map[uint64]*peera.TxCRDTValues, result2 error) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	defer fake.getCRDTValuesByNumMutex.Unlock()
+	fake.GetCRDTValuesByNumStub = nil
+	if fake.getCRDTValuesByNumReturnsOnCall == nil {
+		fake.getCRDTValuesByNumReturnsOnCall = make(map[int]struct {
+			result1 map[uint64]*peera.TxCRDTValues
+			result2 error
+		})
+	}
+	fake.getCRDTValuesByNumReturnsOnCall[i] = struct {
+		result1 map[uint64]*peera.TxCRDTValues
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetConfigHistoryRetriever() (ledger.ConfigHistoryRetriever, error) {
 	fake.getConfigHistoryRetrieverMutex.Lock()
 	ret, specificReturn := fake.getConfigHistoryRetrieverReturnsOnCall[len(fake.getConfigHistoryRetrieverArgsForCall)]
@@ -1693,6 +1769,8 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.getBlockchainInfoMutex.RUnlock()
 	fake.getBlocksIteratorMutex.RLock()
 	defer fake.getBlocksIteratorMutex.RUnlock()
+	fake.getCRDTValuesByNumMutex.RLock()
+	defer fake.getCRDTValuesByNumMutex.RUnlock()
 	fake.getConfigHistoryRetrieverMutex.RLock()
 	defer fake.getConfigHistoryRetrieverMutex.RUnlock()
 	fake.getMissingPvtDataTrackerMutex.RLock()
@@ -5,11 +5,26 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type ChaincodeStub struct {
+	CRDTSetContainsStub        func(string, string) (bool, error)
+	cRDTSetContainsMutex       sync.RWMutex
+	cRDTSetContainsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	cRDTSetContainsReturns struct {
+		result1 bool
+		result2 error
+	}
+	cRDTSetContainsReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	CreateCompositeKeyStub        func(string, []string) (string, error)
 	createCompositeKeyMutex       sync.RWMutex
 	createCompositeKeyArgsForCall []struct {
@@ -47,6 +62,17 @@ type ChaincodeStub struct {
 	delStateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteCRDTStub        func(string) error
+	deleteCRDTMutex       sync.RWMutex
+	deleteCRDTArgsForCall []struct {
+		arg1 string
+	}
+	deleteCRDTReturns struct {
+		result1 error
+	}
+	deleteCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetArgsStub        func() [][]byte
 	getArgsMutex       sync.RWMutex
 	getArgsArgsForCall []struct {
@@ -81,6 +107,60 @@ type ChaincodeStub struct {
 		result1 []byte
 		result2 error
 	}
+	GetCRDTQueryResultStub        func(string) (shim.StateQueryIteratorInterface, error)
+	getCRDTQueryResultMutex       sync.RWMutex
+	getCRDTQueryResultArgsForCall []struct {
+		arg1 string
+	}
+	getCRDTQueryResultReturns struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	getCRDTQueryResultReturnsOnCall map[int]struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	GetCRDTStateStub        func(string) ([]byte, error)
+	getCRDTStateMutex       sync.RWMutex
+	getCRDTStateArgsForCall []struct {
+		arg1 string
+	}
+	getCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetCRDTStateByPartialCompositeKeyStub        func(string, []string) (shim.StateQueryIteratorInterface, error)
+	getCRDTStateByPartialCompositeKeyMutex       sync.RWMutex
+	getCRDTStateByPartialCompositeKeyArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	getCRDTStateByPartialCompositeKeyReturns struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	getCRDTStateByPartialCompositeKeyReturnsOnCall map[int]struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	GetCRDTStateByRangeStub        func(string, string) (shim.StateQueryIteratorInterface, error)
+	getCRDTStateByRangeMutex       sync.RWMutex
+	getCRDTStateByRangeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getCRDTStateByRangeReturns struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	getCRDTStateByRangeReturnsOnCall map[int]struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
 	GetChannelIDStub        func() string
 	getChannelIDMutex       sync.RWMutex
 	getChannelIDArgsForCall []struct {
@@ -138,6 +218,20 @@ type ChaincodeStub struct {
 		result1 shim.HistoryQueryIteratorInterface
 		result2 error
 	}
+	GetPrivateCRDTStateStub        func(string, string) ([]byte, error)
+	getPrivateCRDTStateMutex       sync.RWMutex
+	getPrivateCRDTStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getPrivateCRDTStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateCRDTStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetPrivateDataStub        func(string, string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -425,6 +519,47 @@ type ChaincodeStub struct {
 	purgePrivateDataReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PutCRDTStub        func(string, string, []byte) error
+	putCRDTMutex       sync.RWMutex
+	putCRDTArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}
+	putCRDTReturns struct {
+		result1 error
+	}
+	putCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PutCRDTIfStub        func(string, string, []byte, []*kvrwset.CRDTPredicate) error
+	putCRDTIfMutex       sync.RWMutex
+	putCRDTIfArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+		arg4 []*kvrwset.CRDTPredicate
+	}
+	putCRDTIfReturns struct {
+		result1 error
+	}
+	putCRDTIfReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PutPrivateCRDTStub        func(string, string, string, []byte) error
+	putPrivateCRDTMutex       sync.RWMutex
+	putPrivateCRDTArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []byte
+	}
+	putPrivateCRDTReturns struct {
+		result1 error
+	}
+	putPrivateCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PutPrivateDataStub        func(string, string, []byte) error
 	putPrivateDataMutex       sync.RWMutex
 	putPrivateDataArgsForCall []struct {
@@ -450,6 +585,19 @@ type ChaincodeStub struct {
 	putStateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ResetCRDTStub        func(string, string, []byte) error
+	resetCRDTMutex       sync.RWMutex
+	resetCRDTArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}
+	resetCRDTReturns struct {
+		result1 error
+	}
+	resetCRDTReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetEventStub        func(string, []byte) error
 	setEventMutex       sync.RWMutex
 	setEventArgsForCall []struct {
@@ -506,6 +654,71 @@ type ChaincodeStub struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ChaincodeStub) CRDTSetContains(arg1 string, arg2 string) (bool, error) {
+	fake.cRDTSetContainsMutex.Lock()
+	ret, specificReturn := fake.cRDTSetContainsReturnsOnCall[len(fake.cRDTSetContainsArgsForCall)]
+	fake.cRDTSetContainsArgsForCall = append(fake.cRDTSetContainsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CRDTSetContainsStub
+	fakeReturns := fake.cRDTSetContainsReturns
+	fake.recordInvocation("CRDTSetContains", []interface{}{arg1, arg2})
+	fake.cRDTSetContainsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) CRDTSetContainsCallCount() int {
+	fake.cRDTSetContainsMutex.RLock()
+	defer fake.cRDTSetContainsMutex.RUnlock()
+	return len(fake.cRDTSetContainsArgsForCall)
+}
+
+func (fake *ChaincodeStub) CRDTSetContainsCalls(stub func(string, string) (bool, error)) {
+	fake.cRDTSetContainsMutex.Lock()
+	defer fake.cRDTSetContainsMutex.Unlock()
+	fake.CRDTSetContainsStub = stub
+}
+
+func (fake *ChaincodeStub) CRDTSetContainsArgsForCall(i int) (string, string) {
+	fake.cRDTSetContainsMutex.RLock()
+	defer fake.cRDTSetContainsMutex.RUnlock()
+	argsForCall := fake.cRDTSetContainsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) CRDTSetContainsReturns(result1 bool, result2 error) {
+	fake.cRDTSetContainsMutex.Lock()
+	defer fake.cRDTSetContainsMutex.Unlock()
+	fake.CRDTSetContainsStub = nil
+	fake.cRDTSetContainsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) CRDTSetContainsReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.cRDTSetContainsMutex.Lock()
+	defer fake.cRDTSetContainsMutex.Unlock()
+	fake.CRDTSetContainsStub = nil
+	if fake.cRDTSetContainsReturnsOnCall == nil {
+		fake.cRDTSetContainsReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.cRDTSetContainsReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) CreateCompositeKey(arg1 string, arg2 []string) (string, error) {
 	var arg2Copy []string
 	if arg2 != nil {
@@ -699,6 +912,67 @@ func (fake *ChaincodeStub) DelStateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ChaincodeStub) DeleteCRDT(arg1 string) error {
+	fake.deleteCRDTMutex.Lock()
+	ret, specificReturn := fake.deleteCRDTReturnsOnCall[len(fake.deleteCRDTArgsForCall)]
+	fake.deleteCRDTArgsForCall = append(fake.deleteCRDTArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DeleteCRDTStub
+	fakeReturns := fake.deleteCRDTReturns
+	fake.recordInvocation("DeleteCRDT", []interface{}{arg1})
+	fake.deleteCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChaincodeStub) DeleteCRDTCallCount() int {
+	fake.deleteCRDTMutex.RLock()
+	defer fake.deleteCRDTMutex.RUnlock()
+	return len(fake.deleteCRDTArgsForCall)
+}
+
+func (fake *ChaincodeStub) DeleteCRDTCalls(stub func(string) error) {
+	fake.deleteCRDTMutex.Lock()
+	defer fake.deleteCRDTMutex.Unlock()
+	fake.DeleteCRDTStub = stub
+}
+
+func (fake *ChaincodeStub) DeleteCRDTArgsForCall(i int) string {
+	fake.deleteCRDTMutex.RLock()
+	defer fake.deleteCRDTMutex.RUnlock()
+	argsForCall := fake.deleteCRDTArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChaincodeStub) DeleteCRDTReturns(result1 error) {
+	fake.deleteCRDTMutex.Lock()
+	defer fake.deleteCRDTMutex.Unlock()
+	fake.DeleteCRDTStub = nil
+	fake.deleteCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) DeleteCRDTReturnsOnCall(i int, result1 error) {
+	fake.deleteCRDTMutex.Lock()
+	defer fake.deleteCRDTMutex.Unlock()
+	fake.DeleteCRDTStub = nil
+	if fake.deleteCRDTReturnsOnCall == nil {
+		fake.deleteCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChaincodeStub) GetArgs() [][]byte {
 	fake.getArgsMutex.Lock()
 	ret, specificReturn := fake.getArgsReturnsOnCall[len(fake.getArgsArgsForCall)]
@@ -864,6 +1138,269 @@ func (fake *ChaincodeStub) GetBindingReturnsOnCall(i int, result1 []byte, result
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetCRDTQueryResult(arg1 string) (shim.StateQueryIteratorInterface, error) {
+	fake.getCRDTQueryResultMutex.Lock()
+	ret, specificReturn := fake.getCRDTQueryResultReturnsOnCall[len(fake.getCRDTQueryResultArgsForCall)]
+	fake.getCRDTQueryResultArgsForCall = append(fake.getCRDTQueryResultArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetCRDTQueryResultStub
+	fakeReturns := fake.getCRDTQueryResultReturns
+	fake.recordInvocation("GetCRDTQueryResult", []interface{}{arg1})
+	fake.getCRDTQueryResultMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetCRDTQueryResultCallCount() int {
+	fake.getCRDTQueryResultMutex.RLock()
+	defer fake.getCRDTQueryResultMutex.RUnlock()
+	return len(fake.getCRDTQueryResultArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetCRDTQueryResultCalls(stub func(string) (shim.StateQueryIteratorInterface, error)) {
+	fake.getCRDTQueryResultMutex.Lock()
+	defer fake.getCRDTQueryResultMutex.Unlock()
+	fake.GetCRDTQueryResultStub = stub
+}
+
+func (fake *ChaincodeStub) GetCRDTQueryResultArgsForCall(i int) string {
+	fake.getCRDTQueryResultMutex.RLock()
+	defer fake.getCRDTQueryResultMutex.RUnlock()
+	argsForCall := fake.getCRDTQueryResultArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChaincodeStub) GetCRDTQueryResultReturns(result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getCRDTQueryResultMutex.Lock()
+	defer fake.getCRDTQueryResultMutex.Unlock()
+	fake.GetCRDTQueryResultStub = nil
+	fake.getCRDTQueryResultReturns = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTQueryResultReturnsOnCall(i int, result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getCRDTQueryResultMutex.Lock()
+	defer fake.getCRDTQueryResultMutex.Unlock()
+	fake.GetCRDTQueryResultStub = nil
+	if fake.getCRDTQueryResultReturnsOnCall == nil {
+		fake.getCRDTQueryResultReturnsOnCall = make(map[int]struct {
+			result1 shim.StateQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getCRDTQueryResultReturnsOnCall[i] = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTState(arg1 string) ([]byte, error) {
+	fake.getCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateReturnsOnCall[len(fake.getCRDTStateArgsForCall)]
+	fake.getCRDTStateArgsForCall = append(fake.getCRDTStateArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetCRDTStateStub
+	fakeReturns := fake.getCRDTStateReturns
+	fake.recordInvocation("GetCRDTState", []interface{}{arg1})
+	fake.getCRDTStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetCRDTStateCallCount() int {
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	return len(fake.getCRDTStateArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetCRDTStateCalls(stub func(string) ([]byte, error)) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = stub
+}
+
+func (fake *ChaincodeStub) GetCRDTStateArgsForCall(i int) string {
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	argsForCall := fake.getCRDTStateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChaincodeStub) GetCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = nil
+	fake.getCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getCRDTStateMutex.Lock()
+	defer fake.getCRDTStateMutex.Unlock()
+	fake.GetCRDTStateStub = nil
+	if fake.getCRDTStateReturnsOnCall == nil {
+		fake.getCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByPartialCompositeKey(arg1 string, arg2 []string) (shim.StateQueryIteratorInterface, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.getCRDTStateByPartialCompositeKeyMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateByPartialCompositeKeyReturnsOnCall[len(fake.getCRDTStateByPartialCompositeKeyArgsForCall)]
+	fake.getCRDTStateByPartialCompositeKeyArgsForCall = append(fake.getCRDTStateByPartialCompositeKeyArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.GetCRDTStateByPartialCompositeKeyStub
+	fakeReturns := fake.getCRDTStateByPartialCompositeKeyReturns
+	fake.recordInvocation("GetCRDTStateByPartialCompositeKey", []interface{}{arg1, arg2Copy})
+	fake.getCRDTStateByPartialCompositeKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByPartialCompositeKeyCallCount() int {
+	fake.getCRDTStateByPartialCompositeKeyMutex.RLock()
+	defer fake.getCRDTStateByPartialCompositeKeyMutex.RUnlock()
+	return len(fake.getCRDTStateByPartialCompositeKeyArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByPartialCompositeKeyCalls(stub func(string, []string) (shim.StateQueryIteratorInterface, error)) {
+	fake.getCRDTStateByPartialCompositeKeyMutex.Lock()
+	defer fake.getCRDTStateByPartialCompositeKeyMutex.Unlock()
+	fake.GetCRDTStateByPartialCompositeKeyStub = stub
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByPartialCompositeKeyArgsForCall(i int) (string, []string) {
+	fake.getCRDTStateByPartialCompositeKeyMutex.RLock()
+	defer fake.getCRDTStateByPartialCompositeKeyMutex.RUnlock()
+	argsForCall := fake.getCRDTStateByPartialCompositeKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByPartialCompositeKeyReturns(result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getCRDTStateByPartialCompositeKeyMutex.Lock()
+	defer fake.getCRDTStateByPartialCompositeKeyMutex.Unlock()
+	fake.GetCRDTStateByPartialCompositeKeyStub = nil
+	fake.getCRDTStateByPartialCompositeKeyReturns = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByPartialCompositeKeyReturnsOnCall(i int, result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getCRDTStateByPartialCompositeKeyMutex.Lock()
+	defer fake.getCRDTStateByPartialCompositeKeyMutex.Unlock()
+	fake.GetCRDTStateByPartialCompositeKeyStub = nil
+	if fake.getCRDTStateByPartialCompositeKeyReturnsOnCall == nil {
+		fake.getCRDTStateByPartialCompositeKeyReturnsOnCall = make(map[int]struct {
+			result1 shim.StateQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getCRDTStateByPartialCompositeKeyReturnsOnCall[i] = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByRange(arg1 string, arg2 string) (shim.StateQueryIteratorInterface, error) {
+	fake.getCRDTStateByRangeMutex.Lock()
+	ret, specificReturn := fake.getCRDTStateByRangeReturnsOnCall[len(fake.getCRDTStateByRangeArgsForCall)]
+	fake.getCRDTStateByRangeArgsForCall = append(fake.getCRDTStateByRangeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetCRDTStateByRangeStub
+	fakeReturns := fake.getCRDTStateByRangeReturns
+	fake.recordInvocation("GetCRDTStateByRange", []interface{}{arg1, arg2})
+	fake.getCRDTStateByRangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByRangeCallCount() int {
+	fake.getCRDTStateByRangeMutex.RLock()
+	defer fake.getCRDTStateByRangeMutex.RUnlock()
+	return len(fake.getCRDTStateByRangeArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByRangeCalls(stub func(string, string) (shim.StateQueryIteratorInterface, error)) {
+	fake.getCRDTStateByRangeMutex.Lock()
+	defer fake.getCRDTStateByRangeMutex.Unlock()
+	fake.GetCRDTStateByRangeStub = stub
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByRangeArgsForCall(i int) (string, string) {
+	fake.getCRDTStateByRangeMutex.RLock()
+	defer fake.getCRDTStateByRangeMutex.RUnlock()
+	argsForCall := fake.getCRDTStateByRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByRangeReturns(result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getCRDTStateByRangeMutex.Lock()
+	defer fake.getCRDTStateByRangeMutex.Unlock()
+	fake.GetCRDTStateByRangeStub = nil
+	fake.getCRDTStateByRangeReturns = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetCRDTStateByRangeReturnsOnCall(i int, result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getCRDTStateByRangeMutex.Lock()
+	defer fake.getCRDTStateByRangeMutex.Unlock()
+	fake.GetCRDTStateByRangeStub = nil
+	if fake.getCRDTStateByRangeReturnsOnCall == nil {
+		fake.getCRDTStateByRangeReturnsOnCall = make(map[int]struct {
+			result1 shim.StateQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getCRDTStateByRangeReturnsOnCall[i] = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetChannelID() string {
 	fake.getChannelIDMutex.Lock()
 	ret, specificReturn := fake.getChannelIDReturnsOnCall[len(fake.getChannelIDArgsForCall)]
@@ -1146,6 +1683,71 @@ func (fake *ChaincodeStub) GetHistoryForKeyReturnsOnCall(i int, result1 shim.His
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetPrivateCRDTState(arg1 string, arg2 string) ([]byte, error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	ret, specificReturn := fake.getPrivateCRDTStateReturnsOnCall[len(fake.getPrivateCRDTStateArgsForCall)]
+	fake.getPrivateCRDTStateArgsForCall = append(fake.getPrivateCRDTStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetPrivateCRDTStateStub
+	fakeReturns := fake.getPrivateCRDTStateReturns
+	fake.recordInvocation("GetPrivateCRDTState", []interface{}{arg1, arg2})
+	fake.getPrivateCRDTStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetPrivateCRDTStateCallCount() int {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	return len(fake.getPrivateCRDTStateArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetPrivateCRDTStateCalls(stub func(string, string) ([]byte, error)) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = stub
+}
+
+func (fake *ChaincodeStub) GetPrivateCRDTStateArgsForCall(i int) (string, string) {
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
+	argsForCall := fake.getPrivateCRDTStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetPrivateCRDTStateReturns(result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	fake.getPrivateCRDTStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateCRDTStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getPrivateCRDTStateMutex.Lock()
+	defer fake.getPrivateCRDTStateMutex.Unlock()
+	fake.GetPrivateCRDTStateStub = nil
+	if fake.getPrivateCRDTStateReturnsOnCall == nil {
+		fake.getPrivateCRDTStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateCRDTStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetPrivateData(arg1 string, arg2 string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -2488,6 +3090,217 @@ func (fake *ChaincodeStub) PurgePrivateDataReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ChaincodeStub) PutCRDT(arg1 string, arg2 string, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.putCRDTMutex.Lock()
+	ret, specificReturn := fake.putCRDTReturnsOnCall[len(fake.putCRDTArgsForCall)]
+	fake.putCRDTArgsForCall = append(fake.putCRDTArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.PutCRDTStub
+	fakeReturns := fake.putCRDTReturns
+	fake.recordInvocation("PutCRDT", []interface{}{arg1, arg2, arg3Copy})
+	fake.putCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChaincodeStub) PutCRDTCallCount() int {
+	fake.putCRDTMutex.RLock()
+	defer fake.putCRDTMutex.RUnlock()
+	return len(fake.putCRDTArgsForCall)
+}
+
+func (fake *ChaincodeStub) PutCRDTCalls(stub func(string, string, []byte) error) {
+	fake.putCRDTMutex.Lock()
+	defer fake.putCRDTMutex.Unlock()
+	fake.PutCRDTStub = stub
+}
+
+func (fake *ChaincodeStub) PutCRDTArgsForCall(i int) (string, string, []byte) {
+	fake.putCRDTMutex.RLock()
+	defer fake.putCRDTMutex.RUnlock()
+	argsForCall := fake.putCRDTArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChaincodeStub) PutCRDTReturns(result1 error) {
+	fake.putCRDTMutex.Lock()
+	defer fake.putCRDTMutex.Unlock()
+	fake.PutCRDTStub = nil
+	fake.putCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PutCRDTReturnsOnCall(i int, result1 error) {
+	fake.putCRDTMutex.Lock()
+	defer fake.putCRDTMutex.Unlock()
+	fake.PutCRDTStub = nil
+	if fake.putCRDTReturnsOnCall == nil {
+		fake.putCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PutCRDTIf(arg1 string, arg2 string, arg3 []byte, arg4 []*kvrwset.CRDTPredicate) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	var arg4Copy []*kvrwset.CRDTPredicate
+	if arg4 != nil {
+		arg4Copy = make([]*kvrwset.CRDTPredicate, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.putCRDTIfMutex.Lock()
+	ret, specificReturn := fake.putCRDTIfReturnsOnCall[len(fake.putCRDTIfArgsForCall)]
+	fake.putCRDTIfArgsForCall = append(fake.putCRDTIfArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+		arg4 []*kvrwset.CRDTPredicate
+	}{arg1, arg2, arg3Copy, arg4Copy})
+	stub := fake.PutCRDTIfStub
+	fakeReturns := fake.putCRDTIfReturns
+	fake.recordInvocation("PutCRDTIf", []interface{}{arg1, arg2, arg3Copy, arg4Copy})
+	fake.putCRDTIfMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChaincodeStub) PutCRDTIfCallCount() int {
+	fake.putCRDTIfMutex.RLock()
+	defer fake.putCRDTIfMutex.RUnlock()
+	return len(fake.putCRDTIfArgsForCall)
+}
+
+func (fake *ChaincodeStub) PutCRDTIfCalls(stub func(string, string, []byte, []*kvrwset.CRDTPredicate) error) {
+	fake.putCRDTIfMutex.Lock()
+	defer fake.putCRDTIfMutex.Unlock()
+	fake.PutCRDTIfStub = stub
+}
+
+func (fake *ChaincodeStub) PutCRDTIfArgsForCall(i int) (string, string, []byte, []*kvrwset.CRDTPredicate) {
+	fake.putCRDTIfMutex.RLock()
+	defer fake.putCRDTIfMutex.RUnlock()
+	argsForCall := fake.putCRDTIfArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *ChaincodeStub) PutCRDTIfReturns(result1 error) {
+	fake.putCRDTIfMutex.Lock()
+	defer fake.putCRDTIfMutex.Unlock()
+	fake.PutCRDTIfStub = nil
+	fake.putCRDTIfReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PutCRDTIfReturnsOnCall(i int, result1 error) {
+	fake.putCRDTIfMutex.Lock()
+	defer fake.putCRDTIfMutex.Unlock()
+	fake.PutCRDTIfStub = nil
+	if fake.putCRDTIfReturnsOnCall == nil {
+		fake.putCRDTIfReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putCRDTIfReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PutPrivateCRDT(arg1 string, arg2 string, arg3 string, arg4 []byte) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.putPrivateCRDTMutex.Lock()
+	ret, specificReturn := fake.putPrivateCRDTReturnsOnCall[len(fake.putPrivateCRDTArgsForCall)]
+	fake.putPrivateCRDTArgsForCall = append(fake.putPrivateCRDTArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []byte
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.PutPrivateCRDTStub
+	fakeReturns := fake.putPrivateCRDTReturns
+	fake.recordInvocation("PutPrivateCRDT", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.putPrivateCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChaincodeStub) PutPrivateCRDTCallCount() int {
+	fake.putPrivateCRDTMutex.RLock()
+	defer fake.putPrivateCRDTMutex.RUnlock()
+	return len(fake.putPrivateCRDTArgsForCall)
+}
+
+func (fake *ChaincodeStub) PutPrivateCRDTCalls(stub func(string, string, string, []byte) error) {
+	fake.putPrivateCRDTMutex.Lock()
+	defer fake.putPrivateCRDTMutex.Unlock()
+	fake.PutPrivateCRDTStub = stub
+}
+
+func (fake *ChaincodeStub) PutPrivateCRDTArgsForCall(i int) (string, string, string, []byte) {
+	fake.putPrivateCRDTMutex.RLock()
+	defer fake.putPrivateCRDTMutex.RUnlock()
+	argsForCall := fake.putPrivateCRDTArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *ChaincodeStub) PutPrivateCRDTReturns(result1 error) {
+	fake.putPrivateCRDTMutex.Lock()
+	defer fake.putPrivateCRDTMutex.Unlock()
+	fake.PutPrivateCRDTStub = nil
+	fake.putPrivateCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PutPrivateCRDTReturnsOnCall(i int, result1 error) {
+	fake.putPrivateCRDTMutex.Lock()
+	defer fake.putPrivateCRDTMutex.Unlock()
+	fake.PutPrivateCRDTStub = nil
+	if fake.putPrivateCRDTReturnsOnCall == nil {
+		fake.putPrivateCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putPrivateCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChaincodeStub) PutPrivateData(arg1 string, arg2 string, arg3 []byte) error {
 	var arg3Copy []byte
 	if arg3 != nil {
@@ -2623,6 +3436,74 @@ func (fake *ChaincodeStub) PutStateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ChaincodeStub) ResetCRDT(arg1 string, arg2 string, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.resetCRDTMutex.Lock()
+	ret, specificReturn := fake.resetCRDTReturnsOnCall[len(fake.resetCRDTArgsForCall)]
+	fake.resetCRDTArgsForCall = append(fake.resetCRDTArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.ResetCRDTStub
+	fakeReturns := fake.resetCRDTReturns
+	fake.recordInvocation("ResetCRDT", []interface{}{arg1, arg2, arg3Copy})
+	fake.resetCRDTMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChaincodeStub) ResetCRDTCallCount() int {
+	fake.resetCRDTMutex.RLock()
+	defer fake.resetCRDTMutex.RUnlock()
+	return len(fake.resetCRDTArgsForCall)
+}
+
+func (fake *ChaincodeStub) ResetCRDTCalls(stub func(string, string, []byte) error) {
+	fake.resetCRDTMutex.Lock()
+	defer fake.resetCRDTMutex.Unlock()
+	fake.ResetCRDTStub = stub
+}
+
+func (fake *ChaincodeStub) ResetCRDTArgsForCall(i int) (string, string, []byte) {
+	fake.resetCRDTMutex.RLock()
+	defer fake.resetCRDTMutex.RUnlock()
+	argsForCall := fake.resetCRDTArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChaincodeStub) ResetCRDTReturns(result1 error) {
+	fake.resetCRDTMutex.Lock()
+	defer fake.resetCRDTMutex.Unlock()
+	fake.ResetCRDTStub = nil
+	fake.resetCRDTReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) ResetCRDTReturnsOnCall(i int, result1 error) {
+	fake.resetCRDTMutex.Lock()
+	defer fake.resetCRDTMutex.Unlock()
+	fake.ResetCRDTStub = nil
+	if fake.resetCRDTReturnsOnCall == nil {
+		fake.resetCRDTReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resetCRDTReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChaincodeStub) SetEvent(arg1 string, arg2 []byte) error {
 	var arg2Copy []byte
 	if arg2 != nil {
@@ -2895,18 +3776,30 @@ func (fake *ChaincodeStub) SplitCompositeKeyReturnsOnCall(i int, result1 string,
 func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cRDTSetContainsMutex.RLock()
+	defer fake.cRDTSetContainsMutex.RUnlock()
 	fake.createCompositeKeyMutex.RLock()
 	defer fake.createCompositeKeyMutex.RUnlock()
 	fake.delPrivateDataMutex.RLock()
 	defer fake.delPrivateDataMutex.RUnlock()
 	fake.delStateMutex.RLock()
 	defer fake.delStateMutex.RUnlock()
+	fake.deleteCRDTMutex.RLock()
+	defer fake.deleteCRDTMutex.RUnlock()
 	fake.getArgsMutex.RLock()
 	defer fake.getArgsMutex.RUnlock()
 	fake.getArgsSliceMutex.RLock()
 	defer fake.getArgsSliceMutex.RUnlock()
 	fake.getBindingMutex.RLock()
 	defer fake.getBindingMutex.RUnlock()
+	fake.getCRDTQueryResultMutex.RLock()
+	defer fake.getCRDTQueryResultMutex.RUnlock()
+	fake.getCRDTStateMutex.RLock()
+	defer fake.getCRDTStateMutex.RUnlock()
+	fake.getCRDTStateByPartialCompositeKeyMutex.RLock()
+	defer fake.getCRDTStateByPartialCompositeKeyMutex.RUnlock()
+	fake.getCRDTStateByRangeMutex.RLock()
+	defer fake.getCRDTStateByRangeMutex.RUnlock()
 	fake.getChannelIDMutex.RLock()
 	defer fake.getChannelIDMutex.RUnlock()
 	fake.getCreatorMutex.RLock()
@@ -2917,6 +3810,8 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getFunctionAndParametersMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getPrivateCRDTStateMutex.RLock()
+	defer fake.getPrivateCRDTStateMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.getPrivateDataByPartialCompositeKeyMutex.RLock()
@@ -2959,10 +3854,18 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.invokeChaincodeMutex.RUnlock()
 	fake.purgePrivateDataMutex.RLock()
 	defer fake.purgePrivateDataMutex.RUnlock()
+	fake.putCRDTMutex.RLock()
+	defer fake.putCRDTMutex.RUnlock()
+	fake.putCRDTIfMutex.RLock()
+	defer fake.putCRDTIfMutex.RUnlock()
+	fake.putPrivateCRDTMutex.RLock()
+	defer fake.putPrivateCRDTMutex.RUnlock()
 	fake.putPrivateDataMutex.RLock()
 	defer fake.putPrivateDataMutex.RUnlock()
 	fake.putStateMutex.RLock()
 	defer fake.putStateMutex.RUnlock()
+	fake.resetCRDTMutex.RLock()
+	defer fake.resetCRDTMutex.RUnlock()
 	fake.setEventMutex.RLock()
 	defer fake.setEventMutex.RUnlock()
 	fake.setPrivateDataValidationParameterMutex.RLock()
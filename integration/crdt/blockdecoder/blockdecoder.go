@@ -428,6 +428,7 @@ func unmarshalRWSet(b []byte) ChaincodeKVRWSet {
 			ResolutionType: v.ResolutionType,
 			Key:            v.Key,
 			Data:           v.Data,
+			Operation:      v.Operation.String(),
 		}
 
 		fmt.Println(crdtPayload[i])
@@ -176,4 +176,5 @@ type CRDTPayload struct {
 	ResolutionType string `json:"resolution_type"`
 	Data           []byte `json:"data"`
 	Key            string `json:"key"`
+	Operation      string `json:"operation"`
 }
@@ -399,6 +399,7 @@ type ChaincodeApproveForMyOrg struct {
 	ChannelConfigPolicy string
 	InitRequired        bool
 	CollectionsConfig   string
+	CRDTSchema          string
 	PeerAddresses       []string
 	WaitForEvent        bool
 	ClientAuth          bool
@@ -431,6 +432,10 @@ func (c ChaincodeApproveForMyOrg) Args() []string {
 		args = append(args, "--collections-config", c.CollectionsConfig)
 	}
 
+	if c.CRDTSchema != "" {
+		args = append(args, "--crdt-schema", c.CRDTSchema)
+	}
+
 	if c.ClientAuth {
 		args = append(args, "--clientauth")
 	}
@@ -483,6 +488,7 @@ type ChaincodeCheckCommitReadiness struct {
 	ChannelConfigPolicy string
 	InitRequired        bool
 	CollectionsConfig   string
+	CRDTSchema          string
 	PeerAddresses       []string
 	ClientAuth          bool
 }
@@ -513,6 +519,10 @@ func (c ChaincodeCheckCommitReadiness) Args() []string {
 		args = append(args, "--collections-config", c.CollectionsConfig)
 	}
 
+	if c.CRDTSchema != "" {
+		args = append(args, "--crdt-schema", c.CRDTSchema)
+	}
+
 	for _, p := range c.PeerAddresses {
 		args = append(args, "--peerAddresses", p)
 	}
@@ -535,6 +545,7 @@ type ChaincodeCommit struct {
 	ChannelConfigPolicy string
 	InitRequired        bool
 	CollectionsConfig   string
+	CRDTSchema          string
 	PeerAddresses       []string
 	WaitForEvent        bool
 	ClientAuth          bool
@@ -566,6 +577,10 @@ func (c ChaincodeCommit) Args() []string {
 	if c.CollectionsConfig != "" {
 		args = append(args, "--collections-config", c.CollectionsConfig)
 	}
+
+	if c.CRDTSchema != "" {
+		args = append(args, "--crdt-schema", c.CRDTSchema)
+	}
 	if c.ClientAuth {
 		args = append(args, "--clientauth")
 	}
@@ -36,6 +36,7 @@ type Chaincode struct {
 	Policy              string // only used for legacy lifecycle. For new lifecycle use SignaturePolicy
 	Lang                string
 	CollectionsConfig   string // optional
+	CRDTSchema          string // optional
 	PackageFile         string
 	PackageID           string            // if unspecified, chaincode won't be executable. Can use SetPackageIDFromPackageFile() to set.
 	CodeFiles           map[string]string // map from paths on the filesystem to code.tar.gz paths
@@ -251,6 +252,7 @@ func ApproveChaincodeForMyOrg(n *Network, channel string, orderer *Orderer, chai
 				ChannelConfigPolicy: chaincode.ChannelConfigPolicy,
 				InitRequired:        chaincode.InitRequired,
 				CollectionsConfig:   chaincode.CollectionsConfig,
+				CRDTSchema:          chaincode.CRDTSchema,
 				ClientAuth:          n.ClientAuthRequired,
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -306,6 +308,7 @@ func CommitChaincode(n *Network, channel string, orderer *Orderer, chaincode Cha
 		ChannelConfigPolicy: chaincode.ChannelConfigPolicy,
 		InitRequired:        chaincode.InitRequired,
 		CollectionsConfig:   chaincode.CollectionsConfig,
+		CRDTSchema:          chaincode.CRDTSchema,
 		PeerAddresses:       peerAddresses,
 		ClientAuth:          n.ClientAuthRequired,
 	})
@@ -496,6 +499,7 @@ func checkCommitReadiness(n *Network, peer *Peer, channel string, chaincode Chai
 			ChannelConfigPolicy: chaincode.ChannelConfigPolicy,
 			InitRequired:        chaincode.InitRequired,
 			CollectionsConfig:   chaincode.CollectionsConfig,
+			CRDTSchema:          chaincode.CRDTSchema,
 			ClientAuth:          n.ClientAuthRequired,
 		})
 		Expect(err).NotTo(HaveOccurred())
@@ -211,10 +211,11 @@ type Service struct {
 }
 
 type Handlers struct {
-	AuthFilters []Handler  `yaml:"authFilters,omitempty"`
-	Decorators  []Handler  `yaml:"decorators,omitempty"`
-	Endorsers   HandlerMap `yaml:"endorsers,omitempty"`
-	Validators  HandlerMap `yaml:"validators,omitempty"`
+	AuthFilters   []Handler      `yaml:"authFilters,omitempty"`
+	Decorators    []Handler      `yaml:"decorators,omitempty"`
+	Endorsers     HandlerMap     `yaml:"endorsers,omitempty"`
+	Validators    HandlerMap     `yaml:"validators,omitempty"`
+	CRDTResolvers []CRDTResolver `yaml:"crdtResolvers,omitempty"`
 }
 
 type Handler struct {
@@ -224,6 +225,12 @@ type Handler struct {
 
 type HandlerMap map[string]Handler
 
+type CRDTResolver struct {
+	ResolutionType string `yaml:"resolutionType,omitempty"`
+	Name           string `yaml:"name,omitempty"`
+	Library        string `yaml:"library,omitempty"`
+}
+
 type Discovery struct {
 	Enabled                      bool    `yaml:"enabled"`
 	AuthCacheEnabled             bool    `yaml:"authCacheEnabled"`
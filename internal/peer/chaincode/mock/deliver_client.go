@@ -38,6 +38,20 @@ type PeerDeliverClient struct {
 		result1 peer.Deliver_DeliverFilteredClient
 		result2 error
 	}
+	DeliverWithCRDTValuesStub        func(context.Context, ...grpc.CallOption) (peer.Deliver_DeliverWithCRDTValuesClient, error)
+	deliverWithCRDTValuesMutex       sync.RWMutex
+	deliverWithCRDTValuesArgsForCall []struct {
+		arg1 context.Context
+		arg2 []grpc.CallOption
+	}
+	deliverWithCRDTValuesReturns struct {
+		result1 peer.Deliver_DeliverWithCRDTValuesClient
+		result2 error
+	}
+	deliverWithCRDTValuesReturnsOnCall map[int]struct {
+		result1 peer.Deliver_DeliverWithCRDTValuesClient
+		result2 error
+	}
 	DeliverWithPrivateDataStub        func(context.Context, ...grpc.CallOption) (peer.Deliver_DeliverWithPrivateDataClient, error)
 	deliverWithPrivateDataMutex       sync.RWMutex
 	deliverWithPrivateDataArgsForCall []struct {
@@ -184,6 +198,70 @@ func (fake *PeerDeliverClient) DeliverFilteredReturnsOnCall(i int, result1 peer.
 	}{result1, result2}
 }
 
+func (fake *PeerDeliverClient) DeliverWithCRDTValues(arg1 context.Context, arg2 ...grpc.CallOption) (peer.Deliver_DeliverWithCRDTValuesClient, error) {
+	fake.deliverWithCRDTValuesMutex.Lock()
+	ret, specificReturn := fake.deliverWithCRDTValuesReturnsOnCall[len(fake.deliverWithCRDTValuesArgsForCall)]
+	fake.deliverWithCRDTValuesArgsForCall = append(fake.deliverWithCRDTValuesArgsForCall, struct {
+		arg1 context.Context
+		arg2 []grpc.CallOption
+	}{arg1, arg2})
+	fake.recordInvocation("DeliverWithCRDTValues", []interface{}{arg1, arg2})
+	fake.deliverWithCRDTValuesMutex.Unlock()
+	if fake.DeliverWithCRDTValuesStub != nil {
+		return fake.DeliverWithCRDTValuesStub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deliverWithCRDTValuesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerDeliverClient) DeliverWithCRDTValuesCallCount() int {
+	fake.deliverWithCRDTValuesMutex.RLock()
+	defer fake.deliverWithCRDTValuesMutex.RUnlock()
+	return len(fake.deliverWithCRDTValuesArgsForCall)
+}
+
+func (fake *PeerDeliverClient) DeliverWithCRDTValuesCalls(stub func(context.Context, ...grpc.CallOption) (peer.Deliver_DeliverWithCRDTValuesClient, error)) {
+	fake.deliverWithCRDTValuesMutex.Lock()
+	defer fake.deliverWithCRDTValuesMutex.Unlock()
+	fake.DeliverWithCRDTValuesStub = stub
+}
+
+func (fake *PeerDeliverClient) DeliverWithCRDTValuesArgsForCall(i int) (context.Context, []grpc.CallOption) {
+	fake.deliverWithCRDTValuesMutex.RLock()
+	defer fake.deliverWithCRDTValuesMutex.RUnlock()
+	argsForCall := fake.deliverWithCRDTValuesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PeerDeliverClient) DeliverWithCRDTValuesReturns(result1 peer.Deliver_DeliverWithCRDTValuesClient, result2 error) {
+	fake.deliverWithCRDTValuesMutex.Lock()
+	defer fake.deliverWithCRDTValuesMutex.Unlock()
+	fake.DeliverWithCRDTValuesStub = nil
+	fake.deliverWithCRDTValuesReturns = struct {
+		result1 peer.Deliver_DeliverWithCRDTValuesClient
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerDeliverClient) DeliverWithCRDTValuesReturnsOnCall(i int, result1 peer.Deliver_DeliverWithCRDTValuesClient, result2 error) {
+	fake.deliverWithCRDTValuesMutex.Lock()
+	defer fake.deliverWithCRDTValuesMutex.Unlock()
+	fake.DeliverWithCRDTValuesStub = nil
+	if fake.deliverWithCRDTValuesReturnsOnCall == nil {
+		fake.deliverWithCRDTValuesReturnsOnCall = make(map[int]struct {
+			result1 peer.Deliver_DeliverWithCRDTValuesClient
+			result2 error
+		})
+	}
+	fake.deliverWithCRDTValuesReturnsOnCall[i] = struct {
+		result1 peer.Deliver_DeliverWithCRDTValuesClient
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerDeliverClient) DeliverWithPrivateData(arg1 context.Context, arg2 ...grpc.CallOption) (peer.Deliver_DeliverWithPrivateDataClient, error) {
 	fake.deliverWithPrivateDataMutex.Lock()
 	ret, specificReturn := fake.deliverWithPrivateDataReturnsOnCall[len(fake.deliverWithPrivateDataArgsForCall)]
@@ -204,6 +282,8 @@ func (fake *PeerDeliverClient) DeliverWithPrivateData(arg1 context.Context, arg2
 }
 
 func (fake *PeerDeliverClient) DeliverWithPrivateDataCallCount() int {
+	fake.deliverWithCRDTValuesMutex.RLock()
+	defer fake.deliverWithCRDTValuesMutex.RUnlock()
 	fake.deliverWithPrivateDataMutex.RLock()
 	defer fake.deliverWithPrivateDataMutex.RUnlock()
 	return len(fake.deliverWithPrivateDataArgsForCall)
@@ -50,6 +50,7 @@ type ApproveForMyOrgInput struct {
 	ValidationPlugin         string
 	ValidationParameterBytes []byte
 	CollectionConfigPackage  *pb.CollectionConfigPackage
+	CRDTSchema               *lb.CRDTSchema
 	InitRequired             bool
 	PeerAddresses            []string
 	WaitForEvent             bool
@@ -138,6 +139,7 @@ func ApproveForMyOrgCmd(a *ApproverForMyOrg, cryptoProvider bccsp.BCCSP) *cobra.
 		"channel-config-policy",
 		"init-required",
 		"collections-config",
+		"crdt-schema",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
@@ -255,6 +257,11 @@ func (a *ApproverForMyOrg) createInput() (*ApproveForMyOrgInput, error) {
 		return nil, err
 	}
 
+	crdtSchema, err := createCRDTSchema(crdtSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &ApproveForMyOrgInput{
 		ChannelID:                channelID,
 		Name:                     chaincodeName,
@@ -266,6 +273,7 @@ func (a *ApproverForMyOrg) createInput() (*ApproveForMyOrgInput, error) {
 		ValidationParameterBytes: policyBytes,
 		InitRequired:             initRequired,
 		CollectionConfigPackage:  ccp,
+		CRDTSchema:               crdtSchema,
 		PeerAddresses:            peerAddresses,
 		WaitForEvent:             waitForEvent,
 		WaitForEventTimeout:      waitForEventTimeout,
@@ -305,6 +313,7 @@ func (a *ApproverForMyOrg) createProposal(inputTxID string) (proposal *pb.Propos
 		ValidationParameter: a.Input.ValidationParameterBytes,
 		InitRequired:        a.Input.InitRequired,
 		Collections:         a.Input.CollectionConfigPackage,
+		CrdtSchema:          a.Input.CRDTSchema,
 		Source:              ccsrc,
 	}
 
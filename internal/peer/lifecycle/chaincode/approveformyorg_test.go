@@ -9,12 +9,18 @@ package chaincode_test
 import (
 	"context"
 	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode"
 	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
@@ -78,6 +84,34 @@ var _ = Describe("ApproverForMyOrg", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		Context("when a CRDT schema is provided", func() {
+			BeforeEach(func() {
+				approver.Input.CRDTSchema = &lb.CRDTSchema{
+					Keys: []*lb.CRDTKeySchema{
+						{KeyPattern: "counter_*", ResolutionTypes: []string{"IntAdd"}},
+					},
+				}
+			})
+
+			It("includes the CRDT schema in the approved definition", func() {
+				err := approver.Approve()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(1))
+				_, signedProposal, _ := mockEndorserClient.ProcessProposalArgsForCall(0)
+				proposal, err := protoutil.UnmarshalProposal(signedProposal.ProposalBytes)
+				Expect(err).NotTo(HaveOccurred())
+				payload, err := protoutil.UnmarshalChaincodeProposalPayload(proposal.Payload)
+				Expect(err).NotTo(HaveOccurred())
+				cis, err := protoutil.UnmarshalChaincodeInvocationSpec(payload.Input)
+				Expect(err).NotTo(HaveOccurred())
+				args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+				err = proto.Unmarshal(cis.ChaincodeSpec.Input.Args[1], args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(args.CrdtSchema, approver.Input.CRDTSchema)).To(BeTrue())
+			})
+		})
+
 		Context("when the channel name is not provided", func() {
 			BeforeEach(func() {
 				approver.Input.ChannelID = ""
@@ -406,6 +440,59 @@ var _ = Describe("ApproverForMyOrg", func() {
 				Expect(err).To(MatchError("invalid collection configuration in file idontexist.json: could not read file 'idontexist.json': open idontexist.json: no such file or directory"))
 			})
 		})
+
+		Context("when the CRDT schema file does not exist", func() {
+			BeforeEach(func() {
+				approveForMyOrgCmd.SetArgs([]string{
+					"--crdt-schema=idontexist.json",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--package-id=testpackageid",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("returns an error", func() {
+				err := approveForMyOrgCmd.Execute()
+				Expect(err).To(MatchError("could not read CRDT schema file idontexist.json: open idontexist.json: no such file or directory"))
+			})
+		})
+
+		Context("when the CRDT schema is not valid JSON", func() {
+			var testDir string
+
+			BeforeEach(func() {
+				var err error
+				testDir, err = ioutil.TempDir("", "approveformyorg-test")
+				Expect(err).NotTo(HaveOccurred())
+				schemaFile := filepath.Join(testDir, "crdt.json")
+				err = ioutil.WriteFile(schemaFile, []byte(`{"keyPattern": "counter"}`), 0o644)
+				Expect(err).NotTo(HaveOccurred())
+
+				approveForMyOrgCmd.SetArgs([]string{
+					"--crdt-schema=" + schemaFile,
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--package-id=testpackageid",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(testDir)
+			})
+
+			It("returns an error", func() {
+				err := approveForMyOrgCmd.Execute()
+				Expect(err).To(MatchError(ContainSubstring("invalid CRDT schema in file")))
+			})
+		})
 	})
 })
 
@@ -60,6 +60,7 @@ var (
 	endorsementPlugin     string
 	validationPlugin      string
 	collectionsConfigFile string
+	crdtSchemaFile        string
 	peerAddresses         []string
 	tlsRootCertFiles      []string
 	connectionProfilePath string
@@ -104,6 +105,7 @@ func ResetFlags() {
 	flags.StringVarP(&endorsementPlugin, "endorsement-plugin", "E", "", "The name of the endorsement plugin to be used for this chaincode")
 	flags.StringVarP(&validationPlugin, "validation-plugin", "V", "", "The name of the validation plugin to be used for this chaincode")
 	flags.StringVar(&collectionsConfigFile, "collections-config", "", "The fully qualified path to the collection JSON file including the file name")
+	flags.StringVar(&crdtSchemaFile, "crdt-schema", "", "The fully qualified path to the CRDT schema JSON file including the file name")
 	flags.StringArrayVarP(&peerAddresses, "peerAddresses", "", []string{""}, "The addresses of the peers to connect to")
 	flags.StringArrayVarP(&tlsRootCertFiles, "tlsRootCertFiles", "", []string{""},
 		"If TLS is enabled, the paths to the TLS root cert files of the peers to connect to. The order and number of certs specified should match the --peerAddresses flag")
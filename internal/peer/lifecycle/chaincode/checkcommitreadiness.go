@@ -53,6 +53,7 @@ type CommitReadinessCheckInput struct {
 	ValidationPlugin         string
 	ValidationParameterBytes []byte
 	CollectionConfigPackage  *pb.CollectionConfigPackage
+	CRDTSchema               *lb.CRDTSchema
 	InitRequired             bool
 	PeerAddresses            []string
 	TxID                     string
@@ -133,6 +134,7 @@ func CheckCommitReadinessCmd(c *CommitReadinessChecker, cryptoProvider bccsp.BCC
 		"channel-config-policy",
 		"init-required",
 		"collections-config",
+		"crdt-schema",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
@@ -225,6 +227,11 @@ func (c *CommitReadinessChecker) createInput() (*CommitReadinessCheckInput, erro
 		return nil, err
 	}
 
+	crdtSchema, err := createCRDTSchema(crdtSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &CommitReadinessCheckInput{
 		ChannelID:                channelID,
 		Name:                     chaincodeName,
@@ -236,6 +243,7 @@ func (c *CommitReadinessChecker) createInput() (*CommitReadinessCheckInput, erro
 		ValidationParameterBytes: policyBytes,
 		InitRequired:             initRequired,
 		CollectionConfigPackage:  ccp,
+		CRDTSchema:               crdtSchema,
 		PeerAddresses:            peerAddresses,
 		OutputFormat:             output,
 	}
@@ -253,6 +261,7 @@ func (c *CommitReadinessChecker) createProposal(inputTxID string) (*pb.Proposal,
 		ValidationParameter: c.Input.ValidationParameterBytes,
 		InitRequired:        c.Input.InitRequired,
 		Collections:         c.Input.CollectionConfigPackage,
+		CrdtSchema:          c.Input.CRDTSchema,
 	}
 
 	argsBytes, err := proto.Marshal(args)
@@ -300,5 +300,24 @@ var _ = Describe("CheckCommitReadiness", func() {
 				Expect(err).To(MatchError("invalid collection configuration in file idontexist.json: could not read file 'idontexist.json': open idontexist.json: no such file or directory"))
 			})
 		})
+
+		Context("when the CRDT schema file does not exist", func() {
+			BeforeEach(func() {
+				checkCommitReadinessCmd.SetArgs([]string{
+					"--crdt-schema=idontexist.json",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("returns an error", func() {
+				err := checkCommitReadinessCmd.Execute()
+				Expect(err).To(MatchError("could not read CRDT schema file idontexist.json: open idontexist.json: no such file or directory"))
+			})
+		})
 	})
 })
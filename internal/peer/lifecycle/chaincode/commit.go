@@ -50,6 +50,7 @@ type CommitInput struct {
 	ValidationPlugin         string
 	ValidationParameterBytes []byte
 	CollectionConfigPackage  *pb.CollectionConfigPackage
+	CRDTSchema               *lb.CRDTSchema
 	InitRequired             bool
 	PeerAddresses            []string
 	WaitForEvent             bool
@@ -137,6 +138,7 @@ func CommitCmd(c *Committer, cryptoProvider bccsp.BCCSP) *cobra.Command {
 		"channel-config-policy",
 		"init-required",
 		"collections-config",
+		"crdt-schema",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
@@ -253,6 +255,11 @@ func (c *Committer) createInput() (*CommitInput, error) {
 		return nil, err
 	}
 
+	crdtSchema, err := createCRDTSchema(crdtSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &CommitInput{
 		ChannelID:                channelID,
 		Name:                     chaincodeName,
@@ -263,6 +270,7 @@ func (c *Committer) createInput() (*CommitInput, error) {
 		ValidationParameterBytes: policyBytes,
 		InitRequired:             initRequired,
 		CollectionConfigPackage:  ccp,
+		CRDTSchema:               crdtSchema,
 		PeerAddresses:            peerAddresses,
 		WaitForEvent:             waitForEvent,
 		WaitForEventTimeout:      waitForEventTimeout,
@@ -281,6 +289,7 @@ func (c *Committer) createProposal(inputTxID string) (proposal *pb.Proposal, txI
 		ValidationParameter: c.Input.ValidationParameterBytes,
 		InitRequired:        c.Input.InitRequired,
 		Collections:         c.Input.CollectionConfigPackage,
+		CrdtSchema:          c.Input.CRDTSchema,
 	}
 
 	argsBytes, err := proto.Marshal(args)
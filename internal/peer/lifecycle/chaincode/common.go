@@ -11,9 +11,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/internal/peer/chaincode"
 	"github.com/hyperledger/fabric/protoutil"
@@ -119,6 +121,35 @@ func createCollectionConfigPackage(collectionsConfigFile string) (*pb.Collection
 	return ccp, nil
 }
 
+// crdtKeySchemaJSON is the JSON representation of a key pattern of the CRDT schema,
+// e.g. [{"keyPattern": "counter_*", "resolutionTypes": ["IntAdd"]}]
+type crdtKeySchemaJSON struct {
+	KeyPattern      string   `json:"keyPattern"`
+	ResolutionTypes []string `json:"resolutionTypes"`
+}
+
+func createCRDTSchema(crdtSchemaFile string) (*lb.CRDTSchema, error) {
+	if crdtSchemaFile == "" {
+		return nil, nil
+	}
+	fileBytes, err := ioutil.ReadFile(crdtSchemaFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read CRDT schema file %s", crdtSchemaFile)
+	}
+	var keySchemas []crdtKeySchemaJSON
+	if err := json.Unmarshal(fileBytes, &keySchemas); err != nil {
+		return nil, errors.Wrapf(err, "invalid CRDT schema in file %s", crdtSchemaFile)
+	}
+	schema := &lb.CRDTSchema{}
+	for _, keySchema := range keySchemas {
+		schema.Keys = append(schema.Keys, &lb.CRDTKeySchema{
+			KeyPattern:      keySchema.KeyPattern,
+			ResolutionTypes: keySchema.ResolutionTypes,
+		})
+	}
+	return schema, nil
+}
+
 func printResponseAsJSON(proposalResponse *pb.ProposalResponse, msg proto.Message, out io.Writer) error {
 	err := proto.Unmarshal(proposalResponse.Response.Payload, msg)
 	if err != nil {
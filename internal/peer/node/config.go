@@ -66,6 +66,15 @@ func ledgerConfig() *ledger.Config {
 		SnapshotsConfig: &ledger.SnapshotsConfig{
 			RootDir: snapshotsRootDir,
 		},
+		CRDTConfig: &ledger.CRDTConfig{
+			MergeWorkers:        viper.GetInt("ledger.crdt.mergeWorkers"),
+			EnableTestResolvers: viper.GetBool("ledger.crdt.enableTestResolvers"),
+			ValuesRetention:     viper.GetUint64("ledger.crdt.valuesRetention"),
+			MaxValueSize:        viper.GetInt("ledger.crdt.maxValueSize"),
+			MaxMergeSteps:       viper.GetInt("ledger.crdt.maxMergeSteps"),
+			MaxBlockDiffSize:    viper.GetInt("ledger.crdt.maxBlockDiffSize"),
+			SlowMergeWarning:    viper.GetDuration("ledger.crdt.slowMergeWarning"),
+		},
 	}
 
 	if conf.StateDBConfig.StateDatabase == ledger.CouchDB {
@@ -46,6 +46,7 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
+				CRDTConfig: &ledger.CRDTConfig{},
 			},
 		},
 		{
@@ -92,6 +93,7 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
+				CRDTConfig: &ledger.CRDTConfig{},
 			},
 		},
 		{
@@ -115,6 +117,13 @@ func TestLedgerConfig(t *testing.T) {
 				"ledger.pvtdataStore.deprioritizedDataReconcilerInterval": "180m",
 				"ledger.history.enableHistoryDatabase":                    true,
 				"ledger.snapshots.rootDir":                                "/peerfs/customLocationForsnapshots",
+				"ledger.crdt.mergeWorkers":                                4,
+				"ledger.crdt.enableTestResolvers":                         true,
+				"ledger.crdt.valuesRetention":                             1000,
+				"ledger.crdt.maxValueSize":                                1048576,
+				"ledger.crdt.maxMergeSteps":                               100000,
+				"ledger.crdt.maxBlockDiffSize":                            10485760,
+				"ledger.crdt.slowMergeWarning":                            "5s",
 			},
 			expected: &ledger.Config{
 				RootFSPath: "/peerfs/ledgersData",
@@ -146,6 +155,15 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/customLocationForsnapshots",
 				},
+				CRDTConfig: &ledger.CRDTConfig{
+					MergeWorkers:        4,
+					EnableTestResolvers: true,
+					ValuesRetention:     1000,
+					MaxValueSize:        1048576,
+					MaxMergeSteps:       100000,
+					MaxBlockDiffSize:    10485760,
+					SlowMergeWarning:    5 * time.Second,
+				},
 			},
 		},
 	}
@@ -60,6 +60,7 @@ import (
 	"github.com/hyperledger/fabric/core/dispatcher"
 	"github.com/hyperledger/fabric/core/endorser"
 	authHandler "github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/core/handlers/crdt"
 	endorsement2 "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	endorsement3 "github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
 	"github.com/hyperledger/fabric/core/handlers/library"
@@ -422,6 +423,13 @@ func serve(args []string) error {
 		ebMetadataProvider,
 	)
 
+	libConf, err := library.LoadConfig()
+	if err != nil {
+		return errors.WithMessage(err, "could not decode peer handlers configuration")
+	}
+
+	reg := library.InitRegistry(libConf)
+
 	txProcessors := map[cb.HeaderType]ledger.CustomTxProcessor{
 		cb.HeaderType_CONFIG: &peer.ConfigTxProcessor{},
 	}
@@ -438,6 +446,7 @@ func serve(args []string) error {
 			Config:                          ledgerConfig(),
 			HashProvider:                    factory.GetDefault(),
 			EbMetadataProvider:              ebMetadataProvider,
+			CRDTResolvers:                   reg.Lookup(library.CRDTResolution).(map[string]crdt.Resolver),
 		},
 	)
 
@@ -707,13 +716,6 @@ func serve(args []string) error {
 
 	logger.Debugf("Running peer")
 
-	libConf, err := library.LoadConfig()
-	if err != nil {
-		return errors.WithMessage(err, "could not decode peer handlers configuration")
-	}
-
-	reg := library.InitRegistry(libConf)
-
 	authFilters := reg.Lookup(library.Auth).([]authHandler.Filter)
 	endorserSupport := &endorser.SupportImpl{
 		SignerSerializer: signingIdentity,
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
@@ -512,7 +513,8 @@ func (gs *Server) CommitStatus(ctx context.Context, signedRequest *gp.SignedComm
 // for a specific block. The streamed responses are ordered by ascending block number. Responses are only returned for
 // blocks that contain the requested events, while blocks not containing any of the requested events are skipped. The
 // events within each response message are presented in the same order that the transactions that emitted them appear
-// within the block.
+// within the block. If requested, each response also contains the values of the CRDT keys of the chaincode merged
+// by the transactions that emitted the events, as they are once the block is committed.
 func (gs *Server) ChaincodeEvents(signedRequest *gp.SignedChaincodeEventsRequest, stream gp.Gateway_ChaincodeEventsServer) error {
 	if len(signedRequest.GetRequest()) == 0 {
 		return status.Error(codes.InvalidArgument, "a chaincode events request is required")
@@ -571,6 +573,14 @@ func (gs *Server) ChaincodeEvents(signedRequest *gp.SignedChaincodeEventsRequest
 
 		response.Events = matchingEvents
 
+		if request.GetIncludeCrdtValues() {
+			crdtValues, err := chaincodeCRDTValues(ledger, response.GetBlockNumber(), request.GetChaincodeId(), matchingEvents)
+			if err != nil {
+				return status.Error(codes.Aborted, err.Error())
+			}
+			response.CrdtValues = crdtValues
+		}
+
 		if err := stream.Send(response); err != nil {
 			if err == io.EOF {
 				// Stream closed by the client
@@ -605,6 +615,43 @@ func chaincodeEventMatcher(request *gp.ChaincodeEventsRequest) func(event *peer.
 	}
 }
 
+// chaincodeCRDTValues returns the values of the CRDT keys of the chaincode merged by the transactions that emitted
+// the events, as they are right after each transaction, in the order that the transactions appear within the block
+func chaincodeCRDTValues(ledger ledger.Ledger, blockNumber uint64, chaincodeID string, events []*peer.ChaincodeEvent) ([]*peer.TxCRDTValues, error) {
+	blockValues, err := ledger.GetCRDTValuesByNum(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionIDs := make(map[string]struct{})
+	for _, event := range events {
+		transactionIDs[event.GetTxId()] = struct{}{}
+	}
+
+	var seqs []uint64
+	for seq, txValues := range blockValues {
+		if _, ok := transactionIDs[txValues.GetTxId()]; ok {
+			seqs = append(seqs, seq)
+		}
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	var results []*peer.TxCRDTValues
+	for _, seq := range seqs {
+		txValues := blockValues[seq]
+		var values []*peer.CRDTValue
+		for _, value := range txValues.GetValues() {
+			if value.GetNamespace() == chaincodeID {
+				values = append(values, value)
+			}
+		}
+		if len(values) > 0 {
+			results = append(results, &peer.TxCRDTValues{TxId: txValues.GetTxId(), Values: values})
+		}
+	}
+	return results, nil
+}
+
 func chaincodeEventsStartBlock(ledger ledger.Ledger, request *gp.ChaincodeEventsRequest) (uint64, error) {
 	afterTransactionID := request.GetAfterTransactionId()
 	if len(afterTransactionID) > 0 {
@@ -149,6 +149,7 @@ type testDef struct {
 	blocks             []*cp.Block
 	startPosition      *ab.SeekPosition
 	afterTxID          string
+	includeCRDTValues  bool
 }
 
 type preparedTest struct {
@@ -1688,6 +1689,76 @@ func TestChaincodeEvents(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "returns CRDT values of the transactions that emitted the events",
+			blocks: []*cp.Block{
+				matchingEventBlock,
+			},
+			includeCRDTValues: true,
+			postSetup: func(t *testing.T, test *preparedTest) {
+				test.ledger.GetCRDTValuesByNumReturns(map[uint64]*peer.TxCRDTValues{
+					1: {
+						TxId:   wrongChaincodeEvent.GetTxId(),
+						Values: []*peer.CRDTValue{{Namespace: "WRONG_CHAINCODE", Key: "CRDTFIELD_KEY", Value: []byte("1")}},
+					},
+					2: {
+						TxId: matchEvent.GetTxId(),
+						Values: []*peer.CRDTValue{
+							{Namespace: testChaincode, Key: "CRDTFIELD_KEY", Value: []byte("5")},
+							{Namespace: "OTHER_CHAINCODE", Key: "CRDTFIELD_KEY", Value: []byte("7")},
+							{Namespace: testChaincode, Key: "CRDTFIELD_DELETED", IsDelete: true},
+						},
+					},
+				}, nil)
+			},
+			expectedResponses: []proto.Message{
+				&pb.ChaincodeEventsResponse{
+					BlockNumber: matchingEventBlock.GetHeader().GetNumber(),
+					Events: []*peer.ChaincodeEvent{
+						{
+							ChaincodeId: testChaincode,
+							TxId:        matchEvent.GetTxId(),
+							EventName:   matchEvent.GetEventName(),
+							Payload:     matchEvent.GetPayload(),
+						},
+					},
+					CrdtValues: []*peer.TxCRDTValues{
+						{
+							TxId: matchEvent.GetTxId(),
+							Values: []*peer.CRDTValue{
+								{Namespace: testChaincode, Key: "CRDTFIELD_KEY", Value: []byte("5")},
+								{Namespace: testChaincode, Key: "CRDTFIELD_DELETED", IsDelete: true},
+							},
+						},
+					},
+				},
+			},
+			postTest: func(t *testing.T, test *preparedTest) {
+				require.Equal(t, 1, test.ledger.GetCRDTValuesByNumCallCount())
+				require.EqualValues(t, 101, test.ledger.GetCRDTValuesByNumArgsForCall(0))
+			},
+		},
+		{
+			name: "does not read CRDT values unless requested",
+			blocks: []*cp.Block{
+				matchingEventBlock,
+			},
+			postTest: func(t *testing.T, test *preparedTest) {
+				require.Equal(t, 0, test.ledger.GetCRDTValuesByNumCallCount())
+			},
+		},
+		{
+			name: "error reading CRDT values",
+			blocks: []*cp.Block{
+				matchingEventBlock,
+			},
+			includeCRDTValues: true,
+			postSetup: func(t *testing.T, test *preparedTest) {
+				test.ledger.GetCRDTValuesByNumReturns(nil, errors.New("CRDT_VALUES_ERROR"))
+			},
+			errCode:   codes.Aborted,
+			errString: "CRDT_VALUES_ERROR",
+		},
 		{
 			name: "passes channel name to ledger provider",
 			postTest: func(t *testing.T, test *preparedTest) {
@@ -1922,6 +1993,7 @@ func TestChaincodeEvents(t *testing.T) {
 			if len(tt.afterTxID) > 0 {
 				request.AfterTransactionId = tt.afterTxID
 			}
+			request.IncludeCrdtValues = tt.includeCRDTValues
 			requestBytes, err := proto.Marshal(request)
 			require.NoError(t, err)
 
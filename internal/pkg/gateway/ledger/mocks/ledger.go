@@ -63,6 +63,19 @@ type Ledger struct {
 		result1 ledgerb.ResultsIterator
 		result2 error
 	}
+	GetCRDTValuesByNumStub        func(uint64) (map[uint64]*peer.TxCRDTValues, error)
+	getCRDTValuesByNumMutex       sync.RWMutex
+	getCRDTValuesByNumArgsForCall []struct {
+		arg1 uint64
+	}
+	getCRDTValuesByNumReturns struct {
+		result1 map[uint64]*peer.TxCRDTValues
+		result2 error
+	}
+	getCRDTValuesByNumReturnsOnCall map[int]struct {
+		result1 map[uint64]*peer.TxCRDTValues
+		result2 error
+	}
 	GetTxValidationCodeByTxIDStub        func(string) (peer.TxValidationCode, uint64, error)
 	getTxValidationCodeByTxIDMutex       sync.RWMutex
 	getTxValidationCodeByTxIDArgsForCall []struct {
@@ -330,6 +343,70 @@ func (fake *Ledger) GetBlocksIteratorReturnsOnCall(i int, result1 ledgerb.Result
 	}{result1, result2}
 }
 
+func (fake *Ledger) GetCRDTValuesByNum(arg1 uint64) (map[uint64]*peer.TxCRDTValues, error) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	ret, specificReturn := fake.getCRDTValuesByNumReturnsOnCall[len(fake.getCRDTValuesByNumArgsForCall)]
+	fake.getCRDTValuesByNumArgsForCall = append(fake.getCRDTValuesByNumArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.GetCRDTValuesByNumStub
+	fakeReturns := fake.getCRDTValuesByNumReturns
+	fake.recordInvocation("GetCRDTValuesByNum", []interface{}{arg1})
+	fake.getCRDTValuesByNumMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Ledger) GetCRDTValuesByNumCallCount() int {
+	fake.getCRDTValuesByNumMutex.RLock()
+	defer fake.getCRDTValuesByNumMutex.RUnlock()
+	return len(fake.getCRDTValuesByNumArgsForCall)
+}
+
+func (fake *Ledger) GetCRDTValuesByNumCalls(stub func(uint64) (map[uint64]*peer.TxCRDTValues, error)) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	defer fake.getCRDTValuesByNumMutex.Unlock()
+	fake.GetCRDTValuesByNumStub = stub
+}
+
+func (fake *Ledger) GetCRDTValuesByNumArgsForCall(i int) uint64 {
+	fake.getCRDTValuesByNumMutex.RLock()
+	defer fake.getCRDTValuesByNumMutex.RUnlock()
+	argsForCall := fake.getCRDTValuesByNumArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Ledger) GetCRDTValuesByNumReturns(result1 map[uint64]*peer.TxCRDTValues, result2 error) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	defer fake.getCRDTValuesByNumMutex.Unlock()
+	fake.GetCRDTValuesByNumStub = nil
+	fake.getCRDTValuesByNumReturns = struct {
+		result1 map[uint64]*peer.TxCRDTValues
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Ledger) GetCRDTValuesByNumReturnsOnCall(i int, result1 map[uint64]*peer.TxCRDTValues, result2 error) {
+	fake.getCRDTValuesByNumMutex.Lock()
+	defer fake.getCRDTValuesByNumMutex.Unlock()
+	fake.GetCRDTValuesByNumStub = nil
+	if fake.getCRDTValuesByNumReturnsOnCall == nil {
+		fake.getCRDTValuesByNumReturnsOnCall = make(map[int]struct {
+			result1 map[uint64]*peer.TxCRDTValues
+			result2 error
+		})
+	}
+	fake.getCRDTValuesByNumReturnsOnCall[i] = struct {
+		result1 map[uint64]*peer.TxCRDTValues
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Ledger) GetTxValidationCodeByTxID(arg1 string) (peer.TxValidationCode, uint64, error) {
 	fake.getTxValidationCodeByTxIDMutex.Lock()
 	ret, specificReturn := fake.getTxValidationCodeByTxIDReturnsOnCall[len(fake.getTxValidationCodeByTxIDArgsForCall)]
@@ -408,6 +485,8 @@ func (fake *Ledger) Invocations() map[string][][]interface{} {
 	defer fake.getBlockchainInfoMutex.RUnlock()
 	fake.getBlocksIteratorMutex.RLock()
 	defer fake.getBlocksIteratorMutex.RUnlock()
+	fake.getCRDTValuesByNumMutex.RLock()
+	defer fake.getCRDTValuesByNumMutex.RUnlock()
 	fake.getTxValidationCodeByTxIDMutex.RLock()
 	defer fake.getTxValidationCodeByTxIDMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -20,6 +20,7 @@ type Ledger interface {
 	CommitNotificationsChannel(done <-chan struct{}) (<-chan *peerledger.CommitNotification, error)
 	GetBlockByTxID(txID string) (*common.Block, error)
 	GetBlockchainInfo() (*common.BlockchainInfo, error)
+	GetCRDTValuesByNum(blockNum uint64) (map[uint64]*peerproto.TxCRDTValues, error)
 	GetBlocksIterator(startBlockNumber uint64) (ledger.ResultsIterator, error)
 	GetTxValidationCodeByTxID(txID string) (peerproto.TxValidationCode, uint64, error)
 }